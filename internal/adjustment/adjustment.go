@@ -0,0 +1,113 @@
+// Package adjustment back-adjusts price history for corporate actions.
+//
+// Every action has an ex-date and factors that apply to the bars before it: prices
+// are multiplied by the price factor and share counts are divided by it for splits
+// and bonus shares, so the history lines up with the prices after the last action.
+package adjustment
+
+import (
+	"go-stock/internal/entity"
+	"time"
+)
+
+// Dividend types reported by IDX that change the number of shares by Ratio1 to
+// Ratio2. Other actions with a ratio are bonus shares: Ratio2 new shares for every
+// Ratio1 held.
+const (
+	TypeStockSplit   = "sp"
+	TypeReverseSplit = "rs"
+)
+
+type Action struct {
+	ExDate time.Time
+	// Price multiplies the prices before the ex-date.
+	Price float64
+	// Shares multiplies the share counts before the ex-date; it is 1 for a cash
+	// dividend, which does not change the number of shares.
+	Shares float64
+}
+
+// Actions derives the actions of the stored dividends. A cash dividend is
+// adjusted by its share of the close before the ex-date, which closeBefore looks
+// up; it returns 0 when that close is unknown and the dividend is then skipped.
+func Actions(dividends []entity.Dividend, closeBefore func(exDate time.Time) (float64, error)) ([]Action, error) {
+	var actions []Action
+	for _, dividend := range dividends {
+		if dividend.ExDate.IsZero() {
+			continue
+		}
+
+		if dividend.Ratio1 > 0 && dividend.Ratio2 > 0 {
+			ratio1, ratio2 := float64(dividend.Ratio1), float64(dividend.Ratio2)
+			shares := ratio2 / ratio1
+			if dividend.Type != TypeStockSplit && dividend.Type != TypeReverseSplit {
+				shares = (ratio1 + ratio2) / ratio1
+			}
+			actions = append(actions, Action{
+				ExDate: dividend.ExDate,
+				Price:  1 / shares,
+				Shares: shares,
+			})
+		}
+
+		if dividend.CashDividendPerShare > 0 {
+			previousClose, err := closeBefore(dividend.ExDate)
+			if err != nil {
+				return nil, err
+			}
+			if previousClose <= dividend.CashDividendPerShare {
+				continue
+			}
+			actions = append(actions, Action{
+				ExDate: dividend.ExDate,
+				Price:  1 - dividend.CashDividendPerShare/previousClose,
+				Shares: 1,
+			})
+		}
+	}
+	return actions, nil
+}
+
+// Apply returns a copy of the summaries with every action applied to the
+// summaries dated before its ex-date.
+func Apply(summaries []entity.StockSummary, actions []Action) []entity.StockSummary {
+	adjusted := make([]entity.StockSummary, len(summaries))
+	for i, summary := range summaries {
+		price, shares := 1.0, 1.0
+		for _, action := range actions {
+			if summary.Date.Before(action.ExDate) {
+				price *= action.Price
+				shares *= action.Shares
+			}
+		}
+		adjusted[i] = adjust(summary, price, shares)
+	}
+	return adjusted
+}
+
+func adjust(summary entity.StockSummary, price, shares float64) entity.StockSummary {
+	if price == 1 && shares == 1 {
+		return summary
+	}
+
+	summary.Previous *= price
+	summary.OpenPrice *= price
+	summary.FirstTrade *= price
+	summary.High *= price
+	summary.Low *= price
+	summary.Close *= price
+	summary.Change = summary.Close - summary.Previous
+	summary.Offer *= price
+	summary.Bid *= price
+
+	summary.Volume *= shares
+	summary.OfferVolume *= shares
+	summary.BidVolume *= shares
+	summary.NonRegularVolume *= shares
+	summary.ForeignBuy *= shares
+	summary.ForeignSell *= shares
+	summary.ListedShares *= shares
+	summary.TradebleShares *= shares
+	summary.DeriveMetrics()
+	return summary
+}
@@ -0,0 +1,82 @@
+package adjustment
+
+import (
+	"go-stock/internal/entity"
+	"math"
+	"testing"
+	"time"
+)
+
+func day(d int) time.Time {
+	return time.Date(2021, time.October, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestActions(t *testing.T) {
+	dividends := []entity.Dividend{
+		{Type: "sp", ExDate: day(12), Ratio1: 1, Ratio2: 5},
+		{Type: "sb", ExDate: day(13), Ratio1: 10, Ratio2: 1},
+		{Type: "dt", ExDate: day(14), CashDividendPerShare: 100},
+		{Type: "dt", CashDividendPerShare: 100},
+		{Type: "dt", ExDate: day(15), CashDividendPerShare: 100},
+	}
+	closes := map[time.Time]float64{day(14): 1000}
+	actions, err := Actions(dividends, func(exDate time.Time) (float64, error) {
+		return closes[exDate], nil
+	})
+	if err != nil {
+		t.Fatalf("Actions: %v", err)
+	}
+
+	want := []Action{
+		{ExDate: day(12), Price: 0.2, Shares: 5},
+		{ExDate: day(13), Price: 10.0 / 11, Shares: 1.1},
+		{ExDate: day(14), Price: 0.9, Shares: 1},
+	}
+	if len(actions) != len(want) {
+		t.Fatalf("got %d actions, want %d: %+v", len(actions), len(want), actions)
+	}
+	for i, action := range actions {
+		if !action.ExDate.Equal(want[i].ExDate) || math.Abs(action.Price-want[i].Price) > 1e-12 || math.Abs(action.Shares-want[i].Shares) > 1e-12 {
+			t.Errorf("action %d = %+v, want %+v", i, action, want[i])
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	summaries := []entity.StockSummary{
+		{Date: day(8), Previous: 34000, OpenPrice: 34000, High: 35000, Low: 33500, Close: 34500, Volume: 100, ListedShares: 1000},
+		{Date: day(11), Previous: 34500, OpenPrice: 34500, High: 36000, Low: 34000, Close: 35000, Volume: 200, ListedShares: 1000},
+		{Date: day(12), Previous: 7000, OpenPrice: 7000, High: 7200, Low: 6900, Close: 7100, Volume: 1000, ListedShares: 5000},
+	}
+	actions := []Action{
+		{ExDate: day(12), Price: 0.2, Shares: 5},
+		{ExDate: day(11), Price: 0.5, Shares: 1},
+	}
+
+	adjusted := Apply(summaries, actions)
+	if got := adjusted[0]; got.Close != 3450 || got.High != 3500 || got.Change != 50 || got.Volume != 500 || got.ListedShares != 5000 {
+		t.Errorf("before both actions = %+v", got)
+	}
+	if got := adjusted[1]; got.Close != 7000 || got.Previous != 6900 || got.Volume != 1000 {
+		t.Errorf("before the split = %+v", got)
+	}
+	if got := adjusted[2]; got != summaries[2] {
+		t.Errorf("on the ex-date = %+v, want it unchanged", got)
+	}
+	if summaries[0].Close != 34500 {
+		t.Errorf("Apply changed its input")
+	}
+}
+
+func TestApplyKeepsForeignNetValue(t *testing.T) {
+	summary := entity.StockSummary{Date: day(11), Previous: 34500, Close: 35000, Volume: 200, Value: 7000000, ForeignBuy: 120, ForeignSell: 40}
+	summary.DeriveMetrics()
+
+	adjusted := Apply([]entity.StockSummary{summary}, []Action{{ExDate: day(12), Price: 0.2, Shares: 5}})[0]
+	if adjusted.ForeignBuy != 600 || adjusted.ForeignSell != 200 {
+		t.Errorf("foreign flows = %v buy, %v sell; want 600 and 200", adjusted.ForeignBuy, adjusted.ForeignSell)
+	}
+	if adjusted.ForeignNetValue != summary.ForeignNetValue {
+		t.Errorf("ForeignNetValue = %v, want %v across the 1:5 split", adjusted.ForeignNetValue, summary.ForeignNetValue)
+	}
+}
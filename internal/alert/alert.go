@@ -0,0 +1,64 @@
+// Package alert evaluates the conditions of alert rules on daily summaries.
+package alert
+
+import (
+	"fmt"
+	"go-stock/internal/entity"
+)
+
+// DefaultPeriod is the number of days a value spike is averaged over.
+const DefaultPeriod = 20
+
+// Evaluate tells whether the condition of a rule holds on a summary and returns
+// the value compared with the threshold. history holds the summaries of the stock
+// before the day, latest first; only value spikes use it and they do not hold
+// until Period days are stored.
+func Evaluate(rule entity.AlertRule, summary entity.StockSummary, history []entity.StockSummary) (bool, float64, error) {
+	switch rule.Type {
+	case entity.AlertPriceAbove:
+		return summary.Close > 0 && summary.Close >= rule.Threshold, summary.Close, nil
+	case entity.AlertPriceBelow:
+		return summary.Close > 0 && summary.Close <= rule.Threshold, summary.Close, nil
+	case entity.AlertValueSpike:
+		period := Period(rule)
+		if len(history) < period {
+			return false, 0, nil
+		}
+		average := 0.0
+		for _, previous := range history[:period] {
+			average += previous.Value / float64(period)
+		}
+		if average <= 0 {
+			return false, 0, nil
+		}
+		ratio := summary.Value / average
+		return ratio >= rule.Threshold, ratio, nil
+	case entity.AlertForeignNetSell:
+		netSell := -summary.ForeignNetValue
+		return netSell >= rule.Threshold, netSell, nil
+	}
+	return false, 0, fmt.Errorf("unknown alert type %q", rule.Type)
+}
+
+// Period returns the days a value spike of the rule is averaged over.
+func Period(rule entity.AlertRule) int {
+	if rule.Period > 0 {
+		return rule.Period
+	}
+	return DefaultPeriod
+}
+
+// Message describes an alert of the rule for people.
+func Message(rule entity.AlertRule, observed float64) string {
+	switch rule.Type {
+	case entity.AlertPriceAbove:
+		return fmt.Sprintf("%s closed at %g, at or above %g", rule.StockCode, observed, rule.Threshold)
+	case entity.AlertPriceBelow:
+		return fmt.Sprintf("%s closed at %g, at or below %g", rule.StockCode, observed, rule.Threshold)
+	case entity.AlertValueSpike:
+		return fmt.Sprintf("%s traded %.1fx its %d-day average value", rule.StockCode, observed, Period(rule))
+	case entity.AlertForeignNetSell:
+		return fmt.Sprintf("%s foreign net sell of %.0f, at or above %g", rule.StockCode, observed, rule.Threshold)
+	}
+	return rule.Name
+}
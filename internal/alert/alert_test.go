@@ -0,0 +1,45 @@
+package alert
+
+import (
+	"go-stock/internal/entity"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	history := make([]entity.StockSummary, 20)
+	for i := range history {
+		history[i] = entity.StockSummary{Value: 100}
+	}
+	summary := entity.StockSummary{StockCode: "BBCA", Close: 10000, Value: 300, Volume: 10, ForeignBuy: 2, ForeignSell: 7}
+	summary.DeriveMetrics()
+
+	tests := []struct {
+		rule     entity.AlertRule
+		history  []entity.StockSummary
+		held     bool
+		observed float64
+	}{
+		{entity.AlertRule{Type: entity.AlertPriceAbove, Threshold: 10000}, nil, true, 10000},
+		{entity.AlertRule{Type: entity.AlertPriceAbove, Threshold: 10001}, nil, false, 10000},
+		{entity.AlertRule{Type: entity.AlertPriceBelow, Threshold: 9000}, nil, false, 10000},
+		{entity.AlertRule{Type: entity.AlertValueSpike, Threshold: 3}, history, true, 3},
+		{entity.AlertRule{Type: entity.AlertValueSpike, Threshold: 3}, history[:19], false, 0},
+		{entity.AlertRule{Type: entity.AlertValueSpike, Threshold: 3, Period: 5}, history[:5], true, 3},
+		// 5 shares net sold at the average price of 30.
+		{entity.AlertRule{Type: entity.AlertForeignNetSell, Threshold: 150}, nil, true, 150},
+		{entity.AlertRule{Type: entity.AlertForeignNetSell, Threshold: 151}, nil, false, 150},
+	}
+	for _, test := range tests {
+		held, observed, err := Evaluate(test.rule, summary, test.history)
+		if err != nil {
+			t.Fatalf("%s: %v", test.rule.Type, err)
+		}
+		if held != test.held || observed != test.observed {
+			t.Errorf("%s %v with %d days = %v, %v; want %v, %v", test.rule.Type, test.rule.Threshold, len(test.history), held, observed, test.held, test.observed)
+		}
+	}
+
+	if _, _, err := Evaluate(entity.AlertRule{Type: "volume"}, summary, nil); err == nil {
+		t.Error("Evaluate succeeded with an unknown type")
+	}
+}
@@ -1,15 +1,20 @@
 package app
 
 import (
+	"context"
 	"embed"
 	"fmt"
 	"github.com/go-playground/validator/v10"
+	"go-stock/internal/calendar"
 	"go-stock/internal/config"
 	"go-stock/internal/delivery/http/handler"
 	"go-stock/internal/infrastructure/idx"
 	"go-stock/internal/infrastructure/indopremier"
 	"go-stock/internal/infrastructure/mongo"
+	"go-stock/internal/infrastructure/webhook"
 	"go-stock/internal/repository"
+	"go-stock/internal/shared/cron"
+	"go-stock/internal/shared/rest"
 	"go-stock/internal/usecase"
 	"go-stock/internal/view"
 	"net/http"
@@ -29,6 +34,8 @@ type Infrastructure struct {
 	mongoClient       mongo.MongoClient
 	idxClient         idx.IdxClient
 	indopremierClient indopremier.IndopremierClient
+	webhookClient     webhook.WebhookClient
+	cronClient        cron.CronClient
 }
 
 type Repository struct {
@@ -36,23 +43,52 @@ type Repository struct {
 	StockSummaryRepository    repository.StockSummaryRepository
 	BrokerRepository          repository.BrokerRepository
 	FinancialReportRepository repository.FinancialReportRepository
+	JobRunRepository          repository.JobRunRepository
+	CheckpointRepository      repository.CheckpointRepository
+	BrokerSummaryRepository   repository.BrokerSummaryRepository
+	MarketBreadthRepository   repository.MarketBreadthRepository
+	BasketRepository          repository.BasketRepository
+	TradeRepository           repository.TradeRepository
+	WatchlistRepository       repository.WatchlistRepository
+	AlertRuleRepository       repository.AlertRuleRepository
+	AlertRepository           repository.AlertRepository
 }
 
 type Usecase struct {
 	StockUsecase           usecase.StockUseCase
 	StockSummaryUsecase    usecase.StockSummaryUseCase
+	CalendarUseCase        usecase.CalendarUseCase
+	IndicatorUseCase       usecase.IndicatorUseCase
+	ScreenerUseCase        usecase.ScreenerUseCase
+	MarketUseCase          usecase.MarketUseCase
+	BasketUseCase          usecase.BasketUseCase
+	PortfolioUseCase       usecase.PortfolioUseCase
+	WatchlistUseCase       usecase.WatchlistUseCase
+	BacktestUseCase        usecase.BacktestUseCase
+	AlertUseCase           usecase.AlertUseCase
 	BrokerUsecase          usecase.BrokerUseCase
 	FinancialReportUseCase usecase.FinancialReportUseCase
 	BrokerSummaryUseCase   usecase.BrokerSummaryUseCase
+	JobUsecase             usecase.JobUseCase
 }
 
 type Handler struct {
 	HealthHandler          handler.HealthHandler
 	StockHandler           handler.StockHandler
 	StockSummaryHandler    handler.StockSummaryHandler
+	CalendarHandler        handler.CalendarHandler
+	IndicatorHandler       handler.IndicatorHandler
+	ScreenerHandler        handler.ScreenerHandler
+	MarketHandler          handler.MarketHandler
+	BasketHandler          handler.BasketHandler
+	PortfolioHandler       handler.PortfolioHandler
+	WatchlistHandler       handler.WatchlistHandler
+	BacktestHandler        handler.BacktestHandler
+	AlertHandler           handler.AlertHandler
 	BrokerHandler          handler.BrokerHandler
 	BrokerSummaryHandler   handler.BrokerSummaryHandler
 	FinancialReportHandler handler.FinancialReportHandler
+	JobHandler             handler.JobHandler
 }
 
 type View struct {
@@ -106,6 +142,12 @@ func NewBootstrap(cfg config.Config, v embed.FS) (Bootstrap, error) {
 	idxClient := idx.NewIdxClient(idx.Config{
 		BaseURL: cfg.GetService().IDXService.BaseURL,
 		Delay:   time.Duration(cfg.GetService().IDXService.Delay) * time.Millisecond,
+		RateLimit: rest.RateLimit{
+			RequestsPerSecond: cfg.GetService().IDXService.RateLimit.RequestsPerSecond,
+			Burst:             cfg.GetService().IDXService.RateLimit.Burst,
+		},
+		Retry:          retryPolicy(cfg.GetService().IDXService.Retry),
+		CircuitBreaker: circuitBreaker(cfg.GetService().IDXService.CircuitBreaker),
 		Path: idx.Path{
 			StockList:        cfg.GetService().IDXService.Path.StockList,
 			StockSummaryList: cfg.GetService().IDXService.Path.StockSummaryList,
@@ -117,17 +159,76 @@ func NewBootstrap(cfg config.Config, v embed.FS) (Bootstrap, error) {
 
 	indopremierClient := indopremier.NewIndopremierClient(indopremier.Config{
 		BaseURL: cfg.GetService().IndoPremierService.BaseURL,
-		Delay:   time.Duration(cfg.GetService().IDXService.Delay) * time.Millisecond,
+		Delay:   time.Duration(cfg.GetService().IndoPremierService.Delay) * time.Millisecond,
+		RateLimit: rest.RateLimit{
+			RequestsPerSecond: cfg.GetService().IndoPremierService.RateLimit.RequestsPerSecond,
+			Burst:             cfg.GetService().IndoPremierService.RateLimit.Burst,
+		},
+		Retry:          retryPolicy(cfg.GetService().IndoPremierService.Retry),
+		CircuitBreaker: circuitBreaker(cfg.GetService().IndoPremierService.CircuitBreaker),
 		Path: indopremier.Path{
 			BrokerSummary: cfg.GetService().IndoPremierService.Path.BrokerSummary,
 		},
 	}, httpClient)
 
+	webhookClient := webhook.NewWebhookClient(webhook.Config{
+		Timeout: time.Duration(cfg.GetAlert().Timeout) * time.Millisecond,
+		Retry:   retryPolicy(cfg.GetAlert().Retry),
+	}, httpClient)
+
 	stockRepository := mongo.NewStockRepository(cfg, mongoClient, "stocks")
-	stockUsecase := usecase.NewStockUsecase(cfg, idxClient, stockRepository)
+	checkpointRepository := mongo.NewCheckpointRepository(cfg, mongoClient, "checkpoints")
+	stockUsecase := usecase.NewStockUsecase(cfg, idxClient, stockRepository, checkpointRepository)
 
 	stockSummaryRepository := mongo.NewStockSummaryRepository(cfg, mongoClient, "stock_summaries")
-	stockSummaryUsecase := usecase.NewStockSummaryUseCase(idxClient, stockSummaryRepository)
+	if err := ensureIndexes(stockSummaryRepository); err != nil {
+		return nil, fmt.Errorf("failed to create stock summary indexes: %w", err)
+	}
+	holidays, err := calendar.LoadHolidays(cfg.GetCalendar().HolidayFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load holidays: %w", err)
+	}
+	calendarUsecase := usecase.NewCalendarUseCase(cfg, holidays, stockSummaryRepository)
+	stockSummaryUsecase := usecase.NewStockSummaryUseCase(idxClient, calendarUsecase, stockSummaryRepository, stockRepository)
+	indicatorUsecase := usecase.NewIndicatorUseCase(stockSummaryRepository)
+	screenerUsecase := usecase.NewScreenerUseCase(stockSummaryRepository, stockRepository)
+	backtestUsecase := usecase.NewBacktestUseCase(stockSummaryRepository, stockRepository)
+
+	marketBreadthRepository := mongo.NewMarketBreadthRepository(cfg, mongoClient, "market_breadth")
+	if err := ensureIndexes(marketBreadthRepository); err != nil {
+		return nil, fmt.Errorf("failed to create market breadth indexes: %w", err)
+	}
+	marketUsecase := usecase.NewMarketUseCase(stockSummaryRepository, stockRepository, marketBreadthRepository)
+	stockSummaryUsecase.OnSummariesUpdated(func(ctx context.Context, date time.Time) error {
+		_, err := marketUsecase.UpdateBreadth(ctx, date)
+		return err
+	})
+
+	basketRepository := mongo.NewBasketRepository(cfg, mongoClient, "baskets")
+	basketUsecase := usecase.NewBasketUseCase(basketRepository, stockSummaryRepository, stockRepository)
+
+	tradeRepository := mongo.NewTradeRepository(cfg, mongoClient, "trades")
+	if err := ensureIndexes(tradeRepository); err != nil {
+		return nil, fmt.Errorf("failed to create trade indexes: %w", err)
+	}
+	portfolioUsecase := usecase.NewPortfolioUseCase(tradeRepository, stockSummaryRepository, stockRepository)
+
+	watchlistRepository := mongo.NewWatchlistRepository(cfg, mongoClient, "watchlists")
+	if err := ensureIndexes(watchlistRepository); err != nil {
+		return nil, fmt.Errorf("failed to create watchlist indexes: %w", err)
+	}
+	watchlistUsecase := usecase.NewWatchlistUseCase(watchlistRepository, stockSummaryRepository, stockRepository)
+
+	alertRuleRepository := mongo.NewAlertRuleRepository(cfg, mongoClient, "alert_rules")
+	alertRepository := mongo.NewAlertRepository(cfg, mongoClient, "alerts")
+	if err := ensureIndexes(alertRepository); err != nil {
+		return nil, fmt.Errorf("failed to create alert indexes: %w", err)
+	}
+	alertUsecase := usecase.NewAlertUseCase(cfg, webhookClient, alertRuleRepository, alertRepository, stockSummaryRepository, stockRepository)
+	stockSummaryUsecase.OnSummariesUpdated(func(ctx context.Context, date time.Time) error {
+		_, err := alertUsecase.EvaluateAlerts(ctx, date)
+		return err
+	})
 
 	brokerRepository := mongo.NewBrokerRepository(cfg, mongoClient, "brokers")
 	brokerUsecase := usecase.NewBrokerUseCase(idxClient, brokerRepository)
@@ -135,16 +236,34 @@ func NewBootstrap(cfg config.Config, v embed.FS) (Bootstrap, error) {
 	financialReportRepository := mongo.NewFinancialReportRepository(cfg, mongoClient, "financial_reports")
 	financialReportUsecase := usecase.NewFinancialReportUseCase(cfg, idxClient, financialReportRepository)
 
-	brokerSummaryUsecase := usecase.NewBrokerSummaryUseCase(indopremierClient)
+	brokerSummaryRepository := mongo.NewBrokerSummaryRepository(cfg, mongoClient, "broker_summaries")
+	if err := ensureIndexes(brokerSummaryRepository); err != nil {
+		return nil, fmt.Errorf("failed to create broker summary indexes: %w", err)
+	}
+	brokerSummaryUsecase := usecase.NewBrokerSummaryUseCase(cfg, indopremierClient, brokerSummaryRepository, stockRepository)
+
+	cronClient := cron.NewCronClient(cfg.GetApplication().Timezone)
+	jobRunRepository := mongo.NewJobRunRepository(cfg, mongoClient, "job_runs")
+	jobUsecase := usecase.NewJobUseCase(cfg, cronClient, jobRunRepository, calendarUsecase, stockUsecase, stockSummaryUsecase, marketUsecase, alertUsecase, brokerUsecase, financialReportUsecase, brokerSummaryUsecase)
 
 	validate := validator.New()
 
-	healthHandler := handler.NewHealthHandler()
+	healthHandler := handler.NewHealthHandler(rest.CircuitBreakers)
 	stockHandler := handler.NewStockHandler(stockUsecase, validate)
-	stockSummaryHandler := handler.NewStockSummaryHandler(stockSummaryUsecase, validate)
+	stockSummaryHandler := handler.NewStockSummaryHandler(stockSummaryUsecase, jobUsecase, validate)
+	calendarHandler := handler.NewCalendarHandler(calendarUsecase, validate)
+	indicatorHandler := handler.NewIndicatorHandler(indicatorUsecase, validate)
+	screenerHandler := handler.NewScreenerHandler(screenerUsecase, validate)
+	marketHandler := handler.NewMarketHandler(marketUsecase, validate)
+	basketHandler := handler.NewBasketHandler(basketUsecase, validate)
+	portfolioHandler := handler.NewPortfolioHandler(portfolioUsecase, validate)
+	watchlistHandler := handler.NewWatchlistHandler(watchlistUsecase, validate)
+	backtestHandler := handler.NewBacktestHandler(backtestUsecase, validate)
+	alertHandler := handler.NewAlertHandler(alertUsecase, validate)
 	brokerHandler := handler.NewBrokerHandler(brokerUsecase, validate)
 	brokerSummaryHandler := handler.NewBrokerSummaryHandler(brokerSummaryUsecase, validate)
 	financialReportHandler := handler.NewFinancialReportHandler(financialReportUsecase, validate)
+	jobHandler := handler.NewJobHandler(jobUsecase, validate)
 
 	viewService := view.New(v)
 	return &bootstrap{
@@ -153,30 +272,104 @@ func NewBootstrap(cfg config.Config, v embed.FS) (Bootstrap, error) {
 			mongoClient:       mongoClient,
 			idxClient:         idxClient,
 			indopremierClient: indopremierClient,
+			webhookClient:     webhookClient,
+			cronClient:        cronClient,
 		},
 		repository: Repository{
 			StockRepository:           stockRepository,
 			StockSummaryRepository:    stockSummaryRepository,
 			BrokerRepository:          brokerRepository,
 			FinancialReportRepository: financialReportRepository,
+			JobRunRepository:          jobRunRepository,
+			CheckpointRepository:      checkpointRepository,
+			BrokerSummaryRepository:   brokerSummaryRepository,
+			MarketBreadthRepository:   marketBreadthRepository,
+			BasketRepository:          basketRepository,
+			TradeRepository:           tradeRepository,
+			WatchlistRepository:       watchlistRepository,
+			AlertRuleRepository:       alertRuleRepository,
+			AlertRepository:           alertRepository,
 		},
 		usecase: Usecase{
 			StockUsecase:           stockUsecase,
 			StockSummaryUsecase:    stockSummaryUsecase,
+			CalendarUseCase:        calendarUsecase,
+			IndicatorUseCase:       indicatorUsecase,
+			ScreenerUseCase:        screenerUsecase,
+			MarketUseCase:          marketUsecase,
+			BasketUseCase:          basketUsecase,
+			PortfolioUseCase:       portfolioUsecase,
+			WatchlistUseCase:       watchlistUsecase,
+			BacktestUseCase:        backtestUsecase,
+			AlertUseCase:           alertUsecase,
 			BrokerUsecase:          brokerUsecase,
 			FinancialReportUseCase: financialReportUsecase,
 			BrokerSummaryUseCase:   brokerSummaryUsecase,
+			JobUsecase:             jobUsecase,
 		},
 		handler: Handler{
 			HealthHandler:          healthHandler,
 			StockHandler:           stockHandler,
 			StockSummaryHandler:    stockSummaryHandler,
+			CalendarHandler:        calendarHandler,
+			IndicatorHandler:       indicatorHandler,
+			ScreenerHandler:        screenerHandler,
+			MarketHandler:          marketHandler,
+			BasketHandler:          basketHandler,
+			PortfolioHandler:       portfolioHandler,
+			WatchlistHandler:       watchlistHandler,
+			BacktestHandler:        backtestHandler,
+			AlertHandler:           alertHandler,
 			BrokerHandler:          brokerHandler,
 			BrokerSummaryHandler:   brokerSummaryHandler,
 			FinancialReportHandler: financialReportHandler,
+			JobHandler:             jobHandler,
 		},
 		view: View{
 			ViewService: viewService,
 		},
 	}, nil
 }
+
+// retryPolicy converts the retry settings of a service, retrying throttling and
+// transient server errors when no status codes are configured.
+func retryPolicy(cfg config.Retry) rest.RetryPolicy {
+	statusCodes := cfg.RetryableStatusCodes
+	if len(statusCodes) == 0 {
+		statusCodes = rest.DefaultRetryableStatusCodes
+	}
+
+	return rest.RetryPolicy{
+		MaxAttempts:          cfg.MaxAttempts,
+		InitialBackoff:       time.Duration(cfg.InitialBackoff) * time.Millisecond,
+		MaxBackoff:           time.Duration(cfg.MaxBackoff) * time.Millisecond,
+		Jitter:               cfg.Jitter,
+		RetryableStatusCodes: statusCodes,
+	}
+}
+
+func circuitBreaker(cfg config.CircuitBreaker) rest.CircuitBreakerConfig {
+	return rest.CircuitBreakerConfig{
+		FailureThreshold:    cfg.FailureThreshold,
+		OpenTimeout:         time.Duration(cfg.OpenTimeout) * time.Millisecond,
+		HalfOpenMaxRequests: cfg.HalfOpenMaxRequests,
+		FailureStatusCodes:  cfg.FailureStatusCodes,
+	}
+}
+
+type indexer interface {
+	EnsureIndexes(ctx context.Context) error
+}
+
+// ensureIndexes creates the indexes of the repositories at startup.
+func ensureIndexes(repositories ...indexer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, repo := range repositories {
+		if err := repo.EnsureIndexes(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
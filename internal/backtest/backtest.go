@@ -0,0 +1,425 @@
+// Package backtest simulates rule-based strategies over daily bars.
+//
+// Signals are evaluated at the close of a day and filled at the open of the next
+// bar of the stock, so a strategy never trades on a price it could not have seen.
+// Orders are in whole lots at prices rounded to the IDX tick size, up for buys
+// and down for sells, and pay the fee of their side. Stop-losses and take-profits
+// are checked against the low and high of every day a position is held and fill
+// at their level, or at the open when the stock gaps through it; a day hitting
+// both is assumed to hit the stop first. Days without trades fill nothing.
+package backtest
+
+import (
+	"fmt"
+	"go-stock/internal/entity"
+	"go-stock/internal/indicator"
+	"go-stock/internal/marketrules"
+	"math"
+	"slices"
+	"sort"
+	"time"
+)
+
+// TradingDays annualizes the Sharpe ratio of the daily returns.
+const TradingDays = 252
+
+type Result struct {
+	// Trades are ordered by exit date; positions still open at the end are
+	// closed at their last close.
+	Trades  []entity.BacktestTrade
+	Equity  []entity.EquityPoint
+	Metrics entity.BacktestMetrics
+}
+
+type stock struct {
+	code   string
+	bars   []indicator.Bar
+	values map[string][]float64
+	entry  []bool
+	exit   []bool
+	// next is the bar of the current date, or of a later date.
+	next      int
+	lastClose float64
+	lastDate  time.Time
+
+	pendingEntry bool
+	pendingExit  bool
+	position     *position
+}
+
+type position struct {
+	trade  entity.BacktestTrade
+	stop   float64
+	target float64
+}
+
+func (s *stock) at(date time.Time) bool {
+	return s.next < len(s.bars) && s.bars[s.next].Date.Equal(date)
+}
+
+type simulation struct {
+	strategy Strategy
+	account  Account
+	sizing   Sizing
+	cash     float64
+	trades   []entity.BacktestTrade
+}
+
+// Run simulates the strategy over the bars of every stock, ordered by date, from
+// start. The bars before start only warm up the indicators. Without a sizing the
+// equity is split evenly between the stocks.
+func Run(strategy Strategy, account Account, bars map[string][]indicator.Bar, start time.Time) (Result, error) {
+	entry, exit, err := compile(strategy)
+	if err != nil {
+		return Result{}, err
+	}
+	if account.InitialCapital <= 0 {
+		return Result{}, fmt.Errorf("%w: initial capital must be positive", ErrInvalidStrategy)
+	}
+	if account.BuyFee < 0 || account.BuyFee >= 1 || account.SellFee < 0 || account.SellFee >= 1 {
+		return Result{}, fmt.Errorf("%w: fees must be fractions below 1", ErrInvalidStrategy)
+	}
+
+	sim := &simulation{strategy: strategy, account: account, sizing: strategy.Sizing, cash: account.InitialCapital}
+	if sim.sizing.Type == "" {
+		sim.sizing = Sizing{Type: SizingPercentEquity, Value: 100 / float64(max(len(bars), 1))}
+	}
+
+	codes := make([]string, 0, len(bars))
+	for code := range bars {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	var stocks []*stock
+	seen := make(map[time.Time]bool)
+	var dates []time.Time
+	for _, code := range codes {
+		s := &stock{code: code, bars: bars[code]}
+		if s.values, err = series(s.bars, slices.Concat(entry, exit)); err != nil {
+			return Result{}, err
+		}
+		s.entry = signals(s.values, len(s.bars), entry, true)
+		s.exit = signals(s.values, len(s.bars), exit, false)
+		for s.next < len(s.bars) && s.bars[s.next].Date.Before(start) {
+			s.next++
+		}
+		for _, bar := range s.bars[s.next:] {
+			if !seen[bar.Date] {
+				seen[bar.Date] = true
+				dates = append(dates, bar.Date)
+			}
+		}
+		stocks = append(stocks, s)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	var result Result
+	peak := account.InitialCapital
+	for i, date := range dates {
+		// Exits fill first so their cash is available to the entries.
+		for _, s := range stocks {
+			if s.at(date) && s.bars[s.next].Volume > 0 && s.position != nil && s.pendingExit {
+				sim.close(s, date, marketrules.RoundDown(s.bars[s.next].Open), entity.ExitSignal)
+			}
+		}
+		equity := sim.equity(stocks)
+		for _, s := range stocks {
+			if s.at(date) && s.bars[s.next].Volume > 0 && s.position == nil && s.pendingEntry && sim.canOpen(stocks) {
+				sim.open(s, date, equity)
+			}
+		}
+
+		for _, s := range stocks {
+			if !s.at(date) {
+				continue
+			}
+			bar := s.bars[s.next]
+			if s.position != nil && bar.Volume > 0 {
+				sim.checkLimits(s, date, bar)
+			}
+			// A day without trades leaves the orders for the next one.
+			if bar.Volume > 0 {
+				s.pendingEntry = s.position == nil && s.entry[s.next]
+				s.pendingExit = s.position != nil && s.exit[s.next]
+			}
+			s.lastClose, s.lastDate = bar.Close, bar.Date
+			s.next++
+		}
+
+		if i == len(dates)-1 {
+			for _, s := range stocks {
+				if s.position != nil {
+					sim.close(s, s.lastDate, s.lastClose, entity.ExitEnd)
+				}
+			}
+		}
+
+		point := entity.EquityPoint{Date: date, Cash: sim.cash, Equity: sim.equity(stocks)}
+		peak = math.Max(peak, point.Equity)
+		if peak > 0 {
+			point.Drawdown = (peak - point.Equity) / peak
+		}
+		result.Equity = append(result.Equity, point)
+	}
+
+	result.Trades = sim.trades
+	result.Metrics = metrics(account, result.Equity, result.Trades)
+	return result, nil
+}
+
+// equity returns the cash and the positions at their last close.
+func (sim *simulation) equity(stocks []*stock) float64 {
+	equity := sim.cash
+	for _, s := range stocks {
+		if s.position != nil {
+			equity += s.position.trade.Shares * s.lastClose
+		}
+	}
+	return equity
+}
+
+func (sim *simulation) canOpen(stocks []*stock) bool {
+	if sim.strategy.MaxPositions == 0 {
+		return true
+	}
+	held := 0
+	for _, s := range stocks {
+		if s.position != nil {
+			held++
+		}
+	}
+	return held < sim.strategy.MaxPositions
+}
+
+// open buys the lots the sizing allows at the open of the current bar, when the
+// cash buys at least one.
+func (sim *simulation) open(s *stock, date time.Time, equity float64) {
+	price := marketrules.RoundUp(s.bars[s.next].Open)
+	lotCost := price * marketrules.LotSize * (1 + sim.account.BuyFee)
+
+	var budget float64
+	switch sim.sizing.Type {
+	case SizingPercentEquity:
+		budget = equity * sim.sizing.Value / 100
+	case SizingAmount:
+		budget = sim.sizing.Value
+	case SizingLots:
+		budget = math.Floor(sim.sizing.Value) * lotCost
+	}
+	lots := int(math.Floor(math.Min(budget, sim.cash)/lotCost + 1e-9))
+	if lots < 1 {
+		return
+	}
+
+	shares := float64(lots * marketrules.LotSize)
+	gross := shares * price
+	fee := gross * sim.account.BuyFee
+	sim.cash -= gross + fee
+
+	s.position = &position{trade: entity.BacktestTrade{
+		StockCode:  s.code,
+		EntryDate:  date,
+		EntryPrice: price,
+		Lots:       lots,
+		Shares:     shares,
+		Cost:       gross + fee,
+		Fees:       fee,
+	}}
+	if sim.strategy.StopLoss > 0 {
+		s.position.stop = marketrules.RoundDown(price * (1 - sim.strategy.StopLoss))
+	}
+	if sim.strategy.TakeProfit > 0 {
+		s.position.target = marketrules.RoundUp(price * (1 + sim.strategy.TakeProfit))
+	}
+	s.pendingEntry = false
+}
+
+// checkLimits closes the position when the bar reaches its stop or its target.
+func (sim *simulation) checkLimits(s *stock, date time.Time, bar indicator.Bar) {
+	p := s.position
+	switch {
+	case p.stop > 0 && bar.Low <= p.stop:
+		sim.close(s, date, math.Min(p.stop, marketrules.RoundDown(bar.Open)), entity.ExitStopLoss)
+	case p.target > 0 && bar.High >= p.target:
+		sim.close(s, date, math.Max(p.target, marketrules.RoundDown(bar.Open)), entity.ExitTakeProfit)
+	}
+}
+
+func (sim *simulation) close(s *stock, date time.Time, price float64, reason string) {
+	trade := s.position.trade
+	gross := trade.Shares * price
+	fee := gross * sim.account.SellFee
+	sim.cash += gross - fee
+
+	trade.ExitDate = date
+	trade.ExitPrice = price
+	trade.ExitReason = reason
+	trade.Fees += fee
+	trade.PnL = gross - fee - trade.Cost
+	trade.Return = trade.PnL / trade.Cost
+	sim.trades = append(sim.trades, trade)
+
+	s.position = nil
+	s.pendingExit = false
+}
+
+// series computes the operands of the conditions over the bars, keyed by operand.
+// NaN marks a missing value.
+func series(bars []indicator.Bar, conditions []condition) (map[string][]float64, error) {
+	values := make(map[string][]float64)
+	for _, c := range conditions {
+		for _, o := range []*operand{&c.left, c.right} {
+			if o == nil || values[o.key] != nil {
+				continue
+			}
+			if o.spec == nil {
+				values[o.key] = field(bars, o.field)
+				continue
+			}
+			computed, err := indicator.Compute(bars, *o.spec)
+			if err != nil {
+				return nil, err
+			}
+			line := make([]float64, len(bars))
+			for i, value := range computed.Lines[o.line].Values {
+				line[i] = math.NaN()
+				if value != nil {
+					line[i] = *value
+				}
+			}
+			values[o.key] = line
+		}
+	}
+	return values, nil
+}
+
+func field(bars []indicator.Bar, name string) []float64 {
+	values := make([]float64, len(bars))
+	for i, bar := range bars {
+		switch name {
+		case "open":
+			values[i] = bar.Open
+		case "high":
+			values[i] = bar.High
+		case "low":
+			values[i] = bar.Low
+		case "close":
+			values[i] = bar.Close
+		case "volume":
+			values[i] = bar.Volume
+		case "value":
+			values[i] = bar.Value
+		}
+	}
+	return values
+}
+
+// signals tells for every bar whether all conditions hold, or any of them.
+func signals(values map[string][]float64, n int, conditions []condition, all bool) []bool {
+	result := make([]bool, n)
+	if len(conditions) == 0 {
+		return result
+	}
+	for i := range result {
+		result[i] = all
+		for _, c := range conditions {
+			if c.holds(values, i) != all {
+				result[i] = !all
+				break
+			}
+		}
+	}
+	return result
+}
+
+func (c condition) holds(values map[string][]float64, i int) bool {
+	left := values[c.left.key]
+	right := func(j int) float64 {
+		if c.right == nil {
+			return c.value
+		}
+		return values[c.right.key][j]
+	}
+
+	l, r := left[i], right(i)
+	if math.IsNaN(l) || math.IsNaN(r) {
+		return false
+	}
+	switch c.op {
+	case OpGt:
+		return l > r
+	case OpGte:
+		return l >= r
+	case OpLt:
+		return l < r
+	case OpLte:
+		return l <= r
+	}
+
+	if i == 0 {
+		return false
+	}
+	pl, pr := left[i-1], right(i-1)
+	if math.IsNaN(pl) || math.IsNaN(pr) {
+		return false
+	}
+	if c.op == OpCrossesAbove {
+		return pl <= pr && l > r
+	}
+	return pl >= pr && l < r
+}
+
+// metrics summarizes the equity curve and the trades. CAGR compounds over the
+// calendar days between the first and the last date.
+func metrics(account Account, equity []entity.EquityPoint, trades []entity.BacktestTrade) entity.BacktestMetrics {
+	result := entity.BacktestMetrics{
+		InitialCapital: account.InitialCapital,
+		FinalEquity:    account.InitialCapital,
+		Trades:         len(trades),
+	}
+	if len(equity) == 0 {
+		return result
+	}
+
+	result.FinalEquity = equity[len(equity)-1].Equity
+	result.TotalReturn = result.FinalEquity/account.InitialCapital - 1
+	years := equity[len(equity)-1].Date.Sub(equity[0].Date).Hours() / 24 / 365.25
+	if years > 0 && result.FinalEquity > 0 {
+		result.CAGR = math.Pow(result.FinalEquity/account.InitialCapital, 1/years) - 1
+	}
+
+	returns := make([]float64, 0, len(equity))
+	previous := account.InitialCapital
+	for _, point := range equity {
+		result.MaxDrawdown = math.Max(result.MaxDrawdown, point.Drawdown)
+		if previous > 0 {
+			returns = append(returns, point.Equity/previous-1)
+		}
+		previous = point.Equity
+	}
+	if len(returns) > 1 {
+		mean := 0.0
+		for _, r := range returns {
+			mean += r / float64(len(returns))
+		}
+		variance := 0.0
+		for _, r := range returns {
+			variance += (r - mean) * (r - mean) / float64(len(returns)-1)
+		}
+		if variance > 0 {
+			result.Sharpe = mean / math.Sqrt(variance) * math.Sqrt(TradingDays)
+		}
+	}
+
+	wins := 0
+	for _, trade := range trades {
+		if trade.PnL > 0 {
+			wins++
+		}
+	}
+	if len(trades) > 0 {
+		result.WinRate = float64(wins) / float64(len(trades))
+	}
+	return result
+}
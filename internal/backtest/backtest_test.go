@@ -0,0 +1,134 @@
+package backtest
+
+import (
+	"errors"
+	"go-stock/internal/entity"
+	"go-stock/internal/indicator"
+	"math"
+	"testing"
+	"time"
+)
+
+func day(n int) time.Time {
+	return time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, n)
+}
+
+// barsOf returns daily bars opening at the previous close.
+func barsOf(closes ...float64) []indicator.Bar {
+	bars := make([]indicator.Bar, len(closes))
+	for i, close := range closes {
+		open := close
+		if i > 0 {
+			open = closes[i-1]
+		}
+		bars[i] = indicator.Bar{
+			Date:   day(i),
+			Open:   open,
+			High:   math.Max(open, close),
+			Low:    math.Min(open, close),
+			Close:  close,
+			Volume: 1000,
+		}
+	}
+	return bars
+}
+
+func assertFloat(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-6 {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
+
+func TestRun(t *testing.T) {
+	strategy := Strategy{
+		Entry: []Condition{{Left: "close", Op: OpCrossesAbove, Right: "sma:2"}},
+		Exit:  []Condition{{Left: "close", Op: OpCrossesBelow, Right: "sma:2"}},
+	}
+	account := Account{InitialCapital: 1_000_000, BuyFee: 0.001, SellFee: 0.002}
+	bars := barsOf(1000, 1000, 1100, 1200, 1150, 1000, 1000)
+
+	result, err := Run(strategy, account, map[string][]indicator.Bar{"AAAA": bars}, day(1))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	// Crossing above on day 2, bought at the open of day 3; crossing below on day
+	// 4, sold at the open of day 5.
+	if len(result.Trades) != 1 {
+		t.Fatalf("trades = %+v, want 1", result.Trades)
+	}
+	trade := result.Trades[0]
+	if !trade.EntryDate.Equal(day(3)) || !trade.ExitDate.Equal(day(5)) || trade.ExitReason != entity.ExitSignal {
+		t.Errorf("trade = %+v", trade)
+	}
+	// 1,000,000 buys 9 lots at 1100 with the fee.
+	if trade.Lots != 9 || trade.EntryPrice != 1100 || trade.ExitPrice != 1150 {
+		t.Errorf("lots %d at %v, sold at %v", trade.Lots, trade.EntryPrice, trade.ExitPrice)
+	}
+	cost := 990000 * 1.001
+	proceeds := 1035000 * 0.998
+	assertFloat(t, "pnl", trade.PnL, proceeds-cost)
+	assertFloat(t, "final equity", result.Metrics.FinalEquity, 1_000_000-cost+proceeds)
+	assertFloat(t, "win rate", result.Metrics.WinRate, 1)
+
+	if len(result.Equity) != 6 || !result.Equity[0].Date.Equal(day(1)) {
+		t.Fatalf("equity = %+v, want the 6 days from the start", result.Equity)
+	}
+	// From the close of day 3 at 1200 to the sale net of its fee.
+	peak := 1_000_000 - cost + 900*1200
+	assertFloat(t, "max drawdown", result.Metrics.MaxDrawdown, (peak-result.Metrics.FinalEquity)/peak)
+	if result.Metrics.Sharpe <= 0 || result.Metrics.CAGR <= 0 {
+		t.Errorf("metrics = %+v", result.Metrics)
+	}
+}
+
+func TestRunStopLoss(t *testing.T) {
+	strategy := Strategy{
+		Entry:    []Condition{{Left: "close", Op: OpGt, Value: 0}},
+		StopLoss: 0.1,
+		Sizing:   Sizing{Type: SizingLots, Value: 2},
+	}
+	bars := barsOf(1000, 1000, 1000, 950)
+	bars[3].Low = 880
+	bars = append(bars, indicator.Bar{Date: day(4), Open: 800, High: 800, Low: 800, Close: 800, Volume: 1000})
+
+	result, err := Run(strategy, Account{InitialCapital: 1_000_000}, map[string][]indicator.Bar{"AAAA": bars}, day(0))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	// Bought 2 lots at the open of day 1, stopped at 900 on day 3, bought again at
+	// the open of day 4 and closed at the end.
+	if len(result.Trades) != 2 {
+		t.Fatalf("trades = %+v, want 2", result.Trades)
+	}
+	if stopped := result.Trades[0]; stopped.Lots != 2 || stopped.ExitReason != entity.ExitStopLoss || stopped.ExitPrice != 900 {
+		t.Errorf("stopped trade = %+v", stopped)
+	}
+	if last := result.Trades[1]; last.ExitReason != entity.ExitEnd || !last.EntryDate.Equal(day(4)) {
+		t.Errorf("last trade = %+v", last)
+	}
+	assertFloat(t, "final equity", result.Metrics.FinalEquity, 1_000_000-200*100)
+	assertFloat(t, "win rate", result.Metrics.WinRate, 0)
+}
+
+func TestLookback(t *testing.T) {
+	lookback, err := Lookback(Strategy{Entry: []Condition{{Left: "SMA:20", Op: OpGt, Right: "close"}}})
+	if err != nil || lookback != 20 {
+		t.Errorf("Lookback = %d, %v, want 20", lookback, err)
+	}
+
+	for _, strategy := range []Strategy{
+		{},
+		{Entry: []Condition{{Left: "close", Op: "above", Value: 1}}},
+		{Entry: []Condition{{Left: "price", Op: OpGt, Value: 1}}},
+		{Entry: []Condition{{Left: "macd.line", Op: OpGt, Value: 1}}},
+		{Entry: []Condition{{Left: "close", Op: OpGt, Value: 1}}, StopLoss: 1},
+		{Entry: []Condition{{Left: "close", Op: OpGt, Value: 1}}, Sizing: Sizing{Type: SizingPercentEquity, Value: 150}},
+	} {
+		if _, err := Lookback(strategy); !errors.Is(err, ErrInvalidStrategy) {
+			t.Errorf("Lookback(%+v) = %v, want ErrInvalidStrategy", strategy, err)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package backtest
+
+import (
+	"errors"
+	"fmt"
+	"go-stock/internal/indicator"
+	"math"
+	"slices"
+	"strings"
+)
+
+var ErrInvalidStrategy = errors.New("invalid strategy")
+
+// Operators of a condition. The crossings hold on the day the left operand moves
+// from at or below the right one to above it, or the other way around.
+const (
+	OpCrossesAbove = "crosses_above"
+	OpCrossesBelow = "crosses_below"
+	OpGt           = "gt"
+	OpGte          = "gte"
+	OpLt           = "lt"
+	OpLte          = "lte"
+)
+
+// Position sizing: a percentage of the equity, an amount of cash or a number of
+// lots per entry, always bounded by the cash available.
+const (
+	SizingPercentEquity = "percent_equity"
+	SizingAmount        = "amount"
+	SizingLots          = "lots"
+)
+
+// Fields of a bar a condition can read besides indicators.
+var barFields = []string{"open", "high", "low", "close", "volume", "value"}
+
+// Condition compares Left with Right, or with Value when Right is empty. Operands
+// are bar fields (close, volume, ...) or indicators written like the indicator
+// specs, e.g. "sma:50" or "macd:12:26:9.signal" for a line of an indicator with
+// several.
+type Condition struct {
+	Left  string
+	Op    string
+	Right string
+	Value float64
+}
+
+type Sizing struct {
+	Type  string
+	Value float64
+}
+
+// Strategy enters a stock when all its entry conditions hold and exits when any
+// exit condition holds, or when the price reaches the stop-loss or take-profit,
+// fractions below and above the entry price. Zero disables them.
+type Strategy struct {
+	Entry      []Condition
+	Exit       []Condition
+	StopLoss   float64
+	TakeProfit float64
+	Sizing     Sizing
+	// MaxPositions bounds the stocks held at once, zero leaves it unbounded.
+	MaxPositions int
+}
+
+// Defaults of an account: typical IDX broker fees, the sell fee including the
+// 0.1% sales tax.
+const (
+	DefaultInitialCapital = 100_000_000
+	DefaultBuyFee         = 0.0015
+	DefaultSellFee        = 0.0025
+)
+
+// Account is the starting cash and the broker fees, fractions of the value of a
+// buy or a sell including taxes.
+type Account struct {
+	InitialCapital float64
+	BuyFee         float64
+	SellFee        float64
+}
+
+// operand is a parsed operand: a bar field or a line of an indicator.
+type operand struct {
+	key   string
+	field string
+	spec  *indicator.Spec
+	line  int
+}
+
+type condition struct {
+	left  operand
+	op    string
+	right *operand
+	value float64
+}
+
+// Lookback validates the strategy and returns the bars to load before the start
+// date for its indicators to be stable there.
+func Lookback(strategy Strategy) (int, error) {
+	entry, exit, err := compile(strategy)
+	if err != nil {
+		return 0, err
+	}
+
+	var specs []indicator.Spec
+	for _, c := range slices.Concat(entry, exit) {
+		for _, o := range []*operand{&c.left, c.right} {
+			if o != nil && o.spec != nil {
+				specs = append(specs, *o.spec)
+			}
+		}
+	}
+	// One more bar so a crossing can be seen on the start date.
+	return indicator.Lookback(specs...) + 1, nil
+}
+
+func compile(strategy Strategy) ([]condition, []condition, error) {
+	if len(strategy.Entry) == 0 {
+		return nil, nil, fmt.Errorf("%w: no entry condition", ErrInvalidStrategy)
+	}
+	if strategy.StopLoss < 0 || strategy.StopLoss >= 1 {
+		return nil, nil, fmt.Errorf("%w: stop loss %v must be a fraction below 1", ErrInvalidStrategy, strategy.StopLoss)
+	}
+	if strategy.TakeProfit < 0 {
+		return nil, nil, fmt.Errorf("%w: take profit %v must not be negative", ErrInvalidStrategy, strategy.TakeProfit)
+	}
+	if strategy.MaxPositions < 0 {
+		return nil, nil, fmt.Errorf("%w: max positions must not be negative", ErrInvalidStrategy)
+	}
+	switch strategy.Sizing.Type {
+	case "":
+	case SizingPercentEquity, SizingAmount, SizingLots:
+		if strategy.Sizing.Value <= 0 || strategy.Sizing.Type == SizingPercentEquity && strategy.Sizing.Value > 100 {
+			return nil, nil, fmt.Errorf("%w: invalid %s sizing %v", ErrInvalidStrategy, strategy.Sizing.Type, strategy.Sizing.Value)
+		}
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown sizing %q", ErrInvalidStrategy, strategy.Sizing.Type)
+	}
+
+	entry, err := compileConditions(strategy.Entry)
+	if err != nil {
+		return nil, nil, err
+	}
+	exit, err := compileConditions(strategy.Exit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return entry, exit, nil
+}
+
+func compileConditions(conditions []Condition) ([]condition, error) {
+	compiled := make([]condition, 0, len(conditions))
+	for _, c := range conditions {
+		switch c.Op {
+		case OpCrossesAbove, OpCrossesBelow, OpGt, OpGte, OpLt, OpLte:
+		default:
+			return nil, fmt.Errorf("%w: unknown operator %q", ErrInvalidStrategy, c.Op)
+		}
+		if math.IsNaN(c.Value) || math.IsInf(c.Value, 0) {
+			return nil, fmt.Errorf("%w: invalid value %v", ErrInvalidStrategy, c.Value)
+		}
+
+		left, err := parseOperand(c.Left)
+		if err != nil {
+			return nil, err
+		}
+		result := condition{left: left, op: c.Op, value: c.Value}
+		if strings.TrimSpace(c.Right) != "" {
+			right, err := parseOperand(c.Right)
+			if err != nil {
+				return nil, err
+			}
+			result.right = &right
+		}
+		compiled = append(compiled, result)
+	}
+	return compiled, nil
+}
+
+// parseOperand resolves a bar field or an indicator line; indicator names are
+// normalized, e.g. "RSI" becomes "rsi:14.value".
+func parseOperand(name string) (operand, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if slices.Contains(barFields, name) {
+		return operand{key: name, field: name}, nil
+	}
+
+	specName, line, _ := strings.Cut(name, ".")
+	specs, err := indicator.ParseSpecs(specName)
+	if err != nil || len(specs) != 1 {
+		return operand{}, fmt.Errorf("%w: unknown operand %q", ErrInvalidStrategy, name)
+	}
+	spec := specs[0]
+
+	lines := indicator.Lines(spec)
+	if line == "" && len(lines) == 1 {
+		line = lines[0]
+	}
+	index := slices.Index(lines, line)
+	if index < 0 {
+		return operand{}, fmt.Errorf("%w: operand %q: %s has the lines %s", ErrInvalidStrategy, name, spec.Name, strings.Join(lines, ", "))
+	}
+	return operand{key: spec.String() + "." + line, spec: &spec, line: index}, nil
+}
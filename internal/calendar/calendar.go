@@ -0,0 +1,157 @@
+// Package calendar tells the trading days of IDX apart from the days it is
+// closed: weekends, the public holidays and collective leave days of a holiday
+// file, and weekdays without stored summaries amid stored sessions.
+package calendar
+
+import (
+	"fmt"
+	"go-stock/internal/entity"
+	"sort"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Calendar classifies days from the configured holidays and the dates summaries
+// are stored for. A stored session is trusted over the holiday file.
+type Calendar struct {
+	holidays map[time.Time]string
+	sessions map[time.Time]bool
+	first    time.Time
+	last     time.Time
+}
+
+// New returns the calendar of the holidays and the stored session dates.
+func New(holidays []entity.Holiday, sessions []time.Time) *Calendar {
+	c := &Calendar{
+		holidays: make(map[time.Time]string, len(holidays)),
+		sessions: make(map[time.Time]bool, len(sessions)),
+	}
+	for _, holiday := range holidays {
+		c.holidays[truncate(holiday.Date)] = holiday.Name
+	}
+	for _, session := range sessions {
+		day := truncate(session)
+		c.sessions[day] = true
+		if c.first.IsZero() || day.Before(c.first) {
+			c.first = day
+		}
+		if day.After(c.last) {
+			c.last = day
+		}
+	}
+	return c
+}
+
+// Day classifies a day. A weekday without summaries is inferred as closed when
+// sessions are stored before and after it, and expected to trade otherwise.
+func (c *Calendar) Day(date time.Time) entity.TradingDay {
+	day := entity.TradingDay{Date: truncate(date)}
+	name, holiday := c.holidays[day.Date]
+
+	switch {
+	case c.sessions[day.Date]:
+		day.Status = entity.TradingDayStatusSession
+	case day.Date.Weekday() == time.Saturday || day.Date.Weekday() == time.Sunday:
+		day.Status = entity.TradingDayStatusWeekend
+	case holiday:
+		day.Status = entity.TradingDayStatusHoliday
+		day.Holiday = name
+	case day.Date.After(c.first) && day.Date.Before(c.last):
+		day.Status = entity.TradingDayStatusClosed
+	default:
+		day.Status = entity.TradingDayStatusExpected
+	}
+	return day
+}
+
+// Days classifies every day between start and end, inclusive.
+func (c *Calendar) Days(start, end time.Time) []entity.TradingDay {
+	var days []entity.TradingDay
+	for day := truncate(start); !day.After(truncate(end)); day = day.AddDate(0, 0, 1) {
+		days = append(days, c.Day(day))
+	}
+	return days
+}
+
+// Next returns the first trading day after date.
+func (c *Calendar) Next(date time.Time) entity.TradingDay {
+	day := c.Day(date.AddDate(0, 0, 1))
+	for !day.Trading() {
+		day = c.Day(day.Date.AddDate(0, 0, 1))
+	}
+	return day
+}
+
+// Previous returns the last trading day before date.
+func (c *Calendar) Previous(date time.Time) entity.TradingDay {
+	day := c.Day(date.AddDate(0, 0, -1))
+	for !day.Trading() {
+		day = c.Day(day.Date.AddDate(0, 0, -1))
+	}
+	return day
+}
+
+// Holidays returns the configured holidays between start and end, by date.
+func (c *Calendar) Holidays(start, end time.Time) []entity.Holiday {
+	var holidays []entity.Holiday
+	for date, name := range c.holidays {
+		if !date.Before(truncate(start)) && !date.After(truncate(end)) {
+			holidays = append(holidays, entity.Holiday{Date: date, Name: name})
+		}
+	}
+	sort.Slice(holidays, func(i, j int) bool { return holidays[i].Date.Before(holidays[j].Date) })
+	return holidays
+}
+
+// LoadHolidays reads the holidays of a YAML or JSON file, by its extension:
+//
+//	holidays:
+//	  - date: "2025-03-31"
+//	    name: "Hari Raya Idul Fitri 1446 H"
+//
+// An empty path has no holidays.
+func LoadHolidays(path string) ([]entity.Holiday, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("read holiday file: %w", err)
+	}
+
+	// YAML decodes unquoted dates as times, quoted ones and JSON as strings.
+	var entries []struct {
+		Date any    `mapstructure:"date"`
+		Name string `mapstructure:"name"`
+	}
+	if err := v.UnmarshalKey("holidays", &entries); err != nil {
+		return nil, fmt.Errorf("parse holiday file: %w", err)
+	}
+
+	holidays := make([]entity.Holiday, 0, len(entries))
+	for _, entry := range entries {
+		var date time.Time
+		switch value := entry.Date.(type) {
+		case time.Time:
+			date = truncate(value)
+		case string:
+			parsed, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return nil, fmt.Errorf("holiday %q: invalid date %q: %w", entry.Name, value, err)
+			}
+			date = parsed
+		default:
+			return nil, fmt.Errorf("holiday %q: invalid date %v", entry.Name, entry.Date)
+		}
+		holidays = append(holidays, entity.Holiday{Date: date, Name: entry.Name})
+	}
+	return holidays, nil
+}
+
+// truncate drops the time of day, matching how summary dates are stored.
+func truncate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
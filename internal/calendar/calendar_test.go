@@ -0,0 +1,99 @@
+package calendar
+
+import (
+	"go-stock/internal/entity"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func date(value string) time.Time {
+	t, _ := time.Parse("2006-01-02", value)
+	return t
+}
+
+func TestDay(t *testing.T) {
+	holidays := []entity.Holiday{
+		{Date: date("2025-03-31"), Name: "Hari Raya Idul Fitri 1446 H"},
+		{Date: date("2025-04-01"), Name: "Hari Raya Idul Fitri 1446 H"},
+		{Date: date("2025-04-09"), Name: "Wrongly listed"},
+	}
+	sessions := []time.Time{date("2025-03-26"), date("2025-03-27"), date("2025-04-08"), date("2025-04-09")}
+	c := New(holidays, sessions)
+
+	for _, tc := range []struct {
+		date    string
+		status  entity.TradingDayStatus
+		holiday string
+	}{
+		{"2025-03-25", entity.TradingDayStatusExpected, ""},
+		{"2025-03-27", entity.TradingDayStatusSession, ""},
+		{"2025-03-28", entity.TradingDayStatusClosed, ""},
+		{"2025-03-29", entity.TradingDayStatusWeekend, ""},
+		{"2025-03-31", entity.TradingDayStatusHoliday, "Hari Raya Idul Fitri 1446 H"},
+		{"2025-04-09", entity.TradingDayStatusSession, ""},
+		{"2025-04-10", entity.TradingDayStatusExpected, ""},
+	} {
+		day := c.Day(date(tc.date))
+		if day.Status != tc.status || day.Holiday != tc.holiday {
+			t.Errorf("Day(%s) = %s %q, want %s %q", tc.date, day.Status, day.Holiday, tc.status, tc.holiday)
+		}
+	}
+}
+
+func TestNextAndPrevious(t *testing.T) {
+	holidays := []entity.Holiday{
+		{Date: date("2025-03-31"), Name: "Hari Raya Idul Fitri 1446 H"},
+		{Date: date("2025-04-01"), Name: "Hari Raya Idul Fitri 1446 H"},
+	}
+	c := New(holidays, []time.Time{date("2025-03-26"), date("2025-03-27")})
+
+	if next := c.Next(date("2025-03-28")); !next.Date.Equal(date("2025-04-02")) || next.Status != entity.TradingDayStatusExpected {
+		t.Errorf("Next = %s %s, want 2025-04-02 expected", next.Date.Format("2006-01-02"), next.Status)
+	}
+	// Friday the 28th has no session after it, so it is expected to trade.
+	if previous := c.Previous(date("2025-04-02")); !previous.Date.Equal(date("2025-03-28")) {
+		t.Errorf("Previous = %s, want 2025-03-28", previous.Date.Format("2006-01-02"))
+	}
+
+	c = New(holidays, []time.Time{date("2025-03-27"), date("2025-04-02")})
+	if previous := c.Previous(date("2025-04-02")); !previous.Date.Equal(date("2025-03-27")) {
+		t.Errorf("Previous = %s, want 2025-03-27 past the closed Friday", previous.Date.Format("2006-01-02"))
+	}
+	if next := c.Next(date("2025-03-27")); !next.Date.Equal(date("2025-04-02")) || next.Status != entity.TradingDayStatusSession {
+		t.Errorf("Next = %s %s, want 2025-04-02 session", next.Date.Format("2006-01-02"), next.Status)
+	}
+}
+
+func TestLoadHolidays(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holidays.yaml")
+	content := "holidays:\n  - date: 2025-12-25\n    name: Hari Raya Natal\n  - date: \"2025-01-01\"\n    name: Tahun Baru 2025 Masehi\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	holidays, err := LoadHolidays(path)
+	if err != nil {
+		t.Fatalf("LoadHolidays: %v", err)
+	}
+	if len(holidays) != 2 || !holidays[0].Date.Equal(date("2025-12-25")) || holidays[0].Name != "Hari Raya Natal" {
+		t.Fatalf("holidays = %+v", holidays)
+	}
+
+	c := New(holidays, nil)
+	if got := c.Holidays(date("2025-01-01"), date("2025-12-31")); len(got) != 2 || got[0].Name != "Tahun Baru 2025 Masehi" {
+		t.Errorf("Holidays = %+v, want both by date", got)
+	}
+
+	if holidays, err := LoadHolidays(""); err != nil || holidays != nil {
+		t.Errorf("LoadHolidays(\"\") = %v, %v, want no holidays", holidays, err)
+	}
+
+	if err := os.WriteFile(path, []byte("holidays:\n  - date: \"25/12/2025\"\n    name: Hari Raya Natal\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadHolidays(path); err == nil {
+		t.Error("LoadHolidays accepted an invalid date")
+	}
+}
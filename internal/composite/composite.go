@@ -0,0 +1,109 @@
+// Package composite computes the levels of an index over a basket of stocks.
+//
+// The index is chain-linked: the return of a day is the average return of the
+// constituents from their previous close, weighted at that close. A stock without
+// a summary on a day, e.g. while suspended, carries no weight on that day.
+package composite
+
+import (
+	"fmt"
+	"go-stock/internal/entity"
+	"sort"
+	"time"
+)
+
+type Result struct {
+	Levels        []entity.IndexLevel
+	Contributions []entity.IndexContribution
+}
+
+// Compute calculates the index over summaries ordered by date. The first date is
+// the base date, where the index stands at base; returns count from the next day.
+func Compute(summaries []entity.StockSummary, weighting string, base float64) (Result, error) {
+	if _, err := weight(entity.StockSummary{}, weighting); err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	contributions := make(map[string]*entity.IndexContribution)
+	level := base
+	for _, day := range byDate(summaries) {
+		if len(result.Levels) == 0 {
+			result.Levels = append(result.Levels, entity.IndexLevel{Date: day[0].Date, Level: base})
+			continue
+		}
+
+		weights := make([]float64, len(day))
+		total := 0.0
+		for i, summary := range day {
+			if summary.Previous <= 0 || summary.Close <= 0 {
+				continue
+			}
+			weights[i], _ = weight(summary, weighting)
+			total += weights[i]
+		}
+
+		dayReturn := 0.0
+		for i, summary := range day {
+			if weights[i] <= 0 {
+				continue
+			}
+			stockReturn := summary.Close/summary.Previous - 1
+			share := weights[i] / total
+			dayReturn += share * stockReturn
+
+			contribution, ok := contributions[summary.StockCode]
+			if !ok {
+				contribution = &entity.IndexContribution{StockCode: summary.StockCode, StockName: summary.StockName}
+				contributions[summary.StockCode] = contribution
+			}
+			// Scaled by the level before the day, so the contributions add up to
+			// the return of the index over the whole range.
+			contribution.Contribution += share * stockReturn * level / base
+			contribution.Return = (1+contribution.Return)*(1+stockReturn) - 1
+			contribution.Weight = share
+		}
+
+		level *= 1 + dayReturn
+		result.Levels = append(result.Levels, entity.IndexLevel{Date: day[0].Date, Level: level, Return: dayReturn})
+	}
+
+	for _, contribution := range contributions {
+		result.Contributions = append(result.Contributions, *contribution)
+	}
+	sort.Slice(result.Contributions, func(i, j int) bool {
+		a, b := result.Contributions[i], result.Contributions[j]
+		if a.Contribution != b.Contribution {
+			return a.Contribution > b.Contribution
+		}
+		return a.StockCode < b.StockCode
+	})
+	return result, nil
+}
+
+// weight returns the weight of a stock before normalizing over the constituents.
+func weight(summary entity.StockSummary, weighting string) (float64, error) {
+	switch weighting {
+	case entity.WeightingEqual:
+		return 1, nil
+	case entity.WeightingMarketCap:
+		return summary.ListedShares * summary.Previous, nil
+	case entity.WeightingFreeFloat:
+		return summary.TradebleShares * summary.Previous, nil
+	}
+	return 0, fmt.Errorf("unknown weighting %q", weighting)
+}
+
+// byDate splits summaries ordered by date into the summaries of each date.
+func byDate(summaries []entity.StockSummary) [][]entity.StockSummary {
+	var days [][]entity.StockSummary
+	var date time.Time
+	for i, summary := range summaries {
+		if i == 0 || !summary.Date.Equal(date) {
+			days = append(days, nil)
+			date = summary.Date
+		}
+		days[len(days)-1] = append(days[len(days)-1], summary)
+	}
+	return days
+}
@@ -0,0 +1,80 @@
+package composite
+
+import (
+	"go-stock/internal/entity"
+	"math"
+	"testing"
+	"time"
+)
+
+func day(n int) time.Time {
+	return time.Date(2025, time.January, n, 0, 0, 0, 0, time.UTC)
+}
+
+// basket: AAAA rises 10% then 10% again, BBBB falls 10% then stays flat.
+// BBBB has three times the shares of AAAA at a third of its price.
+var summaries = []entity.StockSummary{
+	{Date: day(1), StockCode: "AAAA", Previous: 100, Close: 100, ListedShares: 10, TradebleShares: 5},
+	{Date: day(1), StockCode: "BBBB", Previous: 100, Close: 100, ListedShares: 30, TradebleShares: 5},
+	{Date: day(2), StockCode: "AAAA", Previous: 100, Close: 110, ListedShares: 10, TradebleShares: 5},
+	{Date: day(2), StockCode: "BBBB", Previous: 100, Close: 90, ListedShares: 30, TradebleShares: 5},
+	{Date: day(3), StockCode: "AAAA", Previous: 110, Close: 121, ListedShares: 10, TradebleShares: 5},
+	{Date: day(3), StockCode: "BBBB", Previous: 90, Close: 90, ListedShares: 30, TradebleShares: 5},
+}
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		weighting string
+		levels    []float64
+	}{
+		// 0% on day 2, then AAAA's 10% at half the weight.
+		{entity.WeightingEqual, []float64{1000, 1000, 1050}},
+		// AAAA weighs 1/4 on day 2, then 1100/(1100+2700) on day 3.
+		{entity.WeightingMarketCap, []float64{1000, 950, 950 * (1 + 0.1*1100/3800)}},
+		// Same tradable shares, so weighted by price: 110/200 on day 3.
+		{entity.WeightingFreeFloat, []float64{1000, 1000, 1000 * (1 + 0.1*110/200)}},
+	}
+	for _, test := range tests {
+		result, err := Compute(summaries, test.weighting, 1000)
+		if err != nil {
+			t.Fatalf("%s: %v", test.weighting, err)
+		}
+		if len(result.Levels) != len(test.levels) {
+			t.Fatalf("%s: %d levels, want %d", test.weighting, len(result.Levels), len(test.levels))
+		}
+		for i, level := range result.Levels {
+			if math.Abs(level.Level-test.levels[i]) > 1e-9 {
+				t.Errorf("%s: level[%d] = %v, want %v", test.weighting, i, level.Level, test.levels[i])
+			}
+		}
+
+		// The contributions add up to the return of the index.
+		total := 0.0
+		for _, contribution := range result.Contributions {
+			total += contribution.Contribution
+		}
+		if want := test.levels[2]/1000 - 1; math.Abs(total-want) > 1e-9 {
+			t.Errorf("%s: contributions add up to %v, want %v", test.weighting, total, want)
+		}
+		if first := result.Contributions[0]; first.StockCode != "AAAA" || math.Abs(first.Return-0.21) > 1e-9 {
+			t.Errorf("%s: top contribution = %+v, want AAAA returning 21%%", test.weighting, first)
+		}
+	}
+}
+
+func TestComputeMissingDay(t *testing.T) {
+	// BBBB is suspended on day 2: AAAA carries the whole index.
+	result, err := Compute([]entity.StockSummary{summaries[0], summaries[1], summaries[2]}, entity.WeightingEqual, 100)
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+	if level := result.Levels[1].Level; math.Abs(level-110) > 1e-9 {
+		t.Errorf("level = %v, want 110", level)
+	}
+}
+
+func TestComputeUnknownWeighting(t *testing.T) {
+	if _, err := Compute(summaries, "price", 1000); err == nil {
+		t.Error("Compute succeeded with an unknown weighting")
+	}
+}
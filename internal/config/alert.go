@@ -0,0 +1,14 @@
+package config
+
+type Alert struct {
+	// Webhooks receive every alert that fires.
+	Webhooks []Webhook `mapstructure:"webhooks"`
+	Timeout  int       `mapstructure:"timeout"`
+	Retry    Retry     `mapstructure:"retry"`
+}
+
+type Webhook struct {
+	URL string `mapstructure:"url"`
+	// Secret signs the payloads; deliveries are unsigned without it.
+	Secret string `mapstructure:"secret"`
+}
@@ -1,9 +1,10 @@
 package config
 
 type Application struct {
-	Name     string `mapstructure:"name"`
-	Version  string `mapstructure:"version"`
-	Timezone string `mapstructure:"timezone"`
-	Host     string `mapstructure:"host"`
-	Port     int    `mapstructure:"port"`
+	Name       string `mapstructure:"name"`
+	Version    string `mapstructure:"version"`
+	Timezone   string `mapstructure:"timezone"`
+	Host       string `mapstructure:"host"`
+	Port       int    `mapstructure:"port"`
+	AdminToken string `mapstructure:"admin_token"`
 }
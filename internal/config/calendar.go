@@ -0,0 +1,7 @@
+package config
+
+type Calendar struct {
+	// HolidayFile lists the public holidays and collective leave days the exchange
+	// is closed on; only weekends are non-trading days without it.
+	HolidayFile string `mapstructure:"holiday_file"`
+}
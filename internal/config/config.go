@@ -9,6 +9,8 @@ type Config interface {
 	GetMongo() Mongo
 	GetService() Service
 	GetCronJob() CronJob
+	GetAlert() Alert
+	GetCalendar() Calendar
 }
 
 type config struct {
@@ -16,6 +18,8 @@ type config struct {
 	Mongo       Mongo       `mapstructure:"mongo"`
 	Service     Service     `mapstructure:"service"`
 	CronJob     CronJob     `mapstructure:"cron_job"`
+	Alert       Alert       `mapstructure:"alert"`
+	Calendar    Calendar    `mapstructure:"calendar"`
 }
 
 func (c *config) GetApplication() Application { return c.Application }
@@ -24,6 +28,8 @@ func (c *config) GetService() Service         { return c.Service }
 func (c *config) GetCronJob() CronJob {
 	return c.CronJob
 }
+func (c *config) GetAlert() Alert       { return c.Alert }
+func (c *config) GetCalendar() Calendar { return c.Calendar }
 
 func NewConfig(path string) (Config, error) {
 	v := viper.New()
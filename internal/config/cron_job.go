@@ -5,4 +5,5 @@ type CronJob struct {
 	UpdateStockSummaryList string `mapstructure:"update_stock_summary_list"`
 	UpdateBrokerList       string `mapstructure:"update_broker_list"`
 	UpdateFinancialReport  string `mapstructure:"update_financial_report"`
+	UpdateBrokerSummary    string `mapstructure:"update_broker_summary"`
 }
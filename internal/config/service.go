@@ -6,9 +6,14 @@ type Service struct {
 }
 
 type IDXService struct {
-	BaseURL string `mapstructure:"base_url"`
-	Delay   int    `mapstructure:"delay"`
-	Path    struct {
+	BaseURL          string         `mapstructure:"base_url"`
+	Delay            int            `mapstructure:"delay"`
+	RateLimit        RateLimit      `mapstructure:"rate_limit"`
+	ProfileWorkers   int            `mapstructure:"profile_workers"`
+	ProfileBatchSize int            `mapstructure:"profile_batch_size"`
+	Retry            Retry          `mapstructure:"retry"`
+	CircuitBreaker   CircuitBreaker `mapstructure:"circuit_breaker"`
+	Path             struct {
 		StockList        string `mapstructure:"stock_list"`
 		StockSummaryList string `mapstructure:"stock_summary_list"`
 		BrokerList       string `mapstructure:"broker_list"`
@@ -18,8 +23,33 @@ type IDXService struct {
 }
 
 type IndoPremierService struct {
-	BaseURL string `mapstructure:"base_url"`
-	Path    struct {
+	BaseURL               string         `mapstructure:"base_url"`
+	Delay                 int            `mapstructure:"delay"`
+	RateLimit             RateLimit      `mapstructure:"rate_limit"`
+	Retry                 Retry          `mapstructure:"retry"`
+	CircuitBreaker        CircuitBreaker `mapstructure:"circuit_breaker"`
+	BrokerSummaryCacheTTL int            `mapstructure:"broker_summary_cache_ttl"`
+	Path                  struct {
 		BrokerSummary string `mapstructure:"broker_summary"`
 	}
 }
+
+type Retry struct {
+	MaxAttempts          int     `mapstructure:"max_attempts"`
+	InitialBackoff       int     `mapstructure:"initial_backoff"`
+	MaxBackoff           int     `mapstructure:"max_backoff"`
+	Jitter               float64 `mapstructure:"jitter"`
+	RetryableStatusCodes []int   `mapstructure:"retryable_status_codes"`
+}
+
+type RateLimit struct {
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+	Burst             int     `mapstructure:"burst"`
+}
+
+type CircuitBreaker struct {
+	FailureThreshold    int   `mapstructure:"failure_threshold"`
+	OpenTimeout         int   `mapstructure:"open_timeout"`
+	HalfOpenMaxRequests int   `mapstructure:"half_open_max_requests"`
+	FailureStatusCodes  []int `mapstructure:"failure_status_codes"`
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"go-stock/internal/app"
+	"go-stock/internal/entity"
+	"log"
+	"time"
+)
+
+// Run executes the subcommand named by args[0] with the remaining args as flags.
+func Run(ctx context.Context, bootstrap app.Bootstrap, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no command given")
+	}
+
+	switch args[0] {
+	case "backfill":
+		return backfill(ctx, bootstrap, args[1:])
+	case "migrate-metrics":
+		return migrateMetrics(ctx, bootstrap, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// backfill ingests stock summaries for every missing trading day of a date range.
+//
+//	go-stock backfill -start 2025-01-01 -end 2025-01-31
+func backfill(ctx context.Context, bootstrap app.Bootstrap, args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	start := fs.String("start", "", "first date to backfill (YYYY-MM-DD)")
+	end := fs.String("end", time.Now().Format("2006-01-02"), "last date to backfill (YYYY-MM-DD)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	startDate, err := time.Parse("2006-01-02", *start)
+	if err != nil {
+		return fmt.Errorf("invalid -start %q: %w", *start, err)
+	}
+	endDate, err := time.Parse("2006-01-02", *end)
+	if err != nil {
+		return fmt.Errorf("invalid -end %q: %w", *end, err)
+	}
+
+	log.Printf("Backfilling stock summaries from %s to %s", *start, *end)
+	results, err := bootstrap.GetUsecase().StockSummaryUsecase.BackfillSummaries(ctx, startDate, endDate)
+
+	counts := make(map[entity.BackfillStatus]int)
+	for _, result := range results {
+		counts[result.Status]++
+		if result.Status == entity.BackfillStatusSkipped {
+			continue
+		}
+		fmt.Printf("%s\t%-7s\t%5d\t%s\n", result.Date.Format("2006-01-02"), result.Status, result.Records, result.Reason)
+	}
+	fmt.Printf("success=%d empty=%d skipped=%d failed=%d\n",
+		counts[entity.BackfillStatusSuccess],
+		counts[entity.BackfillStatusEmpty],
+		counts[entity.BackfillStatusSkipped],
+		counts[entity.BackfillStatusFailed],
+	)
+
+	if err != nil {
+		return err
+	}
+	if counts[entity.BackfillStatusFailed] > 0 {
+		return fmt.Errorf("%d day(s) failed", counts[entity.BackfillStatusFailed])
+	}
+	return nil
+}
+
+// migrateMetrics fills in the derived metrics of the stock summaries stored before
+// they were computed on ingestion.
+//
+//	go-stock migrate-metrics
+func migrateMetrics(ctx context.Context, bootstrap app.Bootstrap, args []string) error {
+	fs := flag.NewFlagSet("migrate-metrics", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	log.Printf("Migrating the derived metrics of stock summaries")
+	migrated, err := bootstrap.GetUsecase().StockSummaryUsecase.MigrateMetrics(ctx)
+	fmt.Printf("migrated=%d\n", migrated)
+	return err
+}
@@ -0,0 +1,313 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"go-stock/internal/entity"
+	"go-stock/internal/model"
+	"go-stock/internal/shared/response"
+	"go-stock/internal/usecase"
+	"net/http"
+)
+
+type AlertHandler interface {
+	ListRules(w http.ResponseWriter, r *http.Request)
+	CreateRule(w http.ResponseWriter, r *http.Request)
+	FindRule(w http.ResponseWriter, r *http.Request)
+	UpdateRule(w http.ResponseWriter, r *http.Request)
+	DeleteRule(w http.ResponseWriter, r *http.Request)
+	ListAlerts(w http.ResponseWriter, r *http.Request)
+}
+
+type alertHandler struct {
+	alertUseCase usecase.AlertUseCase
+	validate     *validator.Validate
+}
+
+func NewAlertHandler(alertUseCase usecase.AlertUseCase, validate *validator.Validate) AlertHandler {
+	return &alertHandler{
+		alertUseCase: alertUseCase,
+		validate:     validate,
+	}
+}
+
+// ListRules list alert rules
+// @Summary List alert rules
+// @Description List the alert rules ordered by stock and name.
+// @Tags Alerts
+// @Produce json
+// @Success 200 {array} model.AlertRuleResponse
+// @Failure 500 {object} response.Error
+// @Router /api/v1/alerts/rules [get]
+func (h *alertHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	rules, err := h.alertUseCase.FindRules(r.Context())
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	data := make([]model.AlertRuleResponse, 0, len(rules))
+	for _, rule := range rules {
+		data = append(data, toAlertRuleResponse(rule))
+	}
+
+	response.Success(w, data, "")
+	return
+}
+
+// CreateRule create alert rule
+// @Summary Create alert rule
+// @Description Create a rule evaluated after the summaries of every trading day are stored: the close at or above (price_above) or at or below (price_below) the threshold, the value traded at least threshold times its average over the previous period days (value_spike), or the foreign net sell value at or above the threshold (foreign_net_sell). The rule fires once when its condition starts to hold.
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param request body model.AlertRuleRequest true "alert rule"
+// @Success 201 {object} model.AlertRuleResponse
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/alerts/rules [post]
+func (h *alertHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	request, ok := h.alertRuleRequest(w, r)
+	if !ok {
+		return
+	}
+
+	rule, err := h.alertUseCase.CreateRule(r.Context(), toAlertRule(request))
+	if errors.Is(err, usecase.ErrInvalidAlertRule) {
+		response.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	response.Created(w, toAlertRuleResponse(*rule), "")
+	return
+}
+
+// FindRule find alert rule
+// @Summary Find alert rule
+// @Tags Alerts
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} model.AlertRuleResponse
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/alerts/rules/{id} [get]
+func (h *alertHandler) FindRule(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	rule, err := h.alertUseCase.FindRule(r.Context(), r.PathValue("id"))
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+	if rule == nil {
+		response.NotFound(w, "alert rule not found")
+		return
+	}
+
+	response.Success(w, toAlertRuleResponse(*rule), "")
+	return
+}
+
+// UpdateRule update alert rule
+// @Summary Update alert rule
+// @Description Replace the condition of a rule. The rule is rearmed, so it fires again when its new condition holds.
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param request body model.AlertRuleRequest true "alert rule"
+// @Success 200 {object} model.AlertRuleResponse
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/alerts/rules/{id} [put]
+func (h *alertHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	request, ok := h.alertRuleRequest(w, r)
+	if !ok {
+		return
+	}
+
+	update := toAlertRule(request)
+	update.ID = r.PathValue("id")
+	rule, err := h.alertUseCase.UpdateRule(r.Context(), update)
+	if errors.Is(err, usecase.ErrInvalidAlertRule) {
+		response.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+	if rule == nil {
+		response.NotFound(w, "alert rule not found")
+		return
+	}
+
+	response.Success(w, toAlertRuleResponse(*rule), "")
+	return
+}
+
+// DeleteRule delete alert rule
+// @Summary Delete alert rule
+// @Description Delete a rule. The alerts it fired are kept.
+// @Tags Alerts
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} response.Response
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/alerts/rules/{id} [delete]
+func (h *alertHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	deleted, err := h.alertUseCase.DeleteRule(r.Context(), r.PathValue("id"))
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+	if !deleted {
+		response.NotFound(w, "alert rule not found")
+		return
+	}
+
+	response.Success(w, nil, "alert rule deleted")
+	return
+}
+
+// ListAlerts list alerts
+// @Summary List alerts
+// @Description List the alerts fired, newest first, with their webhook delivery status.
+// @Tags Alerts
+// @Produce json
+// @Param rule_id query string false "Rule ID (default: every rule)"
+// @Param limit query int64 false "Number of alerts (default: 20, max: 100)" default(20) minimum(1) maximum(100)
+// @Success 200 {array} model.AlertResponse
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/alerts [get]
+func (h *alertHandler) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	request := model.AlertsRequest{
+		RuleID: r.URL.Query().Get("rule_id"),
+		Limit:  20,
+	}
+	if l := r.URL.Query().Get("limit"); l != "" {
+		fmt.Sscanf(l, "%d", &request.Limit)
+	}
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return
+		}
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	alerts, err := h.alertUseCase.FindAlerts(r.Context(), request.RuleID, request.Limit)
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	data := make([]model.AlertResponse, 0, len(alerts))
+	for _, alert := range alerts {
+		data = append(data, model.AlertResponse{
+			ID:            alert.ID,
+			RuleID:        alert.RuleID,
+			RuleName:      alert.RuleName,
+			StockCode:     alert.StockCode,
+			Type:          alert.Type,
+			Date:          alert.Date.Format("2006-01-02"),
+			Threshold:     alert.Threshold,
+			Observed:      alert.Observed,
+			Message:       alert.Message,
+			TriggeredAt:   alert.TriggeredAt,
+			Delivered:     alert.Delivered,
+			DeliveryError: alert.DeliveryError,
+		})
+	}
+
+	response.Success(w, data, "")
+	return
+}
+
+// alertRuleRequest decodes and validates the body of an alert rule and writes
+// the error response when it is invalid.
+func (h *alertHandler) alertRuleRequest(w http.ResponseWriter, r *http.Request) (model.AlertRuleRequest, bool) {
+	var request model.AlertRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		response.BadRequest(w, "invalid request body", nil)
+		return request, false
+	}
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return request, false
+		}
+		response.InternalError(w, err.Error())
+		return request, false
+	}
+	return request, true
+}
+
+// toAlertRule converts a request to a rule, enabled unless stated otherwise.
+func toAlertRule(request model.AlertRuleRequest) entity.AlertRule {
+	enabled := true
+	if request.Enabled != nil {
+		enabled = *request.Enabled
+	}
+	return entity.AlertRule{
+		Name:      request.Name,
+		StockCode: request.StockCode,
+		Type:      request.Type,
+		Threshold: request.Threshold,
+		Period:    request.Period,
+		Enabled:   enabled,
+	}
+}
+
+func toAlertRuleResponse(rule entity.AlertRule) model.AlertRuleResponse {
+	data := model.AlertRuleResponse{
+		ID:        rule.ID,
+		Name:      rule.Name,
+		StockCode: rule.StockCode,
+		Type:      rule.Type,
+		Threshold: rule.Threshold,
+		Period:    rule.Period,
+		Enabled:   rule.Enabled,
+		Triggered: rule.Triggered,
+		CreatedAt: rule.CreatedAt,
+		UpdatedAt: rule.UpdatedAt,
+	}
+	if !rule.LastEvaluated.IsZero() {
+		data.LastEvaluated = rule.LastEvaluated.Format("2006-01-02")
+	}
+	if !rule.LastTriggered.IsZero() {
+		data.LastTriggered = rule.LastTriggered.Format("2006-01-02")
+	}
+	return data
+}
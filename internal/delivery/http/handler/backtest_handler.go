@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"go-stock/internal/backtest"
+	"go-stock/internal/model"
+	"go-stock/internal/shared/response"
+	"go-stock/internal/usecase"
+	"net/http"
+	"time"
+)
+
+type BacktestHandler interface {
+	RunBacktest(w http.ResponseWriter, r *http.Request)
+}
+
+type backtestHandler struct {
+	backtestUseCase usecase.BacktestUseCase
+	validate        *validator.Validate
+}
+
+func NewBacktestHandler(backtestUseCase usecase.BacktestUseCase, validate *validator.Validate) BacktestHandler {
+	return &backtestHandler{
+		backtestUseCase: backtestUseCase,
+		validate:        validate,
+	}
+}
+
+// RunBacktest run backtest
+// @Summary Run backtest
+// @Description Simulate a strategy over the stored daily summaries of the stocks. Signals are evaluated at the close and filled at the next open in lots of 100 shares, at prices rounded to the IDX tick size and net of broker fees. Conditions compare bar fields (open, high, low, close, volume, value) and indicators (e.g. sma:50, rsi:14, macd.signal) with each other or with a value. Returns the trades, the daily equity and the CAGR, max drawdown, Sharpe ratio and win rate.
+// @Tags Backtests
+// @Accept json
+// @Produce json
+// @Param request body model.BacktestRequest true "stocks, range, strategy and account"
+// @Success 200 {object} model.BacktestResponse
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/backtests [post]
+func (h *backtestHandler) RunBacktest(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var request model.BacktestRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		response.BadRequest(w, "invalid request body", nil)
+		return
+	}
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return
+		}
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	startDate, _ := time.Parse("2006-01-02", request.StartDate)
+	endDate, _ := time.Parse("2006-01-02", request.EndDate)
+	if !startDate.IsZero() && !endDate.IsZero() && endDate.Before(startDate) {
+		response.BadRequest(w, "end_date must not be before start_date", nil)
+		return
+	}
+
+	strategy := backtest.Strategy{
+		StopLoss:     request.Strategy.StopLoss,
+		TakeProfit:   request.Strategy.TakeProfit,
+		MaxPositions: request.Strategy.MaxPositions,
+	}
+	for _, c := range request.Strategy.Entry {
+		strategy.Entry = append(strategy.Entry, backtest.Condition{Left: c.Left, Op: c.Op, Right: c.Right, Value: c.Value})
+	}
+	for _, c := range request.Strategy.Exit {
+		strategy.Exit = append(strategy.Exit, backtest.Condition{Left: c.Left, Op: c.Op, Right: c.Right, Value: c.Value})
+	}
+	if request.Strategy.Sizing != nil {
+		strategy.Sizing = backtest.Sizing{Type: request.Strategy.Sizing.Type, Value: request.Strategy.Sizing.Value}
+	}
+
+	account := backtest.Account{
+		InitialCapital: request.InitialCapital,
+		BuyFee:         backtest.DefaultBuyFee,
+		SellFee:        backtest.DefaultSellFee,
+	}
+	if account.InitialCapital == 0 {
+		account.InitialCapital = backtest.DefaultInitialCapital
+	}
+	if request.BuyFee != nil {
+		account.BuyFee = *request.BuyFee
+	}
+	if request.SellFee != nil {
+		account.SellFee = *request.SellFee
+	}
+
+	result, err := h.backtestUseCase.RunBacktest(r.Context(), request.StockCodes, startDate, endDate, strategy, account)
+	if errors.Is(err, usecase.ErrInvalidBacktest) {
+		response.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	data := model.BacktestResponse{
+		StockCodes: result.StockCodes,
+		StartDate:  result.StartDate.Format("2006-01-02"),
+		EndDate:    result.EndDate.Format("2006-01-02"),
+		Metrics: model.BacktestMetricsResponse{
+			InitialCapital: result.Metrics.InitialCapital,
+			FinalEquity:    result.Metrics.FinalEquity,
+			TotalReturnPct: result.Metrics.TotalReturn * 100,
+			CAGRPct:        result.Metrics.CAGR * 100,
+			MaxDrawdownPct: result.Metrics.MaxDrawdown * 100,
+			Sharpe:         result.Metrics.Sharpe,
+			Trades:         result.Metrics.Trades,
+			WinRatePct:     result.Metrics.WinRate * 100,
+		},
+		Trades: make([]model.BacktestTradeResponse, 0, len(result.Trades)),
+		Equity: make([]model.BacktestEquityResponse, 0, len(result.Equity)),
+	}
+	for _, trade := range result.Trades {
+		data.Trades = append(data.Trades, model.BacktestTradeResponse{
+			StockCode:  trade.StockCode,
+			EntryDate:  trade.EntryDate.Format("2006-01-02"),
+			EntryPrice: trade.EntryPrice,
+			ExitDate:   trade.ExitDate.Format("2006-01-02"),
+			ExitPrice:  trade.ExitPrice,
+			ExitReason: trade.ExitReason,
+			Lots:       trade.Lots,
+			Shares:     trade.Shares,
+			Cost:       trade.Cost,
+			Fees:       trade.Fees,
+			PnL:        trade.PnL,
+			ReturnPct:  trade.Return * 100,
+		})
+	}
+	for _, point := range result.Equity {
+		data.Equity = append(data.Equity, model.BacktestEquityResponse{
+			Date:        point.Date.Format("2006-01-02"),
+			Cash:        point.Cash,
+			Equity:      point.Equity,
+			DrawdownPct: point.Drawdown * 100,
+		})
+	}
+
+	response.Success(w, data, "")
+	return
+}
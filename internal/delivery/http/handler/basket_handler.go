@@ -0,0 +1,322 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"go-stock/internal/entity"
+	"go-stock/internal/model"
+	"go-stock/internal/shared/response"
+	"go-stock/internal/usecase"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type BasketHandler interface {
+	ListBaskets(w http.ResponseWriter, r *http.Request)
+	CreateBasket(w http.ResponseWriter, r *http.Request)
+	FindBasket(w http.ResponseWriter, r *http.Request)
+	UpdateBasket(w http.ResponseWriter, r *http.Request)
+	DeleteBasket(w http.ResponseWriter, r *http.Request)
+	FindIndex(w http.ResponseWriter, r *http.Request)
+}
+
+type basketHandler struct {
+	basketUseCase usecase.BasketUseCase
+	validate      *validator.Validate
+}
+
+func NewBasketHandler(basketUseCase usecase.BasketUseCase, validate *validator.Validate) BasketHandler {
+	return &basketHandler{
+		basketUseCase: basketUseCase,
+		validate:      validate,
+	}
+}
+
+// ListBaskets list baskets
+// @Summary List baskets
+// @Description List the baskets ordered by name.
+// @Tags Baskets
+// @Produce json
+// @Success 200 {array} model.BasketResponse
+// @Failure 500 {object} response.Error
+// @Router /api/v1/baskets [get]
+func (h *basketHandler) ListBaskets(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	baskets, err := h.basketUseCase.FindBaskets(r.Context())
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	data := make([]model.BasketResponse, 0, len(baskets))
+	for _, basket := range baskets {
+		data = append(data, toBasketResponse(basket))
+	}
+
+	response.Success(w, data, "")
+	return
+}
+
+// CreateBasket create basket
+// @Summary Create basket
+// @Description Create a named set of listed stocks tracked as an index, weighted equally, by market capitalization (listed shares) or by free float (tradable shares).
+// @Tags Baskets
+// @Accept json
+// @Produce json
+// @Param request body model.BasketRequest true "basket"
+// @Success 201 {object} model.BasketResponse
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/baskets [post]
+func (h *basketHandler) CreateBasket(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	request, ok := h.basketRequest(w, r)
+	if !ok {
+		return
+	}
+
+	basket, err := h.basketUseCase.CreateBasket(r.Context(), entity.Basket{
+		Name:       request.Name,
+		StockCodes: request.StockCodes,
+		Weighting:  request.Weighting,
+	})
+	if errors.Is(err, usecase.ErrInvalidBasket) {
+		response.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	response.Created(w, toBasketResponse(*basket), "")
+	return
+}
+
+// FindBasket find basket
+// @Summary Find basket
+// @Tags Baskets
+// @Produce json
+// @Param id path string true "Basket ID"
+// @Success 200 {object} model.BasketResponse
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/baskets/{id} [get]
+func (h *basketHandler) FindBasket(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	basket, err := h.basketUseCase.FindBasket(r.Context(), r.PathValue("id"))
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+	if basket == nil {
+		response.NotFound(w, "basket not found")
+		return
+	}
+
+	response.Success(w, toBasketResponse(*basket), "")
+	return
+}
+
+// UpdateBasket update basket
+// @Summary Update basket
+// @Description Replace the name, stocks and weighting of a basket.
+// @Tags Baskets
+// @Accept json
+// @Produce json
+// @Param id path string true "Basket ID"
+// @Param request body model.BasketRequest true "basket"
+// @Success 200 {object} model.BasketResponse
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/baskets/{id} [put]
+func (h *basketHandler) UpdateBasket(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	request, ok := h.basketRequest(w, r)
+	if !ok {
+		return
+	}
+
+	basket, err := h.basketUseCase.UpdateBasket(r.Context(), entity.Basket{
+		ID:         r.PathValue("id"),
+		Name:       request.Name,
+		StockCodes: request.StockCodes,
+		Weighting:  request.Weighting,
+	})
+	if errors.Is(err, usecase.ErrInvalidBasket) {
+		response.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+	if basket == nil {
+		response.NotFound(w, "basket not found")
+		return
+	}
+
+	response.Success(w, toBasketResponse(*basket), "")
+	return
+}
+
+// DeleteBasket delete basket
+// @Summary Delete basket
+// @Tags Baskets
+// @Produce json
+// @Param id path string true "Basket ID"
+// @Success 200 {object} response.Response
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/baskets/{id} [delete]
+func (h *basketHandler) DeleteBasket(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	deleted, err := h.basketUseCase.DeleteBasket(r.Context(), r.PathValue("id"))
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+	if !deleted {
+		response.NotFound(w, "basket not found")
+		return
+	}
+
+	response.Success(w, nil, "basket deleted")
+	return
+}
+
+// FindIndex find basket index
+// @Summary Find basket index
+// @Description Daily index levels of a basket computed from the stock summaries, chain-linked from the first trading day of the range at the base level, with the contribution of every stock to the return of the index.
+// @Tags Baskets
+// @Produce json
+// @Param id path string true "Basket ID"
+// @Param start_date query string false "Start date (YYYY-MM-DD, default: a year before the end date)"
+// @Param end_date query string false "End date (YYYY-MM-DD, default: the latest stored day)"
+// @Param base query number false "Level on the first day (default: 1000)" default(1000)
+// @Success 200 {object} model.BasketIndexResponse
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/baskets/{id}/index [get]
+func (h *basketHandler) FindIndex(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	query := r.URL.Query()
+
+	request := model.BasketIndexRequest{
+		StartDate: query.Get("start_date"),
+		EndDate:   query.Get("end_date"),
+		Base:      1000,
+	}
+	if value := query.Get("base"); value != "" {
+		base, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			response.BadRequest(w, "", []response.Error{{Field: "base", Message: "must be a number"}})
+			return
+		}
+		request.Base = base
+	}
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return
+		}
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	startDate, _ := time.Parse("2006-01-02", request.StartDate)
+	endDate, _ := time.Parse("2006-01-02", request.EndDate)
+	if !startDate.IsZero() && !endDate.IsZero() && endDate.Before(startDate) {
+		response.BadRequest(w, "end_date must not be before start_date", nil)
+		return
+	}
+
+	index, err := h.basketUseCase.FindIndex(r.Context(), r.PathValue("id"), startDate, endDate, request.Base)
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+	if index == nil {
+		response.NotFound(w, "basket not found")
+		return
+	}
+
+	data := model.BasketIndexResponse{
+		Basket:        toBasketResponse(index.Basket),
+		StartDate:     index.StartDate.Format("2006-01-02"),
+		EndDate:       index.EndDate.Format("2006-01-02"),
+		BaseLevel:     index.BaseLevel,
+		ReturnPct:     index.Return * 100,
+		Levels:        make([]model.BasketIndexLevelResponse, 0, len(index.Levels)),
+		Contributions: make([]model.BasketContributionResponse, 0, len(index.Contributions)),
+	}
+	for _, level := range index.Levels {
+		data.Levels = append(data.Levels, model.BasketIndexLevelResponse{
+			Date:      level.Date.Format("2006-01-02"),
+			Level:     level.Level,
+			ReturnPct: level.Return * 100,
+		})
+	}
+	for _, contribution := range index.Contributions {
+		data.Contributions = append(data.Contributions, model.BasketContributionResponse{
+			StockCode:       contribution.StockCode,
+			StockName:       contribution.StockName,
+			WeightPct:       contribution.Weight * 100,
+			ReturnPct:       contribution.Return * 100,
+			ContributionPct: contribution.Contribution * 100,
+		})
+	}
+
+	response.Success(w, data, "")
+	return
+}
+
+// basketRequest decodes and validates the body of a basket and writes the error
+// response when it is invalid.
+func (h *basketHandler) basketRequest(w http.ResponseWriter, r *http.Request) (model.BasketRequest, bool) {
+	var request model.BasketRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		response.BadRequest(w, "invalid request body", nil)
+		return request, false
+	}
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return request, false
+		}
+		response.InternalError(w, err.Error())
+		return request, false
+	}
+	return request, true
+}
+
+func toBasketResponse(basket entity.Basket) model.BasketResponse {
+	return model.BasketResponse{
+		ID:         basket.ID,
+		Name:       basket.Name,
+		StockCodes: basket.StockCodes,
+		Weighting:  basket.Weighting,
+		CreatedAt:  basket.CreatedAt,
+		UpdatedAt:  basket.UpdatedAt,
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/go-playground/validator/v10"
 	"go-stock/internal/model"
 	"go-stock/internal/shared/response"
+	"go-stock/internal/shared/rest"
 	"go-stock/internal/usecase"
 	"net/http"
 	"strings"
@@ -37,6 +38,7 @@ func NewBrokerSummaryHandler(brokerSummaryUsecase usecase.BrokerSummaryUseCase,
 // @Failure 400 {object} response.Error
 // @Failure 404 {object} response.Error
 // @Failure 500 {object} response.Error
+// @Failure 503 {object} response.Error
 // @Router /api/v1/brokers/summaries [get]
 func (h *brokerSummaryHandler) Find(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
@@ -79,6 +81,10 @@ func (h *brokerSummaryHandler) Find(w http.ResponseWriter, r *http.Request) {
 	}
 
 	result, err := h.brokerSummaryUsecase.Find(r.Context(), strings.ToUpper(request.StockCode), startDate, endDate, investorType, transactionType)
+	if errors.Is(err, rest.ErrUpstreamUnavailable) {
+		response.ServiceUnavailable(w, err.Error())
+		return
+	}
 	if err != nil {
 		response.InternalError(w, err.Error())
 		return
@@ -95,6 +101,7 @@ func (h *brokerSummaryHandler) Find(w http.ResponseWriter, r *http.Request) {
 			BrokerCode: buyer.BrokerCode,
 			Lot:        buyer.Lot,
 			Val:        buyer.Val,
+			ValAmount:  buyer.ValAmount,
 			Avg:        buyer.Avg,
 		})
 	}
@@ -105,6 +112,7 @@ func (h *brokerSummaryHandler) Find(w http.ResponseWriter, r *http.Request) {
 			BrokerCode: seller.BrokerCode,
 			Lot:        seller.Lot,
 			Val:        seller.Val,
+			ValAmount:  seller.ValAmount,
 			Avg:        seller.Avg,
 		})
 	}
@@ -116,10 +124,12 @@ func (h *brokerSummaryHandler) Find(w http.ResponseWriter, r *http.Request) {
 		Buyers:    buyers,
 		Sellers:   sellers,
 		Summary: model.Summary{
-			TotalVal:      result.Summary.TotalVal,
-			ForeignNetVal: result.Summary.ForeignNetVal,
-			TotalLot:      result.Summary.TotalLot,
-			Avg:           result.Summary.Avg,
+			TotalVal:            result.Summary.TotalVal,
+			TotalValAmount:      result.Summary.TotalValAmount,
+			ForeignNetVal:       result.Summary.ForeignNetVal,
+			ForeignNetValAmount: result.Summary.ForeignNetValAmount,
+			TotalLot:            result.Summary.TotalLot,
+			Avg:                 result.Summary.Avg,
 		},
 	}
 
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"go-stock/internal/entity"
+	"go-stock/internal/model"
+	"go-stock/internal/shared/response"
+	"go-stock/internal/usecase"
+	"net/http"
+	"time"
+)
+
+type CalendarHandler interface {
+	FindDays(w http.ResponseWriter, r *http.Request)
+	NextTradingDay(w http.ResponseWriter, r *http.Request)
+	PreviousTradingDay(w http.ResponseWriter, r *http.Request)
+}
+
+type calendarHandler struct {
+	calendarUseCase usecase.CalendarUseCase
+	validate        *validator.Validate
+}
+
+func NewCalendarHandler(calendarUseCase usecase.CalendarUseCase, validate *validator.Validate) CalendarHandler {
+	return &calendarHandler{
+		calendarUseCase: calendarUseCase,
+		validate:        validate,
+	}
+}
+
+// FindDays find trading calendar days
+// @Summary Find trading calendar days
+// @Description Classify every day of a range: a stored session, a weekday expected to trade, a weekend, a holiday of the holiday file or a weekday between stored sessions without summaries, inferred as closed. The range defaults to today and the 30 days after the start, and spans at most a year.
+// @Tags Calendar
+// @Produce json
+// @Param request query model.TradingDaysRequest false "query params"
+// @Success 200 {array} model.TradingDayResponse
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/calendar [get]
+func (h *calendarHandler) FindDays(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	request := model.TradingDaysRequest{
+		StartDate: r.URL.Query().Get("start_date"),
+		EndDate:   r.URL.Query().Get("end_date"),
+	}
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return
+		}
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	startDate, _ := time.Parse("2006-01-02", request.StartDate)
+	endDate, _ := time.Parse("2006-01-02", request.EndDate)
+	if !endDate.IsZero() {
+		if startDate.IsZero() {
+			response.BadRequest(w, "end_date requires start_date", nil)
+			return
+		}
+		if endDate.Before(startDate) {
+			response.BadRequest(w, "end_date must not be before start_date", nil)
+			return
+		}
+		if endDate.After(startDate.AddDate(1, 0, 0)) {
+			response.BadRequest(w, "the range must not exceed a year", nil)
+			return
+		}
+	}
+
+	result, err := h.calendarUseCase.FindDays(r.Context(), startDate, endDate)
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	data := make([]model.TradingDayResponse, 0, len(result))
+	for _, day := range result {
+		data = append(data, toTradingDayResponse(day))
+	}
+
+	response.Success(w, data, "")
+	return
+}
+
+// NextTradingDay find the next trading day
+// @Summary Find the next trading day
+// @Description The first day after a date, today by default, that is a stored session or a weekday expected to trade.
+// @Tags Calendar
+// @Produce json
+// @Param date query string false "Date (YYYY-MM-DD)"
+// @Success 200 {object} model.TradingDayResponse
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/calendar/next [get]
+func (h *calendarHandler) NextTradingDay(w http.ResponseWriter, r *http.Request) {
+	h.findTradingDay(w, r, h.calendarUseCase.NextTradingDay)
+}
+
+// PreviousTradingDay find the previous trading day
+// @Summary Find the previous trading day
+// @Description The last day before a date, today by default, that is a stored session or a weekday expected to trade, skipping the weekdays inferred as closed.
+// @Tags Calendar
+// @Produce json
+// @Param date query string false "Date (YYYY-MM-DD)"
+// @Success 200 {object} model.TradingDayResponse
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/calendar/previous [get]
+func (h *calendarHandler) PreviousTradingDay(w http.ResponseWriter, r *http.Request) {
+	h.findTradingDay(w, r, h.calendarUseCase.PreviousTradingDay)
+}
+
+func (h *calendarHandler) findTradingDay(w http.ResponseWriter, r *http.Request, find func(ctx context.Context, date time.Time) (*entity.TradingDay, error)) {
+	defer r.Body.Close()
+	request := model.TradingDayRequest{Date: r.URL.Query().Get("date")}
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return
+		}
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	date, _ := time.Parse("2006-01-02", request.Date)
+	result, err := find(r.Context(), date)
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	response.Success(w, toTradingDayResponse(*result), "")
+	return
+}
+
+func toTradingDayResponse(day entity.TradingDay) model.TradingDayResponse {
+	return model.TradingDayResponse{
+		Date:    day.Date.Format("2006-01-02"),
+		Status:  string(day.Status),
+		Trading: day.Trading(),
+		Holiday: day.Holiday,
+	}
+}
@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"go-stock/internal/model"
+	"go-stock/internal/shared/response"
+	"go-stock/internal/shared/rest"
 	"net/http"
 )
 
@@ -8,21 +11,41 @@ type HealthHandler interface {
 	Healthz(w http.ResponseWriter, r *http.Request)
 }
 type healthHandler struct {
+	circuitBreakers func() []rest.CircuitBreakerStatus
 }
 
-func NewHealthHandler() HealthHandler {
-	return &healthHandler{}
+func NewHealthHandler(circuitBreakers func() []rest.CircuitBreakerStatus) HealthHandler {
+	return &healthHandler{
+		circuitBreakers: circuitBreakers,
+	}
 }
 
 // Healthz is a health check endpoint
 // @Summary Health check
-// @Description Health check
+// @Description Health check. The service stays healthy while an upstream is down, its circuit breaker state is reported as "degraded".
 // @Tags Health
-// @Produce plain
-// @Success 200 {string} string "OK"
+// @Produce json
+// @Success 200 {object} model.HealthResponse
 // @Router /healthz [get]
 func (s *healthHandler) Healthz(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("OK"))
+
+	data := model.HealthResponse{
+		Status:    "ok",
+		Upstreams: make([]model.UpstreamResponse, 0),
+	}
+	for _, breaker := range s.circuitBreakers() {
+		if breaker.State != rest.CircuitClosed {
+			data.Status = "degraded"
+		}
+		data.Upstreams = append(data.Upstreams, model.UpstreamResponse{
+			Host:                breaker.Host,
+			State:               string(breaker.State),
+			ConsecutiveFailures: breaker.ConsecutiveFailures,
+			OpenedAt:            optionalTime(breaker.OpenedAt),
+			LastError:           breaker.LastError,
+		})
+	}
+
+	response.Success(w, data, "")
 }
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"go-stock/internal/indicator"
+	"go-stock/internal/model"
+	"go-stock/internal/shared/response"
+	"go-stock/internal/usecase"
+	"net/http"
+	"strings"
+)
+
+type IndicatorHandler interface {
+	FindIndicators(w http.ResponseWriter, r *http.Request)
+}
+
+type indicatorHandler struct {
+	indicatorUseCase usecase.IndicatorUseCase
+	validate         *validator.Validate
+}
+
+func NewIndicatorHandler(indicatorUseCase usecase.IndicatorUseCase, validate *validator.Validate) IndicatorHandler {
+	return &indicatorHandler{
+		indicatorUseCase: indicatorUseCase,
+		validate:         validate,
+	}
+}
+
+// FindIndicators find stock indicators
+// @Summary Find stock indicators
+// @Description Compute technical indicators (sma, ema, rsi, macd, bb, atr, vwap) over the stored stock summaries. Indicators are written as name:param:param, omitted parameters take their defaults.
+// @Tags Stock
+// @Produce json
+// @Param request query model.StockIndicatorRequest true "query params"
+// @Success 200 {object} model.StockIndicatorResponse
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/stock/indicators [get]
+func (h *indicatorHandler) FindIndicators(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	stockCode := r.URL.Query().Get("stock_code")
+	startDate := r.URL.Query().Get("start_date")
+	endDate := r.URL.Query().Get("end_date")
+	indicators := r.URL.Query().Get("indicators")
+
+	request := model.StockIndicatorRequest{
+		StockCode:  stockCode,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Indicators: indicators,
+	}
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return
+		}
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	specs, err := indicator.ParseSpecs(indicators)
+	if err != nil {
+		response.BadRequest(w, "", []response.Error{{
+			Field:   "Indicators",
+			Message: err.Error(),
+		}})
+		return
+	}
+
+	result, err := h.indicatorUseCase.FindIndicators(r.Context(), strings.ToUpper(stockCode), startDate, endDate, specs)
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+	if result == nil {
+		response.NotFound(w, "no stock summaries found")
+		return
+	}
+
+	data := model.StockIndicatorResponse{
+		StockCode:  result.StockCode,
+		Dates:      make([]string, 0, len(result.Dates)),
+		Close:      result.Close,
+		Indicators: make([]model.StockIndicatorSeries, 0, len(result.Series)),
+	}
+	for _, date := range result.Dates {
+		data.Dates = append(data.Dates, date.Format("2006-01-02"))
+	}
+	for _, series := range result.Series {
+		lines := make(map[string][]*float64, len(series.Lines))
+		for _, line := range series.Lines {
+			lines[line.Name] = line.Values
+		}
+		data.Indicators = append(data.Indicators, model.StockIndicatorSeries{
+			Name:  series.Name,
+			Lines: lines,
+		})
+	}
+
+	response.Success(w, data, "")
+	return
+}
@@ -0,0 +1,241 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"go-stock/internal/entity"
+	"go-stock/internal/model"
+	"go-stock/internal/shared/response"
+	"go-stock/internal/usecase"
+	"io"
+	"net/http"
+	"time"
+)
+
+type JobHandler interface {
+	ListJobs(w http.ResponseWriter, r *http.Request)
+	ListJobRuns(w http.ResponseWriter, r *http.Request)
+	TriggerJob(w http.ResponseWriter, r *http.Request)
+}
+
+type jobHandler struct {
+	jobUsecase usecase.JobUseCase
+	validate   *validator.Validate
+}
+
+func NewJobHandler(jobUsecase usecase.JobUseCase, validate *validator.Validate) JobHandler {
+	return &jobHandler{
+		jobUsecase: jobUsecase,
+		validate:   validate,
+	}
+}
+
+// ListJobs list jobs
+// @Summary List jobs
+// @Description List the background jobs with their schedule, next and previous run time and their last run
+// @Tags Job
+// @Produce json
+// @Success 200 {array} model.JobResponse
+// @Failure 500 {object} response.Error
+// @Router /api/v1/jobs [get]
+func (h *jobHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	jobs, err := h.jobUsecase.ListJobs(r.Context())
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	data := make([]model.JobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		data = append(data, toJobResponse(job))
+	}
+
+	response.Success(w, data, "")
+	return
+}
+
+// ListJobRuns list job runs
+// @Summary List job runs
+// @Description List the most recent runs of a job, newest first
+// @Tags Job
+// @Produce json
+// @Param name path string true "job name"
+// @Param limit query int64 false "Number of runs (default: 20, max: 100)" default(20) minimum(1) maximum(100)
+// @Success 200 {object} model.JobRunsResponse
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/jobs/{name}/runs [get]
+func (h *jobHandler) ListJobRuns(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	request := model.JobRunsRequest{
+		Name:  r.PathValue("name"),
+		Limit: 20,
+	}
+	if l := r.URL.Query().Get("limit"); l != "" {
+		fmt.Sscanf(l, "%d", &request.Limit)
+	}
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return
+		}
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	job, err := h.jobUsecase.FindJob(r.Context(), request.Name)
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	if job == nil {
+		response.NotFound(w, "")
+		return
+	}
+
+	runs, err := h.jobUsecase.FindRuns(r.Context(), request.Name, request.Limit)
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	data := model.JobRunsResponse{
+		Job:  toJobResponse(*job),
+		Runs: make([]model.JobRunResponse, 0, len(runs)),
+	}
+	for _, run := range runs {
+		data.Runs = append(data.Runs, toJobRunResponse(run))
+	}
+
+	response.Success(w, data, "")
+	return
+}
+
+// TriggerJob trigger a job run
+// @Summary Trigger a job run
+// @Description Start a run of the job in the background. Omitted parameters fall back to the values of a scheduled run.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "job name"
+// @Param request body model.JobTriggerRequest false "job parameters"
+// @Success 202 {object} model.JobRunResponse
+// @Failure 400 {object} response.Error
+// @Failure 401 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 409 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/admin/jobs/{name}/run [post]
+func (h *jobHandler) TriggerJob(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	if r.Method != http.MethodPost {
+		response.MethodNotAllowed(w, "")
+		return
+	}
+
+	// The body is optional, every parameter has a default.
+	var request model.JobTriggerRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		response.BadRequest(w, "invalid request body", nil)
+		return
+	}
+	request.Name = r.PathValue("name")
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return
+		}
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	run, err := h.jobUsecase.Trigger(r.Context(), request.Name, entity.JobParams{
+		Date:      request.Date,
+		Period:    request.Period,
+		Year:      request.Year,
+		StartDate: request.StartDate,
+		EndDate:   request.EndDate,
+	})
+	switch {
+	case errors.Is(err, usecase.ErrJobNotFound):
+		response.NotFound(w, err.Error())
+		return
+	case errors.Is(err, usecase.ErrJobRunning):
+		response.Conflict(w, err.Error())
+		return
+	case err != nil:
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	response.Accepted(w, toJobRunResponse(*run), "")
+	return
+}
+
+func toJobResponse(job entity.Job) model.JobResponse {
+	result := model.JobResponse{
+		Name:        job.Name,
+		Schedule:    job.Schedule,
+		NextRun:     optionalTime(job.NextRun),
+		PreviousRun: optionalTime(job.PreviousRun),
+		Running:     job.Running,
+	}
+	if job.LastRun != nil {
+		lastRun := toJobRunResponse(*job.LastRun)
+		result.LastRun = &lastRun
+	}
+	return result
+}
+
+func toJobRunResponse(run entity.JobRun) model.JobRunResponse {
+	return model.JobRunResponse{
+		RunID:   run.RunID,
+		JobName: run.JobName,
+		Status:  string(run.Status),
+		Trigger: string(run.Trigger),
+		Params: model.JobParams{
+			Date:      run.Params.Date,
+			Period:    run.Params.Period,
+			Year:      run.Params.Year,
+			StartDate: run.Params.StartDate,
+			EndDate:   run.Params.EndDate,
+		},
+		StartedAt:  run.StartedAt,
+		FinishedAt: optionalTime(run.FinishedAt),
+		DurationMs: run.Duration.Milliseconds(),
+		Records:    run.Records,
+		Error:      run.Error,
+	}
+}
+
+// optionalTime maps the zero time to nil so it is rendered as null.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,513 @@
+package handler
+
+import (
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"go-stock/internal/entity"
+	"go-stock/internal/model"
+	"go-stock/internal/shared/response"
+	"go-stock/internal/usecase"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type MarketHandler interface {
+	FindLeaders(w http.ResponseWriter, r *http.Request)
+	FindSectors(w http.ResponseWriter, r *http.Request)
+	FindSector(w http.ResponseWriter, r *http.Request)
+	FindBreadth(w http.ResponseWriter, r *http.Request)
+	FindPriceLimits(w http.ResponseWriter, r *http.Request)
+	FindAutoRejections(w http.ResponseWriter, r *http.Request)
+}
+
+type marketHandler struct {
+	marketUseCase usecase.MarketUseCase
+	validate      *validator.Validate
+}
+
+func NewMarketHandler(marketUseCase usecase.MarketUseCase, validate *validator.Validate) MarketHandler {
+	return &marketHandler{
+		marketUseCase: marketUseCase,
+		validate:      validate,
+	}
+}
+
+// FindLeaders find market leaders
+// @Summary Find market leaders
+// @Description Leaderboards of a trading day: top gainers and losers by percent change, most active by value, volume and frequency, top foreign net buy and sell by value. Without a date the latest stored day is used, without a type every leaderboard is returned.
+// @Tags Market
+// @Produce json
+// @Param date query string false "Trading date (YYYY-MM-DD)"
+// @Param type query string false "Comma separated leaderboards" example(gainers,most_value)
+// @Param board query string false "Listing board" example(Utama)
+// @Param min_value query number false "Minimum traded value"
+// @Param min_volume query number false "Minimum traded volume"
+// @Param limit query int64 false "Leaders per leaderboard (default: 10, max: 100)" default(10) minimum(1) maximum(100)
+// @Success 200 {object} model.MarketLeadersResponse
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/market/leaders [get]
+func (h *marketHandler) FindLeaders(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	query := r.URL.Query()
+
+	request := model.MarketLeaderRequest{
+		Date:  query.Get("date"),
+		Board: query.Get("board"),
+		Limit: 10,
+	}
+	if types := query.Get("type"); types != "" {
+		for _, t := range strings.Split(types, ",") {
+			request.Types = append(request.Types, strings.ToLower(strings.TrimSpace(t)))
+		}
+	}
+
+	var errs []response.Error
+	for field, target := range map[string]*float64{"min_value": &request.MinValue, "min_volume": &request.MinVolume} {
+		if value := query.Get(field); value != "" {
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				errs = append(errs, response.Error{Field: field, Message: "must be a number"})
+				continue
+			}
+			*target = number
+		}
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			errs = append(errs, response.Error{Field: "limit", Message: "must be a whole number"})
+		}
+		request.Limit = limit
+	}
+	if len(errs) > 0 {
+		response.BadRequest(w, "", errs)
+		return
+	}
+
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return
+		}
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	var date time.Time
+	if request.Date != "" {
+		date, _ = time.Parse("2006-01-02", request.Date)
+	}
+	filter := entity.LeaderFilter{
+		MinValue:  request.MinValue,
+		MinVolume: request.MinVolume,
+	}
+
+	result, err := h.marketUseCase.FindLeaders(r.Context(), date, request.Types, request.Board, filter, request.Limit)
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	data := model.MarketLeadersResponse{
+		Leaderboards: make([]model.MarketLeaderboard, 0, len(result.Leaderboards)),
+	}
+	if !result.Date.IsZero() {
+		data.Date = result.Date.Format("2006-01-02")
+	}
+	for _, leaderboard := range result.Leaderboards {
+		leaders := make([]model.MarketLeaderResponse, 0, len(leaderboard.Leaders))
+		for _, leader := range leaderboard.Leaders {
+			leaders = append(leaders, model.MarketLeaderResponse{
+				StockCode:       leader.StockCode,
+				StockName:       leader.StockName,
+				Previous:        leader.Previous,
+				Close:           leader.Close,
+				Change:          leader.Change,
+				ChangePct:       leader.ChangePct,
+				Volume:          leader.Volume,
+				Value:           leader.Value,
+				Frequency:       leader.Frequency,
+				ForeignBuy:      leader.ForeignBuy,
+				ForeignSell:     leader.ForeignSell,
+				ForeignNet:      leader.ForeignNet,
+				ForeignNetValue: leader.ForeignNetValue,
+			})
+		}
+		data.Leaderboards = append(data.Leaderboards, model.MarketLeaderboard{
+			Name:    leaderboard.Name,
+			Leaders: leaders,
+		})
+	}
+
+	response.Success(w, data, "")
+	return
+}
+
+// FindSectors find sectors
+// @Summary Find sectors
+// @Description Aggregate the stocks by the sector, sub-sector, industry or sub-industry of their profile: market capitalization weighted return, value traded and foreign net value over a date range. Without dates the latest stored day is used.
+// @Tags Market
+// @Produce json
+// @Param request query model.SectorRequest false "query params"
+// @Success 200 {object} model.SectorOverviewResponse
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/market/sectors [get]
+func (h *marketHandler) FindSectors(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	group, startDate, endDate, ok := h.sectorRequest(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.marketUseCase.FindSectors(r.Context(), startDate, endDate, group)
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	data := model.SectorOverviewResponse{
+		Group:   result.Group,
+		Sectors: make([]model.SectorResponse, 0, len(result.Sectors)),
+	}
+	if !result.EndDate.IsZero() {
+		data.StartDate = result.StartDate.Format("2006-01-02")
+		data.EndDate = result.EndDate.Format("2006-01-02")
+	}
+	for _, sector := range result.Sectors {
+		data.Sectors = append(data.Sectors, toSectorResponse(sector))
+	}
+
+	response.Success(w, data, "")
+	return
+}
+
+// FindSector find sector
+// @Summary Find sector
+// @Description Aggregate one sector like FindSectors, with its constituents ordered by weight.
+// @Tags Market
+// @Produce json
+// @Param name path string true "Sector name" example(Keuangan)
+// @Param request query model.SectorRequest false "query params"
+// @Success 200 {object} model.SectorResponse
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/market/sectors/{name} [get]
+func (h *marketHandler) FindSector(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	group, startDate, endDate, ok := h.sectorRequest(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.marketUseCase.FindSector(r.Context(), startDate, endDate, group, r.PathValue("name"))
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+	if result == nil {
+		response.NotFound(w, "sector not found")
+		return
+	}
+
+	response.Success(w, toSectorResponse(*result), "")
+	return
+}
+
+// sectorRequest validates the query of the sector endpoints and writes the error
+// response when it is invalid. A single date stands for a one day range.
+func (h *marketHandler) sectorRequest(w http.ResponseWriter, r *http.Request) (string, time.Time, time.Time, bool) {
+	request := model.SectorRequest{
+		Group:     r.URL.Query().Get("group"),
+		StartDate: r.URL.Query().Get("start_date"),
+		EndDate:   r.URL.Query().Get("end_date"),
+	}
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return "", time.Time{}, time.Time{}, false
+		}
+		response.InternalError(w, err.Error())
+		return "", time.Time{}, time.Time{}, false
+	}
+
+	group := request.Group
+	if group == "" {
+		group = entity.SectorGroupSector
+	}
+	startDate, _ := time.Parse("2006-01-02", request.StartDate)
+	endDate, _ := time.Parse("2006-01-02", request.EndDate)
+	switch {
+	case startDate.IsZero():
+		startDate = endDate
+	case endDate.IsZero():
+		endDate = startDate
+	}
+	if endDate.Before(startDate) {
+		response.BadRequest(w, "end_date must not be before start_date", nil)
+		return "", time.Time{}, time.Time{}, false
+	}
+	return group, startDate, endDate, true
+}
+
+// FindBreadth find market breadth
+// @Summary Find market breadth
+// @Description Market breadth of each trading day: advancers, decliners and unchanged stocks, new 52-week highs and lows, the percentage of stocks above their 20, 50 and 200 day averages and the total turnover. Without dates the 30 days up to the latest stored day are returned.
+// @Tags Market
+// @Produce json
+// @Param request query model.MarketBreadthRequest false "query params"
+// @Success 200 {array} model.MarketBreadthResponse
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/market/breadth [get]
+func (h *marketHandler) FindBreadth(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	request := model.MarketBreadthRequest{
+		StartDate: r.URL.Query().Get("start_date"),
+		EndDate:   r.URL.Query().Get("end_date"),
+	}
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return
+		}
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	startDate, _ := time.Parse("2006-01-02", request.StartDate)
+	endDate, _ := time.Parse("2006-01-02", request.EndDate)
+	if !startDate.IsZero() && !endDate.IsZero() && endDate.Before(startDate) {
+		response.BadRequest(w, "end_date must not be before start_date", nil)
+		return
+	}
+
+	result, err := h.marketUseCase.FindBreadth(r.Context(), startDate, endDate)
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	data := make([]model.MarketBreadthResponse, 0, len(result))
+	for _, breadth := range result {
+		data = append(data, model.MarketBreadthResponse{
+			Date:           breadth.Date.Format("2006-01-02"),
+			Stocks:         breadth.Stocks,
+			Advancers:      breadth.Advancers,
+			Decliners:      breadth.Decliners,
+			Unchanged:      breadth.Unchanged,
+			AdvanceDecline: breadth.Advancers - breadth.Decliners,
+			NewHighs:       breadth.NewHighs,
+			NewLows:        breadth.NewLows,
+			AboveSMA20Pct:  breadth.AboveSMA20,
+			AboveSMA50Pct:  breadth.AboveSMA50,
+			AboveSMA200Pct: breadth.AboveSMA200,
+			Value:          breadth.Value,
+			Volume:         breadth.Volume,
+			Frequency:      breadth.Frequency,
+		})
+	}
+
+	response.Success(w, data, "")
+	return
+}
+
+func toSectorResponse(sector entity.Sector) model.SectorResponse {
+	result := model.SectorResponse{
+		Name:            sector.Name,
+		ReturnPct:       sector.Return * 100,
+		MarketCap:       sector.MarketCap,
+		Value:           sector.Value,
+		ForeignNetValue: sector.ForeignNetValue,
+		Advancers:       sector.Advancers,
+		Decliners:       sector.Decliners,
+		Unchanged:       sector.Unchanged,
+	}
+	for _, constituent := range sector.Constituents {
+		result.Constituents = append(result.Constituents, model.SectorConstituentResponse{
+			StockCode:       constituent.StockCode,
+			StockName:       constituent.StockName,
+			Previous:        constituent.Previous,
+			Close:           constituent.Close,
+			ReturnPct:       constituent.Return * 100,
+			MarketCap:       constituent.MarketCap,
+			WeightPct:       constituent.Weight * 100,
+			Value:           constituent.Value,
+			Volume:          constituent.Volume,
+			ForeignNetValue: constituent.ForeignNetValue,
+		})
+	}
+	return result
+}
+
+// FindPriceLimits find price limits
+// @Summary Find price limits
+// @Description Round a price to the IDX tick size of its band (1 below 200, 2 below 500, 5 below 2000, 10 below 5000, 25 above) and give the auto-rejection limits (ARA and ARB) of the next session on the board of the stock, from its latest stored close.
+// @Tags Market
+// @Produce json
+// @Param stock_code query string true "Stock code" example(BBCA)
+// @Param price query number true "Price" example(9012)
+// @Success 200 {object} model.PriceLimitsResponse
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/market/price_limits [get]
+func (h *marketHandler) FindPriceLimits(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	query := r.URL.Query()
+
+	request := model.PriceLimitsRequest{StockCode: query.Get("stock_code")}
+	if value := query.Get("price"); value != "" {
+		price, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			response.BadRequest(w, "", []response.Error{{Field: "price", Message: "must be a number"}})
+			return
+		}
+		request.Price = price
+	}
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return
+		}
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	result, err := h.marketUseCase.FindPriceLimits(r.Context(), request.StockCode, request.Price)
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+	if result == nil {
+		response.NotFound(w, "stock not found")
+		return
+	}
+
+	data := model.PriceLimitsResponse{
+		StockCode:      result.StockCode,
+		Board:          result.Board,
+		Price:          result.Price,
+		TickSize:       result.TickSize,
+		Valid:          result.Valid,
+		RoundedPrice:   result.RoundedPrice,
+		RoundDown:      result.RoundDown,
+		RoundUp:        result.RoundUp,
+		ReferenceClose: result.ReferenceClose,
+		ARA:            result.Upper,
+		ARB:            result.Lower,
+		ARAPct:         result.UpperPercent,
+		ARBPct:         result.LowerPercent,
+	}
+	if !result.ReferenceDate.IsZero() {
+		data.ReferenceDate = result.ReferenceDate.Format("2006-01-02")
+	}
+
+	response.Success(w, data, "")
+	return
+}
+
+// FindAutoRejections find auto rejections
+// @Summary Find auto rejections
+// @Description Summaries whose high reached the upper auto-rejection limit (ARA) around their previous close, or whose low reached the lower limit (ARB), under the limits of the board of the stock. Without dates the latest stored day is checked.
+// @Tags Market
+// @Produce json
+// @Param request query model.AutoRejectionRequest false "query params"
+// @Success 200 {array} model.AutoRejectionResponse
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/market/auto_rejections [get]
+func (h *marketHandler) FindAutoRejections(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	request := model.AutoRejectionRequest{
+		StockCode: r.URL.Query().Get("stock_code"),
+		StartDate: r.URL.Query().Get("start_date"),
+		EndDate:   r.URL.Query().Get("end_date"),
+	}
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return
+		}
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	startDate, _ := time.Parse("2006-01-02", request.StartDate)
+	endDate, _ := time.Parse("2006-01-02", request.EndDate)
+	if !startDate.IsZero() && !endDate.IsZero() && endDate.Before(startDate) {
+		response.BadRequest(w, "end_date must not be before start_date", nil)
+		return
+	}
+
+	result, err := h.marketUseCase.FindAutoRejections(r.Context(), request.StockCode, startDate, endDate)
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	data := make([]model.AutoRejectionResponse, 0, len(result))
+	for _, rejection := range result {
+		data = append(data, model.AutoRejectionResponse{
+			Date:          rejection.Date.Format("2006-01-02"),
+			StockCode:     rejection.StockCode,
+			StockName:     rejection.StockName,
+			Board:         rejection.Board,
+			Previous:      rejection.Previous,
+			High:          rejection.High,
+			Low:           rejection.Low,
+			Close:         rejection.Close,
+			ARA:           rejection.Upper,
+			ARB:           rejection.Lower,
+			Limit:         rejection.Limit,
+			ClosedAtLimit: rejection.ClosedAtLimit,
+		})
+	}
+
+	response.Success(w, data, "")
+	return
+}
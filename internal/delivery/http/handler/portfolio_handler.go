@@ -0,0 +1,317 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"go-stock/internal/entity"
+	"go-stock/internal/marketrules"
+	"go-stock/internal/model"
+	"go-stock/internal/shared/response"
+	"go-stock/internal/usecase"
+	"net/http"
+	"time"
+)
+
+type PortfolioHandler interface {
+	FindPortfolio(w http.ResponseWriter, r *http.Request)
+	ListTrades(w http.ResponseWriter, r *http.Request)
+	RecordTrade(w http.ResponseWriter, r *http.Request)
+	DeleteTrade(w http.ResponseWriter, r *http.Request)
+	FindValues(w http.ResponseWriter, r *http.Request)
+}
+
+type portfolioHandler struct {
+	portfolioUseCase usecase.PortfolioUseCase
+	validate         *validator.Validate
+}
+
+func NewPortfolioHandler(portfolioUseCase usecase.PortfolioUseCase, validate *validator.Validate) PortfolioHandler {
+	return &portfolioHandler{
+		portfolioUseCase: portfolioUseCase,
+		validate:         validate,
+	}
+}
+
+// FindPortfolio find portfolio
+// @Summary Find portfolio
+// @Description Positions of a portfolio at the close of a day, at average cost, with realized and unrealized profit and loss and the cash dividends earned on the shares held before each ex-date.
+// @Tags Portfolios
+// @Produce json
+// @Param name path string true "Portfolio name"
+// @Param date query string false "Valuation date (YYYY-MM-DD, default: the latest stored day)"
+// @Success 200 {object} model.PortfolioResponse
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/portfolios/{name} [get]
+func (h *portfolioHandler) FindPortfolio(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	request := model.PortfolioRequest{
+		Date: r.URL.Query().Get("date"),
+	}
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return
+		}
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	date, _ := time.Parse("2006-01-02", request.Date)
+	result, err := h.portfolioUseCase.FindPortfolio(r.Context(), r.PathValue("name"), date)
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+	if result == nil {
+		response.NotFound(w, "portfolio not found")
+		return
+	}
+
+	data := model.PortfolioResponse{
+		Name:           result.Name,
+		Date:           result.Date.Format("2006-01-02"),
+		MarketValue:    result.MarketValue,
+		Cost:           result.Cost,
+		UnrealizedPnL:  result.UnrealizedPnL,
+		RealizedPnL:    result.RealizedPnL,
+		DividendIncome: result.DividendIncome,
+		Positions:      make([]model.PositionResponse, 0, len(result.Positions)),
+		Dividends:      make([]model.DividendIncomeResponse, 0, len(result.Dividends)),
+	}
+	for _, position := range result.Positions {
+		data.Positions = append(data.Positions, model.PositionResponse{
+			StockCode:      position.StockCode,
+			StockName:      position.StockName,
+			Shares:         position.Shares,
+			AverageCost:    position.AverageCost,
+			Cost:           position.Cost,
+			Close:          position.Close,
+			MarketValue:    position.MarketValue,
+			UnrealizedPnL:  position.UnrealizedPnL,
+			RealizedPnL:    position.RealizedPnL,
+			DividendIncome: position.DividendIncome,
+		})
+	}
+	for _, dividend := range result.Dividends {
+		income := model.DividendIncomeResponse{
+			StockCode:    dividend.StockCode,
+			ExDate:       dividend.ExDate.Format("2006-01-02"),
+			CashPerShare: dividend.CashPerShare,
+			Shares:       dividend.Shares,
+			Amount:       dividend.Amount,
+		}
+		if !dividend.PaymentDate.IsZero() {
+			income.PaymentDate = dividend.PaymentDate.Format("2006-01-02")
+		}
+		data.Dividends = append(data.Dividends, income)
+	}
+
+	response.Success(w, data, "")
+	return
+}
+
+// ListTrades list trades
+// @Summary List trades
+// @Description List the trades of a portfolio in the order they happened.
+// @Tags Portfolios
+// @Produce json
+// @Param name path string true "Portfolio name"
+// @Success 200 {array} model.TradeResponse
+// @Failure 500 {object} response.Error
+// @Router /api/v1/portfolios/{name}/trades [get]
+func (h *portfolioHandler) ListTrades(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	trades, err := h.portfolioUseCase.FindTrades(r.Context(), r.PathValue("name"))
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	data := make([]model.TradeResponse, 0, len(trades))
+	for _, trade := range trades {
+		data = append(data, toTradeResponse(trade))
+	}
+
+	response.Success(w, data, "")
+	return
+}
+
+// RecordTrade record trade
+// @Summary Record trade
+// @Description Record a buy or sell of a listed stock in a portfolio, which is created by its first trade. A sale may not exceed the shares held.
+// @Tags Portfolios
+// @Accept json
+// @Produce json
+// @Param name path string true "Portfolio name"
+// @Param request body model.TradeRequest true "trade"
+// @Success 201 {object} model.TradeResponse
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/portfolios/{name}/trades [post]
+func (h *portfolioHandler) RecordTrade(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var request model.TradeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		response.BadRequest(w, "invalid request body", nil)
+		return
+	}
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return
+		}
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	date, _ := time.Parse("2006-01-02", request.Date)
+	trade, err := h.portfolioUseCase.RecordTrade(r.Context(), entity.Trade{
+		Portfolio: r.PathValue("name"),
+		StockCode: request.StockCode,
+		Date:      date,
+		Side:      request.Side,
+		Price:     request.Price,
+		Lots:      request.Lots,
+		Fee:       request.Fee,
+	})
+	if errors.Is(err, usecase.ErrInvalidTrade) {
+		response.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	response.Created(w, toTradeResponse(*trade), "")
+	return
+}
+
+// DeleteTrade delete trade
+// @Summary Delete trade
+// @Description Delete a trade recorded by mistake. A buy cannot be deleted while later sales depend on its shares.
+// @Tags Portfolios
+// @Produce json
+// @Param name path string true "Portfolio name"
+// @Param id path string true "Trade ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/portfolios/{name}/trades/{id} [delete]
+func (h *portfolioHandler) DeleteTrade(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	deleted, err := h.portfolioUseCase.DeleteTrade(r.Context(), r.PathValue("name"), r.PathValue("id"))
+	if errors.Is(err, usecase.ErrInvalidTrade) {
+		response.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+	if !deleted {
+		response.NotFound(w, "trade not found")
+		return
+	}
+
+	response.Success(w, nil, "trade deleted")
+	return
+}
+
+// FindValues find portfolio values
+// @Summary Find portfolio values
+// @Description Value of a portfolio at the close of every trading day, with its cost, unrealized profit and loss, and the realized profit and loss and dividend income up to the day.
+// @Tags Portfolios
+// @Produce json
+// @Param name path string true "Portfolio name"
+// @Param request query model.PortfolioValuesRequest false "query params"
+// @Success 200 {array} model.PortfolioValueResponse
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/portfolios/{name}/values [get]
+func (h *portfolioHandler) FindValues(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	request := model.PortfolioValuesRequest{
+		StartDate: r.URL.Query().Get("start_date"),
+		EndDate:   r.URL.Query().Get("end_date"),
+	}
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return
+		}
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	startDate, _ := time.Parse("2006-01-02", request.StartDate)
+	endDate, _ := time.Parse("2006-01-02", request.EndDate)
+	if !startDate.IsZero() && !endDate.IsZero() && endDate.Before(startDate) {
+		response.BadRequest(w, "end_date must not be before start_date", nil)
+		return
+	}
+
+	values, err := h.portfolioUseCase.FindValues(r.Context(), r.PathValue("name"), startDate, endDate)
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	data := make([]model.PortfolioValueResponse, 0, len(values))
+	for _, value := range values {
+		data = append(data, model.PortfolioValueResponse{
+			Date:           value.Date.Format("2006-01-02"),
+			MarketValue:    value.MarketValue,
+			Cost:           value.Cost,
+			UnrealizedPnL:  value.UnrealizedPnL,
+			RealizedPnL:    value.RealizedPnL,
+			DividendIncome: value.DividendIncome,
+		})
+	}
+
+	response.Success(w, data, "")
+	return
+}
+
+func toTradeResponse(trade entity.Trade) model.TradeResponse {
+	return model.TradeResponse{
+		ID:        trade.ID,
+		Portfolio: trade.Portfolio,
+		StockCode: trade.StockCode,
+		Date:      trade.Date.Format("2006-01-02"),
+		Side:      trade.Side,
+		Price:     trade.Price,
+		Lots:      trade.Lots,
+		Shares:    trade.Lots * marketrules.LotSize,
+		Fee:       trade.Fee,
+		CreatedAt: trade.CreatedAt,
+	}
+}
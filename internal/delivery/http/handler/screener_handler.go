@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"go-stock/internal/model"
+	"go-stock/internal/screener"
+	"go-stock/internal/shared/response"
+	"go-stock/internal/usecase"
+	"net/http"
+)
+
+type ScreenerHandler interface {
+	Screen(w http.ResponseWriter, r *http.Request)
+}
+
+type screenerHandler struct {
+	screenerUseCase usecase.ScreenerUseCase
+	validate        *validator.Validate
+}
+
+func NewScreenerHandler(screenerUseCase usecase.ScreenerUseCase, validate *validator.Validate) ScreenerHandler {
+	return &screenerHandler{
+		screenerUseCase: screenerUseCase,
+		validate:        validate,
+	}
+}
+
+// Screen stocks
+// @Summary Screen stocks
+// @Description Filter the stocks traded on the latest stored date by the fields of their summary (close, value, foreign_buy, ...), derived metrics (change_pct, foreign_net, foreign_net_value, typical_price, spread, turnover_ratio of the tradable shares, listed_turnover_ratio of the listed shares, market_cap), stock attributes (board, sector, sub_sector, industry, sub_industry) and indicators (e.g. sma:50, rsi:14, macd.signal). Filters combine with all, any and not.
+// @Tags Screener
+// @Accept json
+// @Produce json
+// @Param request body model.ScreenerRequest true "filter, sort and page"
+// @Success 200 {object} model.ScreenerResponse
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/screener [post]
+func (h *screenerHandler) Screen(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	if r.Method != http.MethodPost {
+		response.MethodNotAllowed(w, "")
+		return
+	}
+
+	var request model.ScreenerRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		response.BadRequest(w, "invalid request body", nil)
+		return
+	}
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return
+		}
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	page, limit := request.Page, request.Limit
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	sorts := make([]screener.Sort, 0, len(request.Sort))
+	for _, s := range request.Sort {
+		sorts = append(sorts, screener.Sort{
+			Field:      s.Field,
+			Descending: s.Order == "desc",
+		})
+	}
+
+	result, err := h.screenerUseCase.Screen(r.Context(), toScreenerFilter(request.Filter), sorts, limit, (page-1)*limit)
+	if errors.Is(err, usecase.ErrInvalidScreener) {
+		response.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	data := model.ScreenerResponse{
+		Data:  make([]model.ScreenerStockRow, 0, len(result.Rows)),
+		Total: result.Total,
+		Page:  page,
+		Limit: limit,
+	}
+	if !result.Date.IsZero() {
+		data.Date = result.Date.Format("2006-01-02")
+	}
+	data.TotalPages = result.Total / limit
+	if result.Total%limit != 0 {
+		data.TotalPages++
+	}
+	for _, row := range result.Rows {
+		data.Data = append(data.Data, model.ScreenerStockRow{
+			StockCode: row.StockCode,
+			StockName: row.StockName,
+			Board:     row.Board,
+			Sector:    row.Sector,
+			SubSector: row.SubSector,
+			Values:    row.Values,
+		})
+	}
+
+	response.Success(w, data, "")
+	return
+}
+
+func toScreenerFilter(filter model.ScreenerFilter) screener.Filter {
+	result := screener.Filter{
+		Field:      filter.Field,
+		Op:         filter.Op,
+		Value:      filter.Value,
+		ValueField: filter.ValueField,
+	}
+	for _, child := range filter.All {
+		result.All = append(result.All, toScreenerFilter(child))
+	}
+	for _, child := range filter.Any {
+		result.Any = append(result.Any, toScreenerFilter(child))
+	}
+	if filter.Not != nil {
+		not := toScreenerFilter(*filter.Not)
+		result.Not = &not
+	}
+	return result
+}
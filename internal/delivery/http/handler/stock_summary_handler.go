@@ -1,33 +1,40 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
 	"github.com/go-playground/validator/v10"
+	"go-stock/internal/entity"
 	"go-stock/internal/model"
 	"go-stock/internal/shared/response"
 	"go-stock/internal/usecase"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type StockSummaryHandler interface {
 	FindStockSummaries(w http.ResponseWriter, r *http.Request)
+	BackfillStockSummaries(w http.ResponseWriter, r *http.Request)
 }
 type stockSummaryHandler struct {
 	stockSummaryUseCase usecase.StockSummaryUseCase
+	jobUseCase          usecase.JobUseCase
 	validate            *validator.Validate
 }
 
-func NewStockSummaryHandler(stockSummaryUseCase usecase.StockSummaryUseCase, validate *validator.Validate) StockSummaryHandler {
+func NewStockSummaryHandler(stockSummaryUseCase usecase.StockSummaryUseCase, jobUseCase usecase.JobUseCase, validate *validator.Validate) StockSummaryHandler {
 	return &stockSummaryHandler{
 		stockSummaryUseCase: stockSummaryUseCase,
+		jobUseCase:          jobUseCase,
 		validate:            validate,
 	}
 }
 
 // FindStockSummaries find stock summaries
 // @Summary Find stock summaries
-// @Description Find stock summaries by stock code, start date, and end date
+// @Description Find stock summaries by stock code, start date, and end date. With a weekly, monthly or yearly interval the daily summaries are rolled up into one bar per period, dated on its first day. Adjusted summaries are back-adjusted for stock splits, bonus shares and cash dividends. Every summary carries its derived metrics: change_pct, foreign_net_value, typical_price, spread, turnover_ratio and market_cap.
 // @Tags Stock
 // @Produce json
 // @Param request query model.StockSummaryRequest true "query params"
@@ -40,11 +47,15 @@ func (s *stockSummaryHandler) FindStockSummaries(w http.ResponseWriter, r *http.
 	stockCode := r.URL.Query().Get("stock_code")
 	startDate := r.URL.Query().Get("start_date")
 	endDate := r.URL.Query().Get("end_date")
+	interval := r.URL.Query().Get("interval")
+	adjusted := r.URL.Query().Get("adjusted")
 
 	request := model.StockSummaryRequest{
 		StockCode: stockCode,
 		StartDate: startDate,
 		EndDate:   endDate,
+		Interval:  interval,
+		Adjusted:  adjusted,
 	}
 	if err := s.validate.Struct(request); err != nil {
 		var validationErrs validator.ValidationErrors
@@ -63,7 +74,8 @@ func (s *stockSummaryHandler) FindStockSummaries(w http.ResponseWriter, r *http.
 		return
 	}
 
-	results, err := s.stockSummaryUseCase.FindSummaries(r.Context(), strings.ToUpper(stockCode), startDate, endDate)
+	isAdjusted, _ := strconv.ParseBool(adjusted)
+	results, err := s.stockSummaryUseCase.FindSummaries(r.Context(), strings.ToUpper(stockCode), startDate, endDate, interval, isAdjusted)
 	if err != nil {
 		response.InternalError(w, err.Error())
 		return
@@ -103,9 +115,82 @@ func (s *stockSummaryHandler) FindStockSummaries(w http.ResponseWriter, r *http.
 			NonRegularFrequency: result.NonRegularFrequency,
 			Persen:              result.Persen,
 			Percentage:          result.Percentage,
+			ChangePct:           result.ChangePct,
+			ForeignNetValue:     result.ForeignNetValue,
+			TypicalPrice:        result.TypicalPrice,
+			Spread:              result.Spread,
+			TurnoverRatio:       result.TurnoverRatio,
+			MarketCap:           result.MarketCap,
 		})
 	}
 
 	response.Success(w, data, "")
 	return
 }
+
+// BackfillStockSummaries backfill stock summaries
+// @Summary Backfill stock summaries
+// @Description Start a BackfillSummaries job run ingesting stock summaries for every missing trading day between start date and end date, skipping weekends and holidays. The run is returned right away; follow it with GET /api/v1/jobs/BackfillSummaries/runs.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.StockSummaryBackfillRequest true "date range"
+// @Success 202 {object} model.JobRunResponse
+// @Failure 400 {object} response.Error
+// @Failure 401 {object} response.Error
+// @Failure 409 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/admin/stock/summaries/backfill [post]
+func (s *stockSummaryHandler) BackfillStockSummaries(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	if r.Method != http.MethodPost {
+		response.MethodNotAllowed(w, "")
+		return
+	}
+
+	var request model.StockSummaryBackfillRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		response.BadRequest(w, "invalid request body", nil)
+		return
+	}
+	if err := s.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return
+		}
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	startDate, _ := time.Parse("2006-01-02", request.StartDate)
+	endDate, _ := time.Parse("2006-01-02", request.EndDate)
+	if endDate.Before(startDate) {
+		response.BadRequest(w, "end_date must not be before start_date", nil)
+		return
+	}
+
+	run, err := s.jobUseCase.Trigger(r.Context(), usecase.JobBackfillSummaries, entity.JobParams{
+		StartDate: request.StartDate,
+		EndDate:   request.EndDate,
+	})
+	if errors.Is(err, usecase.ErrJobRunning) {
+		response.Conflict(w, err.Error())
+		return
+	}
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	response.Accepted(w, toJobRunResponse(*run), "")
+	return
+}
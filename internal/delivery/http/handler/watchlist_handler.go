@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"go-stock/internal/entity"
+	"go-stock/internal/model"
+	"go-stock/internal/shared/response"
+	"go-stock/internal/usecase"
+	"net/http"
+	"strings"
+)
+
+// UserIDHeader identifies the user whose watchlists are read and written.
+const UserIDHeader = "X-User-ID"
+
+type WatchlistHandler interface {
+	ListWatchlists(w http.ResponseWriter, r *http.Request)
+	CreateWatchlist(w http.ResponseWriter, r *http.Request)
+	FindWatchlist(w http.ResponseWriter, r *http.Request)
+	UpdateWatchlist(w http.ResponseWriter, r *http.Request)
+	DeleteWatchlist(w http.ResponseWriter, r *http.Request)
+}
+
+type watchlistHandler struct {
+	watchlistUseCase usecase.WatchlistUseCase
+	validate         *validator.Validate
+}
+
+func NewWatchlistHandler(watchlistUseCase usecase.WatchlistUseCase, validate *validator.Validate) WatchlistHandler {
+	return &watchlistHandler{
+		watchlistUseCase: watchlistUseCase,
+		validate:         validate,
+	}
+}
+
+// ListWatchlists list watchlists
+// @Summary List watchlists
+// @Description List the watchlists of the user ordered by name, each stock with its latest summary.
+// @Tags Watchlists
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Success 200 {array} model.WatchlistResponse
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/watchlists [get]
+func (h *watchlistHandler) ListWatchlists(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	userID, ok := userID(w, r)
+	if !ok {
+		return
+	}
+
+	watchlists, err := h.watchlistUseCase.FindWatchlists(r.Context(), userID)
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	data := make([]model.WatchlistResponse, 0, len(watchlists))
+	for _, watchlist := range watchlists {
+		data = append(data, toWatchlistResponse(watchlist))
+	}
+
+	response.Success(w, data, "")
+	return
+}
+
+// CreateWatchlist create watchlist
+// @Summary Create watchlist
+// @Description Save a named list of listed stocks for the user.
+// @Tags Watchlists
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param request body model.WatchlistRequest true "watchlist"
+// @Success 201 {object} model.WatchlistResponse
+// @Failure 400 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/watchlists [post]
+func (h *watchlistHandler) CreateWatchlist(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	userID, ok := userID(w, r)
+	if !ok {
+		return
+	}
+	request, ok := h.watchlistRequest(w, r)
+	if !ok {
+		return
+	}
+
+	watchlist, err := h.watchlistUseCase.CreateWatchlist(r.Context(), entity.Watchlist{
+		UserID:     userID,
+		Name:       request.Name,
+		StockCodes: request.StockCodes,
+	})
+	if errors.Is(err, usecase.ErrInvalidWatchlist) {
+		response.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+
+	response.Created(w, toWatchlistResponse(*watchlist), "")
+	return
+}
+
+// FindWatchlist find watchlist
+// @Summary Find watchlist
+// @Description A watchlist of the user, each stock with its latest summary.
+// @Tags Watchlists
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Watchlist ID"
+// @Success 200 {object} model.WatchlistResponse
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/watchlists/{id} [get]
+func (h *watchlistHandler) FindWatchlist(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	userID, ok := userID(w, r)
+	if !ok {
+		return
+	}
+
+	watchlist, err := h.watchlistUseCase.FindWatchlist(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+	if watchlist == nil {
+		response.NotFound(w, "watchlist not found")
+		return
+	}
+
+	response.Success(w, toWatchlistResponse(*watchlist), "")
+	return
+}
+
+// UpdateWatchlist update watchlist
+// @Summary Update watchlist
+// @Description Replace the name and stocks of a watchlist of the user.
+// @Tags Watchlists
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Watchlist ID"
+// @Param request body model.WatchlistRequest true "watchlist"
+// @Success 200 {object} model.WatchlistResponse
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/watchlists/{id} [put]
+func (h *watchlistHandler) UpdateWatchlist(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	userID, ok := userID(w, r)
+	if !ok {
+		return
+	}
+	request, ok := h.watchlistRequest(w, r)
+	if !ok {
+		return
+	}
+
+	watchlist, err := h.watchlistUseCase.UpdateWatchlist(r.Context(), entity.Watchlist{
+		ID:         r.PathValue("id"),
+		UserID:     userID,
+		Name:       request.Name,
+		StockCodes: request.StockCodes,
+	})
+	if errors.Is(err, usecase.ErrInvalidWatchlist) {
+		response.BadRequest(w, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+	if watchlist == nil {
+		response.NotFound(w, "watchlist not found")
+		return
+	}
+
+	response.Success(w, toWatchlistResponse(*watchlist), "")
+	return
+}
+
+// DeleteWatchlist delete watchlist
+// @Summary Delete watchlist
+// @Tags Watchlists
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Watchlist ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Error
+// @Failure 404 {object} response.Error
+// @Failure 500 {object} response.Error
+// @Router /api/v1/watchlists/{id} [delete]
+func (h *watchlistHandler) DeleteWatchlist(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	userID, ok := userID(w, r)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.watchlistUseCase.DeleteWatchlist(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		response.InternalError(w, err.Error())
+		return
+	}
+	if !deleted {
+		response.NotFound(w, "watchlist not found")
+		return
+	}
+
+	response.Success(w, nil, "watchlist deleted")
+	return
+}
+
+// watchlistRequest decodes and validates the body of a watchlist and writes the
+// error response when it is invalid.
+func (h *watchlistHandler) watchlistRequest(w http.ResponseWriter, r *http.Request) (model.WatchlistRequest, bool) {
+	var request model.WatchlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		response.BadRequest(w, "invalid request body", nil)
+		return request, false
+	}
+	if err := h.validate.Struct(request); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			errs := make([]response.Error, 0, len(validationErrs))
+			for _, fieldError := range validationErrs {
+				errs = append(errs, response.Error{
+					Field:   fieldError.Field(),
+					Message: fieldError.Error(),
+				})
+			}
+			response.BadRequest(w, "", errs)
+			return request, false
+		}
+		response.InternalError(w, err.Error())
+		return request, false
+	}
+	return request, true
+}
+
+// userID returns the user of the request and writes the error response when the
+// header is missing.
+func userID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID := strings.TrimSpace(r.Header.Get(UserIDHeader))
+	if userID == "" {
+		response.BadRequest(w, "", []response.Error{{Field: UserIDHeader, Message: "header is required"}})
+		return "", false
+	}
+	return userID, true
+}
+
+func toWatchlistResponse(watchlist entity.Watchlist) model.WatchlistResponse {
+	result := model.WatchlistResponse{
+		ID:        watchlist.ID,
+		Name:      watchlist.Name,
+		Stocks:    make([]model.WatchlistStockResponse, 0, len(watchlist.Stocks)),
+		CreatedAt: watchlist.CreatedAt,
+		UpdatedAt: watchlist.UpdatedAt,
+	}
+	for _, stock := range watchlist.Stocks {
+		item := model.WatchlistStockResponse{StockCode: stock.StockCode}
+		if summary := stock.Summary; summary != nil {
+			item.StockName = summary.StockName
+			item.Date = summary.Date.Format("2006-01-02")
+			item.Close = summary.Close
+			item.Change = summary.Change
+			item.ChangePct = summary.ChangePct
+			item.Value = summary.Value
+			item.ForeignNet = summary.ForeignBuy - summary.ForeignSell
+		}
+		result.Stocks = append(result.Stocks, item)
+	}
+	return result
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"go-stock/internal/shared/response"
+	"net/http"
+	"strings"
+)
+
+// AdminAuth only lets requests through that carry "Authorization: Bearer <token>".
+// Every request is rejected when no token is configured.
+func AdminAuth(token string) Middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				response.Forbidden(w, "admin API is disabled")
+				return
+			}
+
+			given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				response.Unauthorized(w, "")
+				return
+			}
+
+			next(w, r)
+		}
+	}
+}
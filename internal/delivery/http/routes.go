@@ -12,9 +12,14 @@ func RegisterRoutes(mux *http.ServeMux, app app.Bootstrap) {
 	cors := middleware.CORS("*")
 	log := middleware.Logger()
 
+	admin := middleware.AdminAuth(app.GetConfig().GetApplication().AdminToken)
+
 	chain := func(h http.HandlerFunc) http.HandlerFunc {
 		return middleware.Chain(h, log, cors)
 	}
+	adminChain := func(h http.HandlerFunc) http.HandlerFunc {
+		return middleware.Chain(h, log, cors, admin)
+	}
 
 	// Apply middleware to routes
 	mux.HandleFunc("/healthz", chain(app.GetHandler().HealthHandler.Healthz))
@@ -22,9 +27,49 @@ func RegisterRoutes(mux *http.ServeMux, app app.Bootstrap) {
 	mux.HandleFunc("/api/v1/stocks/search", chain(app.GetHandler().StockHandler.SearchStock))
 	mux.HandleFunc("/api/v1/stock", chain(app.GetHandler().StockHandler.FindStock))
 	mux.HandleFunc("/api/v1/stock/summaries", chain(app.GetHandler().StockSummaryHandler.FindStockSummaries))
+	mux.HandleFunc("/api/v1/stock/indicators", chain(app.GetHandler().IndicatorHandler.FindIndicators))
+	mux.HandleFunc("/api/v1/screener", chain(app.GetHandler().ScreenerHandler.Screen))
+	mux.HandleFunc("/api/v1/market/leaders", chain(app.GetHandler().MarketHandler.FindLeaders))
+	mux.HandleFunc("/api/v1/market/sectors", chain(app.GetHandler().MarketHandler.FindSectors))
+	mux.HandleFunc("/api/v1/market/sectors/{name}", chain(app.GetHandler().MarketHandler.FindSector))
+	mux.HandleFunc("/api/v1/market/breadth", chain(app.GetHandler().MarketHandler.FindBreadth))
+	mux.HandleFunc("/api/v1/market/price_limits", chain(app.GetHandler().MarketHandler.FindPriceLimits))
+	mux.HandleFunc("/api/v1/market/auto_rejections", chain(app.GetHandler().MarketHandler.FindAutoRejections))
+	mux.HandleFunc("GET /api/v1/calendar", chain(app.GetHandler().CalendarHandler.FindDays))
+	mux.HandleFunc("GET /api/v1/calendar/next", chain(app.GetHandler().CalendarHandler.NextTradingDay))
+	mux.HandleFunc("GET /api/v1/calendar/previous", chain(app.GetHandler().CalendarHandler.PreviousTradingDay))
+	mux.HandleFunc("GET /api/v1/baskets", chain(app.GetHandler().BasketHandler.ListBaskets))
+	mux.HandleFunc("POST /api/v1/baskets", chain(app.GetHandler().BasketHandler.CreateBasket))
+	mux.HandleFunc("GET /api/v1/baskets/{id}", chain(app.GetHandler().BasketHandler.FindBasket))
+	mux.HandleFunc("PUT /api/v1/baskets/{id}", chain(app.GetHandler().BasketHandler.UpdateBasket))
+	mux.HandleFunc("DELETE /api/v1/baskets/{id}", chain(app.GetHandler().BasketHandler.DeleteBasket))
+	mux.HandleFunc("GET /api/v1/baskets/{id}/index", chain(app.GetHandler().BasketHandler.FindIndex))
+	mux.HandleFunc("GET /api/v1/portfolios/{name}", chain(app.GetHandler().PortfolioHandler.FindPortfolio))
+	mux.HandleFunc("GET /api/v1/portfolios/{name}/trades", chain(app.GetHandler().PortfolioHandler.ListTrades))
+	mux.HandleFunc("POST /api/v1/portfolios/{name}/trades", chain(app.GetHandler().PortfolioHandler.RecordTrade))
+	mux.HandleFunc("DELETE /api/v1/portfolios/{name}/trades/{id}", chain(app.GetHandler().PortfolioHandler.DeleteTrade))
+	mux.HandleFunc("GET /api/v1/portfolios/{name}/values", chain(app.GetHandler().PortfolioHandler.FindValues))
+	mux.HandleFunc("GET /api/v1/watchlists", chain(app.GetHandler().WatchlistHandler.ListWatchlists))
+	mux.HandleFunc("POST /api/v1/watchlists", chain(app.GetHandler().WatchlistHandler.CreateWatchlist))
+	mux.HandleFunc("GET /api/v1/watchlists/{id}", chain(app.GetHandler().WatchlistHandler.FindWatchlist))
+	mux.HandleFunc("PUT /api/v1/watchlists/{id}", chain(app.GetHandler().WatchlistHandler.UpdateWatchlist))
+	mux.HandleFunc("DELETE /api/v1/watchlists/{id}", chain(app.GetHandler().WatchlistHandler.DeleteWatchlist))
+	mux.HandleFunc("POST /api/v1/backtests", chain(app.GetHandler().BacktestHandler.RunBacktest))
+	mux.HandleFunc("GET /api/v1/alerts", chain(app.GetHandler().AlertHandler.ListAlerts))
+	mux.HandleFunc("GET /api/v1/alerts/rules", chain(app.GetHandler().AlertHandler.ListRules))
+	mux.HandleFunc("POST /api/v1/alerts/rules", chain(app.GetHandler().AlertHandler.CreateRule))
+	mux.HandleFunc("GET /api/v1/alerts/rules/{id}", chain(app.GetHandler().AlertHandler.FindRule))
+	mux.HandleFunc("PUT /api/v1/alerts/rules/{id}", chain(app.GetHandler().AlertHandler.UpdateRule))
+	mux.HandleFunc("DELETE /api/v1/alerts/rules/{id}", chain(app.GetHandler().AlertHandler.DeleteRule))
 	mux.HandleFunc("/api/v1/brokers", chain(app.GetHandler().BrokerHandler.Find))
 	mux.HandleFunc("/api/v1/brokers/summaries", chain(app.GetHandler().BrokerSummaryHandler.Find))
 	mux.HandleFunc("/api/v1/financial_report", chain(app.GetHandler().FinancialReportHandler.FindFinancialReport))
+	mux.HandleFunc("/api/v1/jobs", chain(app.GetHandler().JobHandler.ListJobs))
+	mux.HandleFunc("/api/v1/jobs/{name}/runs", chain(app.GetHandler().JobHandler.ListJobRuns))
+
+	// Admin routes
+	mux.HandleFunc("/api/v1/admin/stock/summaries/backfill", adminChain(app.GetHandler().StockSummaryHandler.BackfillStockSummaries))
+	mux.HandleFunc("/api/v1/admin/jobs/{name}/run", adminChain(app.GetHandler().JobHandler.TriggerJob))
 
 	// Swagger & Static files
 	mux.HandleFunc("/swagger/", httpSwagger.WrapHandler.ServeHTTP)
@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+// Conditions of an alert rule, checked against the summary of a trading day.
+const (
+	// AlertPriceAbove holds while the close is at or above the threshold.
+	AlertPriceAbove = "price_above"
+	// AlertPriceBelow holds while the close is at or below the threshold.
+	AlertPriceBelow = "price_below"
+	// AlertValueSpike holds while the value traded is at least threshold times
+	// its average over the previous Period days.
+	AlertValueSpike = "value_spike"
+	// AlertForeignNetSell holds while the foreign net sell value, the net shares
+	// at the average price of the day, is at or above the threshold.
+	AlertForeignNetSell = "foreign_net_sell"
+)
+
+// AlertRule fires an alert when its condition starts to hold, and once more only
+// after the condition stopped holding on a later day.
+type AlertRule struct {
+	ID        string    `bson:"rule_id"`
+	Name      string    `bson:"name"`
+	StockCode string    `bson:"stock_code"`
+	Type      string    `bson:"type"`
+	Threshold float64   `bson:"threshold"`
+	Period    int       `bson:"period"`
+	Enabled   bool      `bson:"enabled"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+	// Triggered tells whether the condition held on the last evaluated day.
+	Triggered     bool      `bson:"triggered"`
+	LastEvaluated time.Time `bson:"last_evaluated"`
+	LastTriggered time.Time `bson:"last_triggered"`
+}
+
+// Alert is the firing of a rule on a trading day.
+type Alert struct {
+	ID        string    `bson:"alert_id"`
+	RuleID    string    `bson:"rule_id"`
+	RuleName  string    `bson:"rule_name"`
+	StockCode string    `bson:"stock_code"`
+	Type      string    `bson:"type"`
+	Date      time.Time `bson:"date"`
+	Threshold float64   `bson:"threshold"`
+	// Observed is the value compared with the threshold, e.g. the close.
+	Observed    float64   `bson:"observed"`
+	Message     string    `bson:"message"`
+	TriggeredAt time.Time `bson:"triggered_at"`
+	// Delivered tells whether every webhook accepted the alert; DeliveryError
+	// holds the last failure otherwise.
+	Delivered     bool   `bson:"delivered"`
+	DeliveryError string `bson:"delivery_error"`
+}
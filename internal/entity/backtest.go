@@ -0,0 +1,60 @@
+package entity
+
+import "time"
+
+// Reasons a backtest position was closed.
+const (
+	ExitSignal     = "signal"
+	ExitStopLoss   = "stop_loss"
+	ExitTakeProfit = "take_profit"
+	// ExitEnd closes the positions still open at the last close of the test.
+	ExitEnd = "end"
+)
+
+// BacktestTrade is a round trip of a backtest. Cost includes the buy fee and
+// PnL is the proceeds net of the sell fee minus the cost.
+type BacktestTrade struct {
+	StockCode  string
+	EntryDate  time.Time
+	EntryPrice float64
+	ExitDate   time.Time
+	ExitPrice  float64
+	ExitReason string
+	Lots       int
+	Shares     float64
+	Cost       float64
+	Fees       float64
+	PnL        float64
+	Return     float64
+}
+
+// EquityPoint is the cash and the positions at their close on a trading day.
+// Drawdown is the fall of the equity from its highest point so far.
+type EquityPoint struct {
+	Date     time.Time
+	Cash     float64
+	Equity   float64
+	Drawdown float64
+}
+
+type BacktestMetrics struct {
+	InitialCapital float64
+	FinalEquity    float64
+	TotalReturn    float64
+	CAGR           float64
+	MaxDrawdown    float64
+	// Sharpe is the annualized Sharpe ratio of the daily returns, without a
+	// risk-free rate.
+	Sharpe  float64
+	Trades  int
+	WinRate float64
+}
+
+type Backtest struct {
+	StockCodes []string
+	StartDate  time.Time
+	EndDate    time.Time
+	Metrics    BacktestMetrics
+	Trades     []BacktestTrade
+	Equity     []EquityPoint
+}
@@ -0,0 +1,50 @@
+package entity
+
+import "time"
+
+// Weightings of the constituents of a basket index. Market cap weighs a stock by
+// its listed shares, free float by its tradable shares, both at the previous close.
+const (
+	WeightingEqual     = "equal"
+	WeightingMarketCap = "market_cap"
+	WeightingFreeFloat = "free_float"
+)
+
+// Basket is a named set of stocks tracked as an index.
+type Basket struct {
+	ID         string    `bson:"basket_id"`
+	Name       string    `bson:"name"`
+	StockCodes []string  `bson:"stock_codes"`
+	Weighting  string    `bson:"weighting"`
+	CreatedAt  time.Time `bson:"created_at"`
+	UpdatedAt  time.Time `bson:"updated_at"`
+}
+
+type BasketIndex struct {
+	Basket    Basket
+	StartDate time.Time
+	EndDate   time.Time
+	BaseLevel float64
+	// Return is the return of the index over the range, e.g. 0.05 for 5%.
+	Return        float64
+	Levels        []IndexLevel
+	Contributions []IndexContribution
+}
+
+type IndexLevel struct {
+	Date  time.Time
+	Level float64
+	// Return is the return of the day.
+	Return float64
+}
+
+// IndexContribution is the part of the index return that came from one stock;
+// the contributions add up to the return of the index.
+type IndexContribution struct {
+	StockCode string
+	StockName string
+	// Weight is the weight of the stock on the last day it was traded.
+	Weight       float64
+	Return       float64
+	Contribution float64
+}
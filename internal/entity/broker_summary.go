@@ -3,24 +3,38 @@ package entity
 import "time"
 
 type BrokerSummary struct {
-	StockCode string              `bson:"stock_code"`
-	StartDate time.Time           `bson:"start_date"`
-	EndDate   time.Time           `bson:"end_date"`
-	Buyers    []BrokerSummaryData `bson:"buyers"`
-	Sellers   []BrokerSummaryData `bson:"sellers"`
-	Summary   Summary             `bson:"summary"`
+	StockCode    string              `bson:"stock_code"`
+	StartDate    time.Time           `bson:"start_date"`
+	EndDate      time.Time           `bson:"end_date"`
+	InvestorType string              `bson:"investor_type"`
+	Board        string              `bson:"board"`
+	Buyers       []BrokerSummaryData `bson:"buyers"`
+	Sellers      []BrokerSummaryData `bson:"sellers"`
+	Summary      Summary             `bson:"summary"`
+	FetchedAt    time.Time           `bson:"fetched_at"`
 }
 
+// Empty reports whether no broker traded the stock in the date range. Empty
+// summaries are stored too, so such ranges are not fetched again while fresh.
+func (s BrokerSummary) Empty() bool {
+	return len(s.Buyers) == 0 && len(s.Sellers) == 0
+}
+
+// BrokerSummaryData keeps the value as displayed by Indopremier in Val and in
+// rupiah in ValAmount.
 type BrokerSummaryData struct {
 	BrokerCode string  `bson:"broker_code"`
 	Lot        float64 `bson:"lot"`
 	Val        string  `bson:"val"`
+	ValAmount  float64 `bson:"val_amount"`
 	Avg        float64 `bson:"avg"`
 }
 
 type Summary struct {
-	TotalVal      string  `bson:"total_val"`
-	ForeignNetVal string  `bson:"foreign_net_val"`
-	TotalLot      float64 `bson:"total_lot"`
-	Avg           float64 `bson:"avg"`
+	TotalVal            string  `bson:"total_val"`
+	TotalValAmount      float64 `bson:"total_val_amount"`
+	ForeignNetVal       string  `bson:"foreign_net_val"`
+	ForeignNetValAmount float64 `bson:"foreign_net_val_amount"`
+	TotalLot            float64 `bson:"total_lot"`
+	Avg                 float64 `bson:"avg"`
 }
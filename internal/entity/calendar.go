@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+type TradingDayStatus string
+
+const (
+	// TradingDayStatusSession is a day summaries are stored for.
+	TradingDayStatusSession TradingDayStatus = "session"
+	// TradingDayStatusExpected is a weekday expected to be a session but not stored
+	// yet, e.g. today, a future day or a day before the stored history.
+	TradingDayStatusExpected TradingDayStatus = "expected"
+	TradingDayStatusWeekend  TradingDayStatus = "weekend"
+	// TradingDayStatusHoliday is a public holiday or collective leave day listed in
+	// the holiday file.
+	TradingDayStatusHoliday TradingDayStatus = "holiday"
+	// TradingDayStatusClosed is a weekday between stored sessions without
+	// summaries, inferred as a day the exchange was closed.
+	TradingDayStatusClosed TradingDayStatus = "closed"
+)
+
+// Holiday is a day the exchange is closed on besides weekends.
+type Holiday struct {
+	Date time.Time
+	Name string
+}
+
+type TradingDay struct {
+	Date   time.Time
+	Status TradingDayStatus
+	// Holiday is the name of the holiday, if any.
+	Holiday string
+}
+
+// Trading reports whether the exchange is, or is expected to be, open on the day.
+func (d TradingDay) Trading() bool {
+	return d.Status == TradingDayStatusSession || d.Status == TradingDayStatusExpected
+}
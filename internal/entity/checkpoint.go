@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// Checkpoint tracks the items a long running job already finished, so a restarted
+// run can skip them.
+type Checkpoint struct {
+	Name      string    `bson:"name"`
+	StartedAt time.Time `bson:"started_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+	Done      []string  `bson:"done"`
+}
@@ -0,0 +1,50 @@
+package entity
+
+import "time"
+
+type JobRunStatus string
+
+const (
+	JobRunStatusRunning JobRunStatus = "running"
+	JobRunStatusSuccess JobRunStatus = "success"
+	JobRunStatusFailed  JobRunStatus = "failed"
+)
+
+type JobTrigger string
+
+const (
+	JobTriggerSchedule JobTrigger = "schedule"
+	JobTriggerManual   JobTrigger = "manual"
+)
+
+type Job struct {
+	Name        string    `bson:"name"`
+	Schedule    string    `bson:"schedule"`
+	NextRun     time.Time `bson:"next_run"`
+	PreviousRun time.Time `bson:"previous_run"`
+	LastRun     *JobRun   `bson:"last_run"`
+	Running     bool      `bson:"running"`
+}
+
+type JobRun struct {
+	RunID      string        `bson:"run_id"`
+	JobName    string        `bson:"job_name"`
+	Status     JobRunStatus  `bson:"status"`
+	Trigger    JobTrigger    `bson:"trigger"`
+	Params     JobParams     `bson:"params"`
+	StartedAt  time.Time     `bson:"started_at"`
+	FinishedAt time.Time     `bson:"finished_at"`
+	Duration   time.Duration `bson:"duration"`
+	Records    int           `bson:"records"`
+	Error      string        `bson:"error"`
+}
+
+// JobParams overrides the defaults a job derives from the current date.
+type JobParams struct {
+	Date   string `bson:"date,omitempty"`
+	Period string `bson:"period,omitempty"`
+	Year   string `bson:"year,omitempty"`
+	// StartDate and EndDate bound the days of a backfill, inclusive.
+	StartDate string `bson:"start_date,omitempty"`
+	EndDate   string `bson:"end_date,omitempty"`
+}
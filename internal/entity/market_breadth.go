@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+// MarketBreadth summarizes how broadly the market moved on a trading day.
+type MarketBreadth struct {
+	Date time.Time `bson:"date"`
+	// Stocks counts the stocks with a previous close, which advance, decline or
+	// stay unchanged.
+	Stocks    int `bson:"stocks"`
+	Advancers int `bson:"advancers"`
+	Decliners int `bson:"decliners"`
+	Unchanged int `bson:"unchanged"`
+	// NewHighs and NewLows count the stocks trading beyond their range of the
+	// previous 52 weeks.
+	NewHighs int `bson:"new_highs"`
+	NewLows  int `bson:"new_lows"`
+	// AboveSMA20, AboveSMA50 and AboveSMA200 are the percentages of the stocks
+	// with enough history that closed above their simple moving average.
+	AboveSMA20  float64   `bson:"above_sma_20"`
+	AboveSMA50  float64   `bson:"above_sma_50"`
+	AboveSMA200 float64   `bson:"above_sma_200"`
+	Value       float64   `bson:"value"`
+	Volume      float64   `bson:"volume"`
+	Frequency   float64   `bson:"frequency"`
+	ComputedAt  time.Time `bson:"computed_at"`
+}
+
+// StockRange is the range of a stock over a date range. Low ignores the days
+// without trades.
+type StockRange struct {
+	StockCode    string  `bson:"stock_code"`
+	High         float64 `bson:"high"`
+	Low          float64 `bson:"low"`
+	AverageClose float64 `bson:"average_close"`
+	Days         int     `bson:"days"`
+}
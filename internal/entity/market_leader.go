@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+// Leaderboards of a trading day.
+const (
+	LeaderboardGainers        = "gainers"
+	LeaderboardLosers         = "losers"
+	LeaderboardMostValue      = "most_value"
+	LeaderboardMostVolume     = "most_volume"
+	LeaderboardMostFrequency  = "most_frequency"
+	LeaderboardForeignNetBuy  = "foreign_net_buy"
+	LeaderboardForeignNetSell = "foreign_net_sell"
+)
+
+// Metrics stock summaries are ranked by, all stored on the summaries; change
+// percent and foreign net value are derived when a summary is stored.
+const (
+	LeaderMetricChangePct       = "change_pct"
+	LeaderMetricValue           = "value"
+	LeaderMetricVolume          = "volume"
+	LeaderMetricFrequency       = "frequency"
+	LeaderMetricForeignNetValue = "foreign_net_value"
+)
+
+// LeaderFilter narrows the summaries ranked on a leaderboard.
+type LeaderFilter struct {
+	// StockCodes limits the ranking to these stocks when not empty.
+	StockCodes []string `bson:"stock_codes"`
+	MinValue   float64  `bson:"min_value"`
+	MinVolume  float64  `bson:"min_volume"`
+}
+
+type MarketLeader struct {
+	StockSummary `bson:",inline"`
+	// ForeignNet is the foreign net buy in shares.
+	ForeignNet float64 `bson:"foreign_net"`
+}
+
+type Leaderboard struct {
+	Name    string         `bson:"name"`
+	Leaders []MarketLeader `bson:"leaders"`
+}
+
+type MarketLeaders struct {
+	Date         time.Time     `bson:"date"`
+	Leaderboards []Leaderboard `bson:"leaderboards"`
+}
@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// PriceLimits checks a price against the tick size of its band and gives the
+// auto-rejection limits of the next session, around the latest stored close.
+type PriceLimits struct {
+	StockCode    string
+	Board        string
+	Price        float64
+	TickSize     float64
+	Valid        bool
+	RoundedPrice float64
+	RoundDown    float64
+	RoundUp      float64
+	// ReferenceDate is the day of the close the limits are computed from; the
+	// limits are zero when the stock has no stored summary.
+	ReferenceDate  time.Time
+	ReferenceClose float64
+	Upper          float64
+	Lower          float64
+	UpperPercent   float64
+	LowerPercent   float64
+}
+
+// AutoRejection is a summary whose price reached the upper (ARA) or the lower
+// (ARB) auto-rejection limit of its session.
+type AutoRejection struct {
+	Date      time.Time
+	StockCode string
+	StockName string
+	Board     string
+	Previous  float64
+	High      float64
+	Low       float64
+	Close     float64
+	Upper     float64
+	Lower     float64
+	// Limit is the limit reached, "ara" or "arb".
+	Limit         string
+	ClosedAtLimit bool
+}
@@ -0,0 +1,71 @@
+package entity
+
+import "time"
+
+const (
+	TradeSideBuy  = "buy"
+	TradeSideSell = "sell"
+)
+
+// Trade is a buy or sell transaction recorded in a portfolio.
+type Trade struct {
+	ID        string    `bson:"trade_id"`
+	Portfolio string    `bson:"portfolio"`
+	StockCode string    `bson:"stock_code"`
+	Date      time.Time `bson:"date"`
+	Side      string    `bson:"side"`
+	Price     float64   `bson:"price"`
+	Lots      int       `bson:"lots"`
+	// Fee is the total of the broker fee and taxes of the trade.
+	Fee       float64   `bson:"fee"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// Position is the holding of a stock, valued at its latest close. Cost is the
+// average cost of the shares held, including buy fees.
+type Position struct {
+	StockCode      string
+	StockName      string
+	Shares         float64
+	AverageCost    float64
+	Cost           float64
+	Close          float64
+	MarketValue    float64
+	UnrealizedPnL  float64
+	RealizedPnL    float64
+	DividendIncome float64
+}
+
+// DividendIncome is the cash dividend earned on the shares held before the
+// ex-date.
+type DividendIncome struct {
+	StockCode    string
+	ExDate       time.Time
+	PaymentDate  time.Time
+	CashPerShare float64
+	Shares       float64
+	Amount       float64
+}
+
+// PortfolioValue is the value of a portfolio at the close of a trading day.
+// RealizedPnL and DividendIncome are cumulative up to the day.
+type PortfolioValue struct {
+	Date           time.Time
+	MarketValue    float64
+	Cost           float64
+	UnrealizedPnL  float64
+	RealizedPnL    float64
+	DividendIncome float64
+}
+
+type Portfolio struct {
+	Name           string
+	Date           time.Time
+	MarketValue    float64
+	Cost           float64
+	UnrealizedPnL  float64
+	RealizedPnL    float64
+	DividendIncome float64
+	Positions      []Position
+	Dividends      []DividendIncome
+}
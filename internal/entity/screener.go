@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// ScreenerResult is one page of the stocks matching a screener, as of Date.
+type ScreenerResult struct {
+	Date  time.Time     `bson:"date"`
+	Total int64         `bson:"total"`
+	Rows  []ScreenerRow `bson:"rows"`
+}
+
+type ScreenerRow struct {
+	StockCode string `bson:"stock_code"`
+	StockName string `bson:"stock_name"`
+	Board     string `bson:"board"`
+	Sector    string `bson:"sector"`
+	SubSector string `bson:"sub_sector"`
+	// Values holds the numeric fields of the stock, including the indicators the
+	// screener referenced.
+	Values map[string]float64 `bson:"values"`
+}
@@ -0,0 +1,58 @@
+package entity
+
+import "time"
+
+// Groupings of stocks by the classification in their profile.
+const (
+	SectorGroupSector      = "sector"
+	SectorGroupSubSector   = "sub_sector"
+	SectorGroupIndustry    = "industry"
+	SectorGroupSubIndustry = "sub_industry"
+)
+
+// StockPeriodSummary rolls the summaries of a stock over a date range up.
+type StockPeriodSummary struct {
+	StockCode string    `bson:"stock_code"`
+	StockName string    `bson:"stock_name"`
+	FirstDate time.Time `bson:"first_date"`
+	LastDate  time.Time `bson:"last_date"`
+	// Previous is the close before the first day, Close the close of the last day.
+	Previous float64 `bson:"previous"`
+	Close    float64 `bson:"close"`
+	// Return compounds the daily close to previous changes, which IDX adjusts on
+	// the ex-date of a split, so a split within the range does not count as a loss.
+	Return float64 `bson:"return"`
+	// MarketCap is the market capitalization before the first day, which weighs
+	// the stock in its sector.
+	MarketCap       float64 `bson:"market_cap"`
+	Value           float64 `bson:"value"`
+	Volume          float64 `bson:"volume"`
+	ForeignNetValue float64 `bson:"foreign_net_value"`
+	Days            int     `bson:"days"`
+}
+
+type SectorConstituent struct {
+	StockPeriodSummary `bson:",inline"`
+	// Weight is the share of the stock in the market capitalization of its sector.
+	Weight float64 `bson:"weight"`
+}
+
+type Sector struct {
+	Name string `bson:"name"`
+	// Return is the market capitalization weighted return of the constituents.
+	Return          float64             `bson:"return"`
+	MarketCap       float64             `bson:"market_cap"`
+	Value           float64             `bson:"value"`
+	ForeignNetValue float64             `bson:"foreign_net_value"`
+	Advancers       int                 `bson:"advancers"`
+	Decliners       int                 `bson:"decliners"`
+	Unchanged       int                 `bson:"unchanged"`
+	Constituents    []SectorConstituent `bson:"constituents"`
+}
+
+type SectorOverview struct {
+	Group     string    `bson:"group"`
+	StartDate time.Time `bson:"start_date"`
+	EndDate   time.Time `bson:"end_date"`
+	Sectors   []Sector  `bson:"sectors"`
+}
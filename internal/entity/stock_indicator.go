@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// StockIndicators holds indicator series aligned with Dates.
+type StockIndicators struct {
+	StockCode string            `bson:"stock_code"`
+	Dates     []time.Time       `bson:"dates"`
+	Close     []float64         `bson:"close"`
+	Series    []IndicatorSeries `bson:"series"`
+}
+
+type IndicatorSeries struct {
+	Name  string          `bson:"name"`
+	Lines []IndicatorLine `bson:"lines"`
+}
+
+// IndicatorLine is one output of an indicator; a value is nil while the indicator
+// warms up.
+type IndicatorLine struct {
+	Name   string     `bson:"name"`
+	Values []*float64 `bson:"values"`
+}
@@ -2,36 +2,89 @@ package entity
 
 import "time"
 
+// Intervals stock summaries can be resampled to; stored summaries are daily.
+const (
+	IntervalDaily   = "daily"
+	IntervalWeekly  = "weekly"
+	IntervalMonthly = "monthly"
+	IntervalYearly  = "yearly"
+)
+
 type StockSummary struct {
-	IDStockSummary      int         `bson:"id_stock_summary"`
-	Date                time.Time   `bson:"date"`
-	StockCode           string      `bson:"stock_code"`
-	StockName           string      `bson:"stock_name"`
-	Remarks             string      `bson:"remarks"`
-	Previous            float64     `bson:"previous"`
-	OpenPrice           float64     `bson:"open_price"`
-	FirstTrade          float64     `bson:"first_trade"`
-	High                float64     `bson:"high"`
-	Low                 float64     `bson:"low"`
-	Close               float64     `bson:"close"`
-	Change              float64     `bson:"change"`
-	Volume              float64     `bson:"volume"`
-	Value               float64     `bson:"value"`
-	Frequency           float64     `bson:"frequency"`
-	IndexIndividual     float64     `bson:"index_individual"`
-	Offer               float64     `bson:"offer"`
-	OfferVolume         float64     `bson:"offer_volume"`
-	Bid                 float64     `bson:"bid"`
-	BidVolume           float64     `bson:"bid_volume"`
-	ListedShares        float64     `bson:"listed_shares"`
-	TradebleShares      float64     `bson:"tradeble_shares"`
-	WeightForIndex      float64     `bson:"weight_for_index"`
-	ForeignSell         float64     `bson:"foreign_sell"`
-	ForeignBuy          float64     `bson:"foreign_buy"`
-	DelistingDate       string      `bson:"delisting_date"`
-	NonRegularVolume    float64     `bson:"non_regular_volume"`
-	NonRegularValue     float64     `bson:"non_regular_value"`
-	NonRegularFrequency float64     `bson:"non_regular_frequency"`
-	Persen              interface{} `bson:"persen"`
-	Percentage          interface{} `bson:"percentage"`
+	IDStockSummary      int       `bson:"id_stock_summary"`
+	Date                time.Time `bson:"date"`
+	StockCode           string    `bson:"stock_code"`
+	StockName           string    `bson:"stock_name"`
+	Remarks             string    `bson:"remarks"`
+	Previous            float64   `bson:"previous"`
+	OpenPrice           float64   `bson:"open_price"`
+	FirstTrade          float64   `bson:"first_trade"`
+	High                float64   `bson:"high"`
+	Low                 float64   `bson:"low"`
+	Close               float64   `bson:"close"`
+	Change              float64   `bson:"change"`
+	Volume              float64   `bson:"volume"`
+	Value               float64   `bson:"value"`
+	Frequency           float64   `bson:"frequency"`
+	IndexIndividual     float64   `bson:"index_individual"`
+	Offer               float64   `bson:"offer"`
+	OfferVolume         float64   `bson:"offer_volume"`
+	Bid                 float64   `bson:"bid"`
+	BidVolume           float64   `bson:"bid_volume"`
+	ListedShares        float64   `bson:"listed_shares"`
+	TradebleShares      float64   `bson:"tradeble_shares"`
+	WeightForIndex      float64   `bson:"weight_for_index"`
+	ForeignSell         float64   `bson:"foreign_sell"`
+	ForeignBuy          float64   `bson:"foreign_buy"`
+	DelistingDate       string    `bson:"delisting_date"`
+	NonRegularVolume    float64   `bson:"non_regular_volume"`
+	NonRegularValue     float64   `bson:"non_regular_value"`
+	NonRegularFrequency float64   `bson:"non_regular_frequency"`
+	Persen              float64   `bson:"persen"`
+	Percentage          float64   `bson:"percentage"`
+
+	// Metrics derived from the fields above, see DeriveMetrics.
+	ChangePct float64 `bson:"change_pct"`
+	// ForeignNetValue is the foreign net buy valued at the average price of the day.
+	ForeignNetValue float64 `bson:"foreign_net_value"`
+	// TypicalPrice is the average of the high, low and close.
+	TypicalPrice float64 `bson:"typical_price"`
+	// Spread is the best offer minus the best bid, zero without both.
+	Spread float64 `bson:"spread"`
+	// TurnoverRatio is the fraction of the tradable shares traded.
+	TurnoverRatio float64 `bson:"turnover_ratio"`
+	MarketCap     float64 `bson:"market_cap"`
+}
+
+// DeriveMetrics computes the derived metrics from the prices, flows and share
+// counts of the summary. Metrics without a base, e.g. the change of a stock
+// without a previous close, are zero.
+func (s *StockSummary) DeriveMetrics() {
+	s.ChangePct = 0
+	if s.Previous > 0 {
+		s.ChangePct = (s.Close - s.Previous) / s.Previous * 100
+	}
+
+	s.ForeignNetValue = 0
+	if s.Volume > 0 {
+		s.ForeignNetValue = (s.ForeignBuy - s.ForeignSell) * s.Value / s.Volume
+	}
+
+	// A day without trades has no high or low.
+	s.TypicalPrice = s.Close
+	if s.High > 0 && s.Low > 0 {
+		s.TypicalPrice = (s.High + s.Low + s.Close) / 3
+	}
+
+	s.Spread = 0
+	if s.Offer > 0 && s.Bid > 0 {
+		s.Spread = s.Offer - s.Bid
+	}
+
+	s.TurnoverRatio = 0
+	if s.TradebleShares > 0 {
+		s.TurnoverRatio = s.Volume / s.TradebleShares
+	}
+
+	s.MarketCap = s.ListedShares * s.Close
 }
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+type BackfillStatus string
+
+const (
+	BackfillStatusSuccess BackfillStatus = "success"
+	BackfillStatusEmpty   BackfillStatus = "empty"
+	BackfillStatusSkipped BackfillStatus = "skipped"
+	BackfillStatusFailed  BackfillStatus = "failed"
+)
+
+type BackfillResult struct {
+	Date    time.Time      `bson:"date"`
+	Status  BackfillStatus `bson:"status"`
+	Records int            `bson:"records"`
+	Reason  string         `bson:"reason"`
+}
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// Watchlist is a named list of stocks saved by a user.
+type Watchlist struct {
+	ID         string    `bson:"watchlist_id"`
+	UserID     string    `bson:"user_id"`
+	Name       string    `bson:"name"`
+	StockCodes []string  `bson:"stock_codes"`
+	CreatedAt  time.Time `bson:"created_at"`
+	UpdatedAt  time.Time `bson:"updated_at"`
+	// Stocks are the stocks of the list with their latest summary, in the order
+	// of StockCodes; they are not stored.
+	Stocks []WatchlistStock `bson:"-"`
+}
+
+type WatchlistStock struct {
+	StockCode string
+	// Summary is nil when the stock has no stored summary.
+	Summary *StockSummary
+}
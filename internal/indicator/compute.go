@@ -0,0 +1,178 @@
+package indicator
+
+import "math"
+
+// firstValid returns the index of the first value that is not NaN, or len(values).
+func firstValid(values []float64) int {
+	for i, value := range values {
+		if !math.IsNaN(value) {
+			return i
+		}
+	}
+	return len(values)
+}
+
+// sma is the simple moving average; the first period-1 values are missing.
+func sma(values []float64, period int) []float64 {
+	result := missing(len(values))
+	start := firstValid(values)
+
+	sum := 0.0
+	for i := start; i < len(values); i++ {
+		sum += values[i]
+		if i-start >= period {
+			sum -= values[i-period]
+		}
+		if i-start >= period-1 {
+			result[i] = sum / float64(period)
+		}
+	}
+	return result
+}
+
+// smooth is an exponential moving average with factor alpha, seeded with the
+// simple average of the first period values.
+func smooth(values []float64, period int, alpha float64) []float64 {
+	result := missing(len(values))
+	start := firstValid(values)
+	if len(values)-start < period {
+		return result
+	}
+
+	sum := 0.0
+	for i := start; i < start+period; i++ {
+		sum += values[i]
+	}
+	previous := sum / float64(period)
+	result[start+period-1] = previous
+
+	for i := start + period; i < len(values); i++ {
+		previous += alpha * (values[i] - previous)
+		result[i] = previous
+	}
+	return result
+}
+
+func ema(values []float64, period int) []float64 {
+	return smooth(values, period, 2/float64(period+1))
+}
+
+// wilder is Wilder's smoothing used by RSI and ATR.
+func wilder(values []float64, period int) []float64 {
+	return smooth(values, period, 1/float64(period))
+}
+
+// rsi is Wilder's relative strength index; the first period values are missing.
+func rsi(values []float64, period int) []float64 {
+	gains := missing(len(values))
+	losses := missing(len(values))
+	for i := 1; i < len(values); i++ {
+		change := values[i] - values[i-1]
+		gains[i] = max(change, 0)
+		losses[i] = max(-change, 0)
+	}
+
+	averageGains := wilder(gains, period)
+	averageLosses := wilder(losses, period)
+
+	result := missing(len(values))
+	for i := range values {
+		gain, loss := averageGains[i], averageLosses[i]
+		switch {
+		case math.IsNaN(gain):
+		case gain == 0 && loss == 0:
+			result[i] = 50
+		case loss == 0:
+			result[i] = 100
+		default:
+			result[i] = 100 - 100/(1+gain/loss)
+		}
+	}
+	return result
+}
+
+// macd returns the MACD line, its signal line and their difference.
+func macd(values []float64, fast, slow, signal int) [][]float64 {
+	fastEMA := ema(values, fast)
+	slowEMA := ema(values, slow)
+
+	line := missing(len(values))
+	for i := range values {
+		if !math.IsNaN(slowEMA[i]) {
+			line[i] = fastEMA[i] - slowEMA[i]
+		}
+	}
+
+	signalLine := ema(line, signal)
+	histogram := missing(len(values))
+	for i := range values {
+		if !math.IsNaN(signalLine[i]) {
+			histogram[i] = line[i] - signalLine[i]
+		}
+	}
+	return [][]float64{line, signalLine, histogram}
+}
+
+// bollinger returns the bands deviations standard deviations around the simple
+// moving average.
+func bollinger(values []float64, period int, deviations float64) [][]float64 {
+	middle := sma(values, period)
+	upper := missing(len(values))
+	lower := missing(len(values))
+
+	for i := range values {
+		if math.IsNaN(middle[i]) {
+			continue
+		}
+		variance := 0.0
+		for _, value := range values[i-period+1 : i+1] {
+			variance += (value - middle[i]) * (value - middle[i])
+		}
+		deviation := deviations * math.Sqrt(variance/float64(period))
+		upper[i] = middle[i] + deviation
+		lower[i] = middle[i] - deviation
+	}
+	return [][]float64{upper, middle, lower}
+}
+
+// atr is Wilder's average true range. The true range needs the previous close,
+// so the first bar has none and the first period values are missing.
+func atr(bars []Bar, period int) []float64 {
+	trueRanges := missing(len(bars))
+	for i := 1; i < len(bars); i++ {
+		previousClose := bars[i-1].Close
+		trueRanges[i] = max(
+			bars[i].High-bars[i].Low,
+			math.Abs(bars[i].High-previousClose),
+			math.Abs(bars[i].Low-previousClose),
+		)
+	}
+	return wilder(trueRanges, period)
+}
+
+// vwap is the volume weighted average price over the last period bars.
+func vwap(bars []Bar, period int) []float64 {
+	result := missing(len(bars))
+
+	values := make([]float64, len(bars))
+	for i, bar := range bars {
+		values[i] = bar.Value
+		if values[i] == 0 {
+			values[i] = (bar.High + bar.Low + bar.Close) / 3 * bar.Volume
+		}
+	}
+
+	value, volume := 0.0, 0.0
+	for i, bar := range bars {
+		value += values[i]
+		volume += bar.Volume
+		if i >= period {
+			value -= values[i-period]
+			volume -= bars[i-period].Volume
+		}
+		if i >= period-1 && volume > 0 {
+			result[i] = value / volume
+		}
+	}
+	return result
+}
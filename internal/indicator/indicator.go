@@ -0,0 +1,296 @@
+// Package indicator computes technical indicators over daily bars.
+//
+// Every series is aligned with the bars it is computed from. Values are nil until
+// the indicator has seen enough bars; Lookback tells how many bars to load before
+// the first wanted date for the values to be stable from that date on.
+package indicator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxPeriod bounds the periods of an indicator, which bounds the history loaded
+// for its warm-up.
+const MaxPeriod = 500
+
+type Bar struct {
+	Date   time.Time
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+	// Value is the traded value; VWAP falls back to the typical price without it.
+	Value float64
+}
+
+// Spec is an indicator with its parameters, written as "name:param:param".
+type Spec struct {
+	Name   string
+	Params []float64
+}
+
+func (s Spec) String() string {
+	parts := []string{s.Name}
+	for _, param := range s.Params {
+		parts = append(parts, strconv.FormatFloat(param, 'f', -1, 64))
+	}
+	return strings.Join(parts, ":")
+}
+
+// Line is one output of an indicator, e.g. the signal line of MACD.
+type Line struct {
+	Name   string
+	Values []*float64
+}
+
+type Series struct {
+	Spec  Spec
+	Lines []Line
+}
+
+type definition struct {
+	defaults []float64
+	lines    []string
+	validate func(params []float64) error
+	lookback func(params []float64) int
+	compute  func(bars []Bar, params []float64) [][]float64
+}
+
+var definitions = map[string]definition{
+	"sma": {
+		defaults: []float64{20},
+		lines:    []string{"value"},
+		validate: periods(1),
+		lookback: func(p []float64) int { return int(p[0]) - 1 },
+		compute: func(bars []Bar, p []float64) [][]float64 {
+			return [][]float64{sma(closes(bars), int(p[0]))}
+		},
+	},
+	"ema": {
+		defaults: []float64{20},
+		lines:    []string{"value"},
+		validate: periods(1),
+		lookback: func(p []float64) int { return emaLookback(int(p[0])) },
+		compute: func(bars []Bar, p []float64) [][]float64 {
+			return [][]float64{ema(closes(bars), int(p[0]))}
+		},
+	},
+	"rsi": {
+		defaults: []float64{14},
+		lines:    []string{"value"},
+		validate: periods(1),
+		lookback: func(p []float64) int { return wilderLookback(int(p[0])) },
+		compute: func(bars []Bar, p []float64) [][]float64 {
+			return [][]float64{rsi(closes(bars), int(p[0]))}
+		},
+	},
+	"macd": {
+		defaults: []float64{12, 26, 9},
+		lines:    []string{"macd", "signal", "histogram"},
+		validate: func(p []float64) error {
+			if err := periods(3)(p); err != nil {
+				return err
+			}
+			if p[0] >= p[1] {
+				return fmt.Errorf("fast period %v must be shorter than slow period %v", p[0], p[1])
+			}
+			return nil
+		},
+		lookback: func(p []float64) int { return emaLookback(int(p[1])) + emaLookback(int(p[2])) },
+		compute: func(bars []Bar, p []float64) [][]float64 {
+			return macd(closes(bars), int(p[0]), int(p[1]), int(p[2]))
+		},
+	},
+	"bb": {
+		defaults: []float64{20, 2},
+		lines:    []string{"upper", "middle", "lower"},
+		validate: func(p []float64) error {
+			if err := periods(1)(p[:1]); err != nil {
+				return err
+			}
+			if p[1] <= 0 {
+				return fmt.Errorf("standard deviations %v must be positive", p[1])
+			}
+			return nil
+		},
+		lookback: func(p []float64) int { return int(p[0]) - 1 },
+		compute: func(bars []Bar, p []float64) [][]float64 {
+			return bollinger(closes(bars), int(p[0]), p[1])
+		},
+	},
+	"atr": {
+		defaults: []float64{14},
+		lines:    []string{"value"},
+		validate: periods(1),
+		lookback: func(p []float64) int { return wilderLookback(int(p[0])) },
+		compute: func(bars []Bar, p []float64) [][]float64 {
+			return [][]float64{atr(bars, int(p[0]))}
+		},
+	},
+	"vwap": {
+		defaults: []float64{20},
+		lines:    []string{"value"},
+		validate: periods(1),
+		lookback: func(p []float64) int { return int(p[0]) - 1 },
+		compute: func(bars []Bar, p []float64) [][]float64 {
+			return [][]float64{vwap(bars, int(p[0]))}
+		},
+	},
+}
+
+var aliases = map[string]string{
+	"bollinger": "bb",
+}
+
+// ParseSpecs parses a comma separated list such as "rsi:14,sma:50,macd". Omitted
+// parameters take their defaults and repeated indicators are dropped.
+func ParseSpecs(value string) ([]Spec, error) {
+	var specs []Spec
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item == "" {
+			continue
+		}
+
+		parts := strings.Split(item, ":")
+		name := parts[0]
+		if alias, ok := aliases[name]; ok {
+			name = alias
+		}
+		def, ok := definitions[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown indicator %q", parts[0])
+		}
+		if len(parts)-1 > len(def.defaults) {
+			return nil, fmt.Errorf("%s takes at most %d parameter(s)", name, len(def.defaults))
+		}
+
+		params := append([]float64(nil), def.defaults...)
+		for i, part := range parts[1:] {
+			param, err := strconv.ParseFloat(part, 64)
+			if err != nil || math.IsNaN(param) || math.IsInf(param, 0) {
+				return nil, fmt.Errorf("%s: invalid parameter %q", name, part)
+			}
+			params[i] = param
+		}
+		if err := def.validate(params); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		spec := Spec{Name: name, Params: params}
+		if !seen[spec.String()] {
+			seen[spec.String()] = true
+			specs = append(specs, spec)
+		}
+	}
+
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no indicator given")
+	}
+	return specs, nil
+}
+
+// Lookback returns the number of bars to load before the first wanted date so the
+// indicator is stable there. Smoothed indicators need more than their period for
+// the seed to fade out.
+func Lookback(specs ...Spec) int {
+	lookback := 0
+	for _, spec := range specs {
+		lookback = max(lookback, definitions[spec.Name].lookback(spec.Params))
+	}
+	return lookback
+}
+
+// Lines returns the names of the lines the indicator computes, in the order of
+// the lines of its series.
+func Lines(spec Spec) []string {
+	return definitions[spec.Name].lines
+}
+
+// Compute calculates the indicator over bars, which must be ordered by date.
+func Compute(bars []Bar, spec Spec) (Series, error) {
+	def, ok := definitions[spec.Name]
+	if !ok {
+		return Series{}, fmt.Errorf("unknown indicator %q", spec.Name)
+	}
+
+	series := Series{Spec: spec}
+	for i, values := range def.compute(bars, spec.Params) {
+		series.Lines = append(series.Lines, Line{
+			Name:   def.lines[i],
+			Values: pointers(values),
+		})
+	}
+	return series, nil
+}
+
+// Trim drops the first n values of every line, i.e. the warm-up bars.
+func (s Series) Trim(n int) Series {
+	trimmed := Series{Spec: s.Spec}
+	for _, line := range s.Lines {
+		trimmed.Lines = append(trimmed.Lines, Line{
+			Name:   line.Name,
+			Values: line.Values[min(n, len(line.Values)):],
+		})
+	}
+	return trimmed
+}
+
+// periods validates that the first n parameters are whole periods within bounds.
+func periods(n int) func(params []float64) error {
+	return func(params []float64) error {
+		for _, param := range params[:n] {
+			if param < 1 || param > MaxPeriod || param != math.Trunc(param) {
+				return fmt.Errorf("period %v must be a whole number between 1 and %d", param, MaxPeriod)
+			}
+		}
+		return nil
+	}
+}
+
+// converge returns the bars after which the seed of a smoothing with factor alpha
+// weighs less than 1%.
+func converge(alpha float64) int {
+	return int(math.Ceil(math.Log(100) / alpha))
+}
+
+func emaLookback(period int) int {
+	return period - 1 + converge(2/float64(period+1))
+}
+
+func wilderLookback(period int) int {
+	return period + converge(1/float64(period))
+}
+
+func closes(bars []Bar) []float64 {
+	values := make([]float64, len(bars))
+	for i, bar := range bars {
+		values[i] = bar.Close
+	}
+	return values
+}
+
+// pointers maps NaN, the internal marker of a missing value, to nil.
+func pointers(values []float64) []*float64 {
+	result := make([]*float64, len(values))
+	for i, value := range values {
+		if !math.IsNaN(value) {
+			result[i] = &value
+		}
+	}
+	return result
+}
+
+func missing(n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = math.NaN()
+	}
+	return values
+}
@@ -0,0 +1,172 @@
+package indicator
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func barsOf(closes ...float64) []Bar {
+	bars := make([]Bar, len(closes))
+	for i, c := range closes {
+		bars[i] = Bar{Open: c, High: c, Low: c, Close: c, Volume: 1}
+	}
+	return bars
+}
+
+func compute(t *testing.T, bars []Bar, spec string) Series {
+	t.Helper()
+	specs, err := ParseSpecs(spec)
+	if err != nil {
+		t.Fatalf("ParseSpecs(%q): %v", spec, err)
+	}
+	series, err := Compute(bars, specs[0])
+	if err != nil {
+		t.Fatalf("Compute(%q): %v", spec, err)
+	}
+	return series
+}
+
+// assertLine compares a line with want, NaN standing for a missing value.
+func assertLine(t *testing.T, line Line, want ...float64) {
+	t.Helper()
+	if len(line.Values) != len(want) {
+		t.Fatalf("%s has %d values, want %d", line.Name, len(line.Values), len(want))
+	}
+	for i, value := range line.Values {
+		switch {
+		case math.IsNaN(want[i]) && value != nil:
+			t.Errorf("%s[%d] = %v, want missing", line.Name, i, *value)
+		case !math.IsNaN(want[i]) && value == nil:
+			t.Errorf("%s[%d] is missing, want %v", line.Name, i, want[i])
+		case value != nil && math.Abs(*value-want[i]) > 1e-9:
+			t.Errorf("%s[%d] = %v, want %v", line.Name, i, *value, want[i])
+		}
+	}
+}
+
+var nan = math.NaN()
+
+func TestSMA(t *testing.T) {
+	series := compute(t, barsOf(1, 2, 3, 4, 5), "sma:3")
+	assertLine(t, series.Lines[0], nan, nan, 2, 3, 4)
+}
+
+func TestEMA(t *testing.T) {
+	// Seeded with the SMA of the first 3 closes, then smoothed by 2/(3+1).
+	series := compute(t, barsOf(1, 2, 3, 4, 5), "ema:3")
+	assertLine(t, series.Lines[0], nan, nan, 2, 3, 4)
+
+	series = compute(t, barsOf(2, 4, 6, 2), "ema:3")
+	assertLine(t, series.Lines[0], nan, nan, 4, 3)
+}
+
+func TestRSI(t *testing.T) {
+	series := compute(t, barsOf(1, 2, 3, 2, 3), "rsi:2")
+	assertLine(t, series.Lines[0], nan, nan, 100, 50, 75)
+
+	series = compute(t, barsOf(5, 5, 5), "rsi:2")
+	assertLine(t, series.Lines[0], nan, nan, 50)
+}
+
+func TestMACD(t *testing.T) {
+	closes := make([]float64, 40)
+	for i := range closes {
+		closes[i] = 100 + float64(i%7)
+	}
+	series := compute(t, barsOf(closes...), "macd:3:6:4")
+
+	macdLine, signal, histogram := series.Lines[0], series.Lines[1], series.Lines[2]
+	if macdLine.Name != "macd" || signal.Name != "signal" || histogram.Name != "histogram" {
+		t.Fatalf("lines = %s %s %s", macdLine.Name, signal.Name, histogram.Name)
+	}
+	// The MACD line starts with the slow EMA, the signal needs 4 more MACD values.
+	if firstValue(macdLine) != 5 || firstValue(signal) != 8 || firstValue(histogram) != 8 {
+		t.Errorf("first values at %d, %d, %d; want 5, 8, 8", firstValue(macdLine), firstValue(signal), firstValue(histogram))
+	}
+	for i := 8; i < len(closes); i++ {
+		if diff := *macdLine.Values[i] - *signal.Values[i]; math.Abs(diff-*histogram.Values[i]) > 1e-9 {
+			t.Errorf("histogram[%d] = %v, want %v", i, *histogram.Values[i], diff)
+		}
+	}
+}
+
+func TestBollinger(t *testing.T) {
+	series := compute(t, barsOf(1, 3, 1, 3), "bollinger:2:1")
+	assertLine(t, series.Lines[0], nan, 3, 3, 3)
+	assertLine(t, series.Lines[1], nan, 2, 2, 2)
+	assertLine(t, series.Lines[2], nan, 1, 1, 1)
+}
+
+func TestATR(t *testing.T) {
+	bars := []Bar{
+		{High: 10, Low: 8, Close: 9},
+		{High: 11, Low: 9, Close: 10},  // true range 2
+		{High: 14, Low: 12, Close: 13}, // gap up, true range 14-10 = 4
+		{High: 13, Low: 12, Close: 12}, // true range 1
+	}
+	series := compute(t, bars, "atr:2")
+	assertLine(t, series.Lines[0], nan, nan, 3, 2)
+}
+
+func TestVWAP(t *testing.T) {
+	bars := []Bar{
+		{Close: 10, Volume: 100, Value: 1000},
+		{Close: 20, Volume: 300, Value: 6000},
+		{High: 30, Low: 30, Close: 30, Volume: 100}, // no value, typical price
+	}
+	series := compute(t, bars, "vwap:2")
+	assertLine(t, series.Lines[0], nan, 17.5, 22.5)
+}
+
+func TestTrim(t *testing.T) {
+	series := compute(t, barsOf(1, 2, 3, 4, 5), "sma:3").Trim(3)
+	assertLine(t, series.Lines[0], 3, 4)
+}
+
+func TestParseSpecs(t *testing.T) {
+	specs, err := ParseSpecs(" RSI:14, sma:50,macd,bollinger:20:2.5, rsi:14 ")
+	if err != nil {
+		t.Fatalf("ParseSpecs: %v", err)
+	}
+
+	var names []string
+	for _, spec := range specs {
+		names = append(names, spec.String())
+	}
+	if got := strings.Join(names, ","); got != "rsi:14,sma:50,macd:12:26:9,bb:20:2.5" {
+		t.Errorf("specs = %s", got)
+	}
+}
+
+func TestParseSpecsErrors(t *testing.T) {
+	for _, value := range []string{"", "foo:3", "sma:0", "sma:2.5", "sma:501", "sma:x", "rsi:14:2", "macd:26:12", "bb:20:0"} {
+		if _, err := ParseSpecs(value); err == nil {
+			t.Errorf("ParseSpecs(%q) succeeded, want an error", value)
+		}
+	}
+}
+
+func TestLookback(t *testing.T) {
+	sma50, _ := ParseSpecs("sma:50")
+	rsi14, _ := ParseSpecs("rsi:14")
+	if got := Lookback(sma50...); got != 49 {
+		t.Errorf("sma:50 lookback = %d, want 49", got)
+	}
+	// RSI needs 14 changes plus the bars for the Wilder seed to fade below 1%.
+	if got := Lookback(rsi14...); got != 14+65 {
+		t.Errorf("rsi:14 lookback = %d, want 79", got)
+	}
+	if got := Lookback(append(sma50, rsi14...)...); got != 79 {
+		t.Errorf("combined lookback = %d, want the longest", got)
+	}
+}
+
+func firstValue(line Line) int {
+	for i, value := range line.Values {
+		if value != nil {
+			return i
+		}
+	}
+	return -1
+}
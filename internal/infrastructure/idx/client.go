@@ -28,9 +28,12 @@ type idxClient struct {
 }
 
 type Config struct {
-	BaseURL string
-	Delay   time.Duration
-	Path    Path
+	BaseURL        string
+	Delay          time.Duration
+	RateLimit      rest.RateLimit
+	Retry          rest.RetryPolicy
+	CircuitBreaker rest.CircuitBreakerConfig
+	Path           Path
 }
 
 type Path struct {
@@ -49,6 +52,9 @@ func NewIdxClient(cfg Config, client *http.Client) IdxClient {
 		WithHeader("Content-Type", "application/json").
 		WithHeader("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/136.0.0.0 Safari/537.36").
 		WithDelay(cfg.Delay).
+		WithRateLimit(cfg.RateLimit).
+		WithRetryPolicy(cfg.Retry).
+		WithCircuitBreaker(cfg.CircuitBreaker).
 		WithHTTPClient(client).
 		Build()
 
@@ -63,12 +69,13 @@ func NewIdxClient(cfg Config, client *http.Client) IdxClient {
 }
 
 func (c *idxClient) GetStockList(ctx context.Context) (*StockListResponse, error) {
-	respBody, statusCode, err := c.restClient.SendRequest(ctx, "GET", c.stockListPath, nil, nil)
+	var attempts int
+	respBody, statusCode, err := c.restClient.SendRequest(ctx, "GET", c.stockListPath, nil, nil, rest.WithAttemptCount(&attempts))
 	if err != nil {
 		return nil, fmt.Errorf("error calling stock list endpoint: %w", err)
 	}
 	if statusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", statusCode)
+		return nil, fmt.Errorf("unexpected status code: %d after %d attempt(s)", statusCode, attempts)
 	}
 
 	var result StockListResponse
@@ -80,12 +87,13 @@ func (c *idxClient) GetStockList(ctx context.Context) (*StockListResponse, error
 
 func (c *idxClient) GetStockSummaryList(ctx context.Context, date string) (*StockSummaryListResponse, error) {
 	path := strings.ReplaceAll(c.stockSummaryListPath, "{DATE}", date)
-	respBody, statusCode, err := c.restClient.SendRequest(ctx, "GET", path, nil, nil)
+	var attempts int
+	respBody, statusCode, err := c.restClient.SendRequest(ctx, "GET", path, nil, nil, rest.WithAttemptCount(&attempts))
 	if err != nil {
 		return nil, fmt.Errorf("error calling stock summary list endpoint: %w", err)
 	}
 	if statusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", statusCode)
+		return nil, fmt.Errorf("unexpected status code: %d after %d attempt(s)", statusCode, attempts)
 	}
 
 	var result StockSummaryListResponse
@@ -96,12 +104,13 @@ func (c *idxClient) GetStockSummaryList(ctx context.Context, date string) (*Stoc
 }
 
 func (c *idxClient) GetBrokerList(ctx context.Context) (*BrokerListResponse, error) {
-	respBody, statusCode, err := c.restClient.SendRequest(ctx, "GET", c.brokerListPath, nil, nil)
+	var attempts int
+	respBody, statusCode, err := c.restClient.SendRequest(ctx, "GET", c.brokerListPath, nil, nil, rest.WithAttemptCount(&attempts))
 	if err != nil {
 		return nil, fmt.Errorf("error calling stock list endpoint: %w", err)
 	}
 	if statusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", statusCode)
+		return nil, fmt.Errorf("unexpected status code: %d after %d attempt(s)", statusCode, attempts)
 	}
 
 	var result BrokerListResponse
@@ -113,12 +122,13 @@ func (c *idxClient) GetBrokerList(ctx context.Context) (*BrokerListResponse, err
 
 func (c *idxClient) GetCompanyProfile(ctx context.Context, code string) (*CompanyProfileResponse, error) {
 	path := strings.ReplaceAll(c.companyProfilePath, "{CODE}", code)
-	respBody, statusCode, err := c.restClient.SendRequest(ctx, "GET", path, nil, nil)
+	var attempts int
+	respBody, statusCode, err := c.restClient.SendRequest(ctx, "GET", path, nil, nil, rest.WithAttemptCount(&attempts))
 	if err != nil {
 		return nil, fmt.Errorf("error calling company profile endpoint: %w", err)
 	}
 	if statusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", statusCode)
+		return nil, fmt.Errorf("unexpected status code: %d after %d attempt(s)", statusCode, attempts)
 	}
 
 	var result CompanyProfileResponse
@@ -131,12 +141,13 @@ func (c *idxClient) GetCompanyProfile(ctx context.Context, code string) (*Compan
 func (c *idxClient) GetFinancialReports(ctx context.Context, period string, year string) (*FinancialReportResponse, error) {
 	path := strings.ReplaceAll(c.financialReportPath, "{PERIOD}", period)
 	path = strings.ReplaceAll(path, "{YEAR}", year)
-	respBody, statusCode, err := c.restClient.SendRequest(ctx, "GET", path, nil, nil)
+	var attempts int
+	respBody, statusCode, err := c.restClient.SendRequest(ctx, "GET", path, nil, nil, rest.WithAttemptCount(&attempts))
 	if err != nil {
 		return nil, fmt.Errorf("error calling financial report endpoint: %w", err)
 	}
 	if statusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", statusCode)
+		return nil, fmt.Errorf("unexpected status code: %d after %d attempt(s)", statusCode, attempts)
 	}
 
 	var result FinancialReportResponse
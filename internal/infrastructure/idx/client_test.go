@@ -0,0 +1,163 @@
+package idx
+
+import (
+	"context"
+	"encoding/json"
+	"go-stock/internal/shared/rest/recorder"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// newTestClient replays the responses in testdata/fixtures. They are synthetic,
+// written by hand with consistent prices, volumes and values. Set
+// GOSTOCK_HTTP_FIXTURES=record to replace them with live responses from idx.co.id.
+func newTestClient(t *testing.T) IdxClient {
+	t.Helper()
+
+	return NewIdxClient(Config{
+		BaseURL: "https://www.idx.co.id",
+		Path: Path{
+			StockList:        "/primary/StockData/GetSecuritiesStock?start=0&length=9999&code=&sector=&board=&language=id-id",
+			StockSummaryList: "/primary/TradingSummary/GetStockSummary?length=9999&start=0&date={DATE}",
+			BrokerList:       "/primary/ExchangeMember/GetBrokerSearch?option=0&license=&start=0&length=9999&language=id-id",
+			CompanyProfile:   "/primary/ListedCompany/GetCompanyProfilesDetail?KodeEmiten={CODE}",
+			FinancialReport:  "/primary/ListedCompany/GetFinancialReport?periode={PERIOD}&year={YEAR}&indexFrom=0&pageSize=1000&reportType=rdf",
+		},
+	}, &http.Client{Transport: recorder.NewTransport(recorder.ModeFromEnv(), "testdata/fixtures", nil)})
+}
+
+func TestGetStockList(t *testing.T) {
+	result, err := newTestClient(t).GetStockList(context.Background())
+	if err != nil {
+		t.Fatalf("GetStockList: %v", err)
+	}
+
+	if len(result.StockListData) != result.RecordsTotal || len(result.StockListData) == 0 {
+		t.Fatalf("got %d stocks, want recordsTotal %d", len(result.StockListData), result.RecordsTotal)
+	}
+	stock := result.StockListData[0]
+	if stock.Code != "BBCA" || stock.Board != "Utama" || stock.Share <= 0 || stock.ListingDate == "" {
+		t.Errorf("first stock = %+v, want a complete BBCA listing", stock)
+	}
+}
+
+func TestGetStockSummaryList(t *testing.T) {
+	result, err := newTestClient(t).GetStockSummaryList(context.Background(), "20250102")
+	if err != nil {
+		t.Fatalf("GetStockSummaryList: %v", err)
+	}
+
+	if len(result.StockSummaryListData) == 0 {
+		t.Fatal("got no summaries")
+	}
+	for _, summary := range result.StockSummaryListData {
+		if !strings.HasPrefix(summary.Date, "2025-01-02") {
+			t.Errorf("%s date = %s, want 2025-01-02", summary.StockCode, summary.Date)
+		}
+		if summary.Close != summary.Previous+summary.Change {
+			t.Errorf("%s close %v != previous %v + change %v", summary.StockCode, summary.Close, summary.Previous, summary.Change)
+		}
+		if summary.High < summary.Low || summary.Volume <= 0 || summary.ListedShares <= 0 {
+			t.Errorf("%s has inconsistent prices or volume: %+v", summary.StockCode, summary)
+		}
+	}
+}
+
+func TestGetStockSummaryListHoliday(t *testing.T) {
+	result, err := newTestClient(t).GetStockSummaryList(context.Background(), "20250101")
+	if err != nil {
+		t.Fatalf("GetStockSummaryList: %v", err)
+	}
+
+	if len(result.StockSummaryListData) != 0 {
+		t.Errorf("got %d summaries on a holiday, want none", len(result.StockSummaryListData))
+	}
+}
+
+func TestGetBrokerList(t *testing.T) {
+	result, err := newTestClient(t).GetBrokerList(context.Background())
+	if err != nil {
+		t.Fatalf("GetBrokerList: %v", err)
+	}
+
+	if len(result.BrokerListData) == 0 {
+		t.Fatal("got no brokers")
+	}
+	for _, broker := range result.BrokerListData {
+		if broker.Code == "" || broker.Name == "" || broker.License == "" {
+			t.Errorf("incomplete broker %+v", broker)
+		}
+	}
+}
+
+func TestGetCompanyProfile(t *testing.T) {
+	result, err := newTestClient(t).GetCompanyProfile(context.Background(), "BBCA")
+	if err != nil {
+		t.Fatalf("GetCompanyProfile: %v", err)
+	}
+
+	if len(result.Profiles) != 1 || result.Profiles[0].KodeEmiten != "BBCA" {
+		t.Fatalf("profiles = %+v, want the BBCA profile", result.Profiles)
+	}
+	if len(result.Direktur) == 0 || len(result.Komisaris) == 0 || len(result.PemegangSaham) == 0 {
+		t.Errorf("profile misses its management or shareholders: %+v", result)
+	}
+
+	var cash, split bool
+	for _, dividend := range result.Dividen {
+		cash = cash || dividend.CashDividenPerSaham > 0
+		split = split || (dividend.Rasio1 > 0 && dividend.Rasio2 > 0)
+	}
+	if !cash || !split {
+		t.Errorf("dividends = %+v, want a cash dividend and a stock split", result.Dividen)
+	}
+}
+
+func TestGetCompanyProfileBlocked(t *testing.T) {
+	_, err := newTestClient(t).GetCompanyProfile(context.Background(), "GOTO")
+	if err == nil || !strings.Contains(err.Error(), "unexpected status code: 403") {
+		t.Fatalf("err = %v, want the 403 of the Cloudflare challenge", err)
+	}
+}
+
+func TestGetFinancialReports(t *testing.T) {
+	result, err := newTestClient(t).GetFinancialReports(context.Background(), "TW1", "2025")
+	if err != nil {
+		t.Fatalf("GetFinancialReports: %v", err)
+	}
+
+	if len(result.Results) != result.ResultCount || len(result.Results) == 0 {
+		t.Fatalf("got %d reports, want ResultCount %d", len(result.Results), result.ResultCount)
+	}
+	for _, report := range result.Results {
+		if report.ReportPeriod != "TW1" || report.ReportYear != "2025" {
+			t.Errorf("%s report is for %s %s, want TW1 2025", report.KodeEmiten, report.ReportPeriod, report.ReportYear)
+		}
+		if len(report.Attachments) == 0 {
+			t.Errorf("%s report has no attachments", report.KodeEmiten)
+		}
+		for _, attachment := range report.Attachments {
+			if attachment.EmitenCode != report.KodeEmiten || !strings.HasPrefix(attachment.FilePath, "/") {
+				t.Errorf("%s attachment %+v, want a relative path of the same issuer", report.KodeEmiten, attachment)
+			}
+		}
+	}
+}
+
+func TestNumber(t *testing.T) {
+	for input, want := range map[string]Number{`null`: 0, `""`: 0, `1.5`: 1.5, `"2.25"`: 2.25, `"-"`: 0, `"N/A"`: 0} {
+		var n Number
+		if err := json.Unmarshal([]byte(input), &n); err != nil || n != want {
+			t.Errorf("Unmarshal(%s) = %v, %v; want %v", input, n, err, want)
+		}
+	}
+
+	var summary StockSummaryListData
+	if err := json.Unmarshal([]byte(`{"StockCode": "BBCA", "Close": 9775, "persen": "-", "percentage": "N/A"}`), &summary); err != nil {
+		t.Fatalf("Unmarshal summary: %v", err)
+	}
+	if summary.Close != 9775 || summary.Persen != 0 || summary.Percentage != 0 {
+		t.Errorf("summary = %+v, want the close and zero percentages", summary)
+	}
+}
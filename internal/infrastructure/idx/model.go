@@ -1,5 +1,10 @@
 package idx
 
+import (
+	"strconv"
+	"strings"
+)
+
 type StockListResponse struct {
 	Draw            int             `json:"draw"`
 	RecordsTotal    int             `json:"recordsTotal"`
@@ -180,8 +185,22 @@ type StockSummaryListData struct {
 	NonRegularVolume    float64 `json:"NonRegularVolume"`
 	NonRegularValue     float64 `json:"NonRegularValue"`
 	NonRegularFrequency float64 `json:"NonRegularFrequency"`
-	Persen              any     `json:"persen"`
-	Percentage          any     `json:"percentage"`
+	Persen              Number  `json:"persen"`
+	Percentage          Number  `json:"percentage"`
+}
+
+// Number is a number IDX sends as a JSON number, a numeric string or null. Null
+// and strings that are not numbers, e.g. "-" or "N/A", decode to zero rather than
+// failing the whole response.
+type Number float64
+
+func (n *Number) UnmarshalJSON(data []byte) error {
+	value, err := strconv.ParseFloat(strings.Trim(string(data), `"`), 64)
+	if err != nil {
+		value = 0
+	}
+	*n = Number(value)
+	return nil
 }
 
 type FinancialReportResponse struct {
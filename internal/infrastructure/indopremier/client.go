@@ -2,15 +2,20 @@ package indopremier
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/PuerkitoBio/goquery"
 	"go-stock/internal/shared/rest"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 )
 
+// ErrEmptyBrokerSummary is returned when no broker traded the stock in the date range.
+var ErrEmptyBrokerSummary = errors.New("empty broker summary data")
+
 type IndopremierClient interface {
 	GetBrokerSummary(ctx context.Context, stockCode, startDate, endDate, investorType, board string) (*GetBrokerSummaryResponse, error)
 }
@@ -21,9 +26,12 @@ type indopremierClient struct {
 }
 
 type Config struct {
-	BaseURL string
-	Delay   time.Duration
-	Path    Path
+	BaseURL        string
+	Delay          time.Duration
+	RateLimit      rest.RateLimit
+	Retry          rest.RetryPolicy
+	CircuitBreaker rest.CircuitBreakerConfig
+	Path           Path
 }
 
 type Path struct {
@@ -37,6 +45,9 @@ func NewIndopremierClient(cfg Config, client *http.Client) IndopremierClient {
 		WithHeader("Content-Type", "text/html").
 		WithHeader("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/136.0.0.0 Safari/537.36").
 		WithDelay(cfg.Delay).
+		WithRateLimit(cfg.RateLimit).
+		WithRetryPolicy(cfg.Retry).
+		WithCircuitBreaker(cfg.CircuitBreaker).
 		WithHTTPClient(client).
 		Build()
 
@@ -68,12 +79,13 @@ func (c *indopremierClient) GetBrokerSummary(ctx context.Context, stockCode, sta
 	).Replace(c.BrokerSummaryPath)
 
 	// Send the HTTP request
-	respBody, statusCode, err := c.restClient.SendRequest(ctx, "GET", path, nil, nil)
+	var attempts int
+	respBody, statusCode, err := c.restClient.SendRequest(ctx, "GET", path, nil, nil, rest.WithAttemptCount(&attempts))
 	if err != nil {
 		return nil, fmt.Errorf("error calling broker summary endpoint: %w", err)
 	}
 	if statusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", statusCode)
+		return nil, fmt.Errorf("unexpected status code: %d after %d attempt(s)", statusCode, attempts)
 	}
 
 	// Parse the response
@@ -122,6 +134,7 @@ func (c *indopremierClient) brokerSummaryParse(html []byte) (*GetBrokerSummaryRe
 			BrokerCode: buyerCode,
 			Lot:        c.parseNumber(tds.Eq(1).Text()),
 			Val:        strings.TrimSpace(tds.Eq(2).Text()),
+			ValAmount:  ParseAmount(tds.Eq(2).Text()),
 			Avg:        c.parseNumber(tds.Eq(3).Text()),
 		}
 
@@ -129,6 +142,7 @@ func (c *indopremierClient) brokerSummaryParse(html []byte) (*GetBrokerSummaryRe
 			BrokerCode: sellerCode,
 			Lot:        c.parseNumber(tds.Eq(6).Text()),
 			Val:        strings.TrimSpace(tds.Eq(7).Text()),
+			ValAmount:  ParseAmount(tds.Eq(7).Text()),
 			Avg:        c.parseNumber(tds.Eq(8).Text()),
 		}
 
@@ -143,8 +157,10 @@ func (c *indopremierClient) brokerSummaryParse(html []byte) (*GetBrokerSummaryRe
 		switch {
 		case strings.HasPrefix(text, "T. Val"):
 			result.Summary.TotalVal = c.extractValue(text)
+			result.Summary.TotalValAmount = ParseAmount(result.Summary.TotalVal)
 		case strings.HasPrefix(text, "F. NVal"):
 			result.Summary.ForeignNetVal = c.extractValue(text)
+			result.Summary.ForeignNetValAmount = ParseAmount(result.Summary.ForeignNetVal)
 		case strings.HasPrefix(text, "T.Lot"):
 			result.Summary.TotalLot = c.parseNumber(c.extractValue(text))
 		case strings.HasPrefix(text, "Avg"):
@@ -154,7 +170,7 @@ func (c *indopremierClient) brokerSummaryParse(html []byte) (*GetBrokerSummaryRe
 
 	// Jika tidak ada baris valid dan summary juga kosong, anggap datanya kosong
 	if !hasValidRow && result.Summary.TotalVal == "0" && result.Summary.TotalLot == 0 && result.Summary.Avg == 0 {
-		return nil, fmt.Errorf("empty broker summary data")
+		return nil, ErrEmptyBrokerSummary
 	}
 
 	return &result, nil
@@ -170,6 +186,50 @@ func (c *indopremierClient) parseNumber(val string) float64 {
 	return num
 }
 
+// amountUnits are the magnitude suffixes of displayed values.
+var amountUnits = map[string]float64{
+	"":  1,
+	"K": 1e3,
+	"M": 1e6,
+	"B": 1e9,
+	"T": 1e12,
+}
+
+// ParseAmount converts a displayed value such as "1.2 B", "-350.4 M", "(350.4 M)"
+// or "12,500" into whole rupiah. Unparsable values are 0.
+func ParseAmount(val string) float64 {
+	val = strings.ReplaceAll(strings.TrimSpace(val), ",", "")
+
+	negative := false
+	if strings.HasPrefix(val, "(") && strings.HasSuffix(val, ")") {
+		negative = true
+		val = strings.TrimSpace(val[1 : len(val)-1])
+	}
+
+	// Split the number from its unit, which may or may not be separated by a space.
+	i := strings.IndexFunc(val, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.' && r != '-' && r != '+'
+	})
+	number, unit := val, ""
+	if i >= 0 {
+		number, unit = val[:i], strings.ToUpper(strings.TrimSpace(val[i:]))
+	}
+
+	multiplier, ok := amountUnits[unit]
+	if !ok {
+		return 0
+	}
+	amount, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0
+	}
+	if negative {
+		amount = -amount
+	}
+	// Round away the float error of the multiplication, e.g. 47.1 * 1e9.
+	return math.Round(amount * multiplier)
+}
+
 // extractValue ...
 func (c *indopremierClient) extractValue(text string) string {
 	parts := strings.SplitN(text, ":", 2)
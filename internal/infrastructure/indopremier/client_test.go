@@ -0,0 +1,112 @@
+package indopremier
+
+import (
+	"context"
+	"errors"
+	"go-stock/internal/shared/rest/recorder"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// newTestClient replays the responses in testdata/fixtures. They are synthetic,
+// written by hand to match the IDX fixtures of the same days: each broker value
+// is its lots of 100 shares at its average price. Set GOSTOCK_HTTP_FIXTURES=record
+// to replace them with live responses from indopremier.com.
+func newTestClient(t *testing.T) IndopremierClient {
+	t.Helper()
+
+	return NewIndopremierClient(Config{
+		BaseURL: "https://www.indopremier.com",
+		Path: Path{
+			BrokerSummary: "/module/saham/include/data-brokersummary.php?code={CODE}&start={START_DATE}&end={END_DATE}&fd={INVESTOR_TYPE}&board={BOARD}",
+		},
+	}, &http.Client{Transport: recorder.NewTransport(recorder.ModeFromEnv(), "testdata/fixtures", nil)})
+}
+
+func TestGetBrokerSummary(t *testing.T) {
+	result, err := newTestClient(t).GetBrokerSummary(context.Background(), "BBCA", "01/02/2025", "01/02/2025", "ALL", "ALL")
+	if err != nil {
+		t.Fatalf("GetBrokerSummary: %v", err)
+	}
+
+	day := time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC)
+	if result.StockCode != "BBCA" || !result.StartDate.Equal(day) || !result.EndDate.Equal(day) {
+		t.Errorf("got %s %v - %v, want BBCA on %v", result.StockCode, result.StartDate, result.EndDate, day)
+	}
+
+	// The trailing row without broker codes is skipped.
+	if len(result.Buyers) != 4 || len(result.Sellers) != 4 {
+		t.Fatalf("got %d buyers and %d sellers, want 4 each", len(result.Buyers), len(result.Sellers))
+	}
+	buyer := BrokerSummaryData{BrokerCode: "BK", Lot: 48120, Val: "47.1 B", ValAmount: 47_100_000_000, Avg: 9788.5}
+	if result.Buyers[0] != buyer {
+		t.Errorf("top buyer = %+v, want %+v", result.Buyers[0], buyer)
+	}
+	seller := BrokerSummaryData{BrokerCode: "CC", Lot: 14295, Val: "14.0 B", ValAmount: 14_000_000_000, Avg: 9770.1}
+	if result.Sellers[2] != seller {
+		t.Errorf("third seller = %+v, want %+v", result.Sellers[2], seller)
+	}
+
+	summary := Summary{
+		TotalVal:            "177.8 B",
+		TotalValAmount:      177_800_000_000,
+		ForeignNetVal:       "-3.8 B",
+		ForeignNetValAmount: -3_800_000_000,
+		TotalLot:            182345,
+		Avg:                 9750.7,
+	}
+	if result.Summary != summary {
+		t.Errorf("summary = %+v, want %+v", result.Summary, summary)
+	}
+}
+
+func TestGetBrokerSummaryMillions(t *testing.T) {
+	result, err := newTestClient(t).GetBrokerSummary(context.Background(), "BBCA", "01/02/2025", "01/02/2025", "ALL", "ALL")
+	if err != nil {
+		t.Fatalf("GetBrokerSummary: %v", err)
+	}
+
+	if got := result.Buyers[3]; got.Val != "95.7 M" || got.ValAmount != 95_700_000 {
+		t.Errorf("fourth buyer value = %q (%v), want 95.7 M (95700000)", got.Val, got.ValAmount)
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		val  string
+		want float64
+	}{
+		{"1.2 B", 1_200_000_000},
+		{"-350.4 M", -350_400_000},
+		{"(350.4 M)", -350_400_000},
+		{"12.5 K", 12_500},
+		{"1.05 T", 1_050_000_000_000},
+		{"1,234.5 M", 1_234_500_000},
+		{"47.1B", 47_100_000_000},
+		{" 12,500 ", 12_500},
+		{"0", 0},
+		{"", 0},
+		{"-", 0},
+		{"1.2 X", 0},
+	}
+	for _, tt := range tests {
+		if got := ParseAmount(tt.val); got != tt.want {
+			t.Errorf("ParseAmount(%q) = %v, want %v", tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestGetBrokerSummaryEmpty(t *testing.T) {
+	_, err := newTestClient(t).GetBrokerSummary(context.Background(), "BBCA", "01/04/2025", "01/04/2025", "ALL", "ALL")
+	if !errors.Is(err, ErrEmptyBrokerSummary) {
+		t.Fatalf("err = %v, want ErrEmptyBrokerSummary", err)
+	}
+}
+
+func TestGetBrokerSummaryInvalidDate(t *testing.T) {
+	_, err := newTestClient(t).GetBrokerSummary(context.Background(), "BBCA", "2025-01-02", "01/02/2025", "ALL", "ALL")
+	if err == nil {
+		t.Fatal("want an error for a start date not in MM/DD/YYYY")
+	}
+}
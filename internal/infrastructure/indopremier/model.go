@@ -11,15 +11,20 @@ type GetBrokerSummaryResponse struct {
 	Summary   Summary
 }
 
+// BrokerSummaryData keeps the value as displayed ("47.1 B") in Val and in rupiah
+// in ValAmount.
 type BrokerSummaryData struct {
 	BrokerCode string
 	Lot        float64
 	Val        string
+	ValAmount  float64
 	Avg        float64
 }
 type Summary struct {
-	TotalVal      string
-	ForeignNetVal string
-	TotalLot      float64
-	Avg           float64
+	TotalVal            string
+	TotalValAmount      float64
+	ForeignNetVal       string
+	ForeignNetValAmount float64
+	TotalLot            float64
+	Avg                 float64
 }
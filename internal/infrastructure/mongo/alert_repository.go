@@ -0,0 +1,194 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-stock/internal/config"
+	"go-stock/internal/entity"
+	"go-stock/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type alertRuleRepository struct {
+	cfg         config.Config
+	mongoClient MongoClient
+	collection  string
+}
+
+func NewAlertRuleRepository(cfg config.Config, mongoClient MongoClient, collection string) repository.AlertRuleRepository {
+	return &alertRuleRepository{
+		cfg:         cfg,
+		mongoClient: mongoClient,
+		collection:  collection,
+	}
+}
+
+func (r *alertRuleRepository) Upsert(ctx context.Context, rule entity.AlertRule) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	filter := bson.M{"rule_id": rule.ID}
+	if _, err := collection.ReplaceOne(ctx, filter, rule, options.Replace().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to upsert alert rule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *alertRuleRepository) UpdateState(ctx context.Context, rule entity.AlertRule) (bool, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	update := bson.M{"$set": bson.M{
+		"triggered":      rule.Triggered,
+		"last_evaluated": rule.LastEvaluated,
+		"last_triggered": rule.LastTriggered,
+	}}
+	result, err := collection.UpdateOne(ctx, bson.M{"rule_id": rule.ID}, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to update alert rule state: %w", err)
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func (r *alertRuleRepository) FindOne(ctx context.Context, id string) (*entity.AlertRule, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	var rule entity.AlertRule
+	err := collection.FindOne(ctx, bson.M{"rule_id": id}).Decode(&rule)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find alert rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// All returns the rules ordered by stock code and name.
+func (r *alertRuleRepository) All(ctx context.Context) ([]entity.AlertRule, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	opts := options.Find().SetSort(bson.D{{Key: "stock_code", Value: 1}, {Key: "name", Value: 1}})
+	cursor, err := collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find failed: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []entity.AlertRule
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	return results, nil
+}
+
+func (r *alertRuleRepository) Delete(ctx context.Context, id string) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	if _, err := collection.DeleteOne(ctx, bson.M{"rule_id": id}); err != nil {
+		return fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+
+	return nil
+}
+
+type alertRepository struct {
+	cfg         config.Config
+	mongoClient MongoClient
+	collection  string
+}
+
+func NewAlertRepository(cfg config.Config, mongoClient MongoClient, collection string) repository.AlertRepository {
+	return &alertRepository{
+		cfg:         cfg,
+		mongoClient: mongoClient,
+		collection:  collection,
+	}
+}
+
+func (r *alertRepository) Insert(ctx context.Context, alert entity.Alert) (bool, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	_, err := collection.InsertOne(ctx, alert)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to insert alert: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *alertRepository) Update(ctx context.Context, alert entity.Alert) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	if _, err := collection.ReplaceOne(ctx, bson.M{"alert_id": alert.ID}, alert); err != nil {
+		return fmt.Errorf("failed to update alert: %w", err)
+	}
+
+	return nil
+}
+
+func (r *alertRepository) Find(ctx context.Context, ruleID string, limit int64) ([]entity.Alert, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	filter := bson.M{}
+	if ruleID != "" {
+		filter["rule_id"] = ruleID
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "triggered_at", Value: -1}}).
+		SetLimit(limit)
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find failed: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []entity.Alert
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	return results, nil
+}
+
+// EnsureIndexes creates the unique index that keeps a rule from firing twice on
+// the same date.
+func (r *alertRepository) EnsureIndexes(ctx context.Context) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "rule_id", Value: 1}, {Key: "date", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "triggered_at", Value: -1}}},
+	}
+	if _, err := collection.Indexes().CreateMany(ctx, models); err != nil {
+		return fmt.Errorf("create indexes failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-stock/internal/config"
+	"go-stock/internal/entity"
+	"go-stock/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type basketRepository struct {
+	cfg         config.Config
+	mongoClient MongoClient
+	collection  string
+}
+
+func NewBasketRepository(cfg config.Config, mongoClient MongoClient, collection string) repository.BasketRepository {
+	return &basketRepository{
+		cfg:         cfg,
+		mongoClient: mongoClient,
+		collection:  collection,
+	}
+}
+
+func (r *basketRepository) Upsert(ctx context.Context, basket entity.Basket) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	filter := bson.M{"basket_id": basket.ID}
+	if _, err := collection.ReplaceOne(ctx, filter, basket, options.Replace().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to upsert basket: %w", err)
+	}
+
+	return nil
+}
+
+func (r *basketRepository) FindOne(ctx context.Context, id string) (*entity.Basket, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	var basket entity.Basket
+	err := collection.FindOne(ctx, bson.M{"basket_id": id}).Decode(&basket)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find basket: %w", err)
+	}
+	return &basket, nil
+}
+
+func (r *basketRepository) All(ctx context.Context) ([]entity.Basket, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find baskets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var baskets []entity.Basket
+	if err := cursor.All(ctx, &baskets); err != nil {
+		return nil, fmt.Errorf("failed to decode baskets: %w", err)
+	}
+	return baskets, nil
+}
+
+func (r *basketRepository) Delete(ctx context.Context, id string) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	if _, err := collection.DeleteOne(ctx, bson.M{"basket_id": id}); err != nil {
+		return fmt.Errorf("failed to delete basket: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-stock/internal/config"
+	"go-stock/internal/entity"
+	"go-stock/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"time"
+)
+
+type brokerSummaryRepository struct {
+	cfg         config.Config
+	mongoClient MongoClient
+	collection  string
+}
+
+func NewBrokerSummaryRepository(cfg config.Config, mongoClient MongoClient, collection string) repository.BrokerSummaryRepository {
+	return &brokerSummaryRepository{
+		cfg:         cfg,
+		mongoClient: mongoClient,
+		collection:  collection,
+	}
+}
+
+// Upsert stores the summary, replacing the one with the same stock, date range,
+// investor type and board.
+func (r *brokerSummaryRepository) Upsert(ctx context.Context, summary entity.BrokerSummary) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	filter := bson.M{
+		"stock_code":    summary.StockCode,
+		"start_date":    summary.StartDate,
+		"end_date":      summary.EndDate,
+		"investor_type": summary.InvestorType,
+		"board":         summary.Board,
+	}
+	update := bson.M{"$set": summary}
+
+	_, err := collection.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to upsert broker summary: %w", err)
+	}
+
+	return nil
+}
+
+func (r *brokerSummaryRepository) FindOne(ctx context.Context, stockCode string, startDate, endDate time.Time, investorType, board string) (*entity.BrokerSummary, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	filter := bson.M{
+		"stock_code":    stockCode,
+		"start_date":    startDate,
+		"end_date":      endDate,
+		"investor_type": investorType,
+		"board":         board,
+	}
+
+	var result entity.BrokerSummary
+	err := collection.FindOne(ctx, filter).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find failed: %w", err)
+	}
+	return &result, nil
+}
+
+// EnsureIndexes creates the unique index on the key the summaries are cached by.
+func (r *brokerSummaryRepository) EnsureIndexes(ctx context.Context) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	model := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "stock_code", Value: 1},
+			{Key: "start_date", Value: 1},
+			{Key: "end_date", Value: 1},
+			{Key: "investor_type", Value: 1},
+			{Key: "board", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := collection.Indexes().CreateOne(ctx, model); err != nil {
+		return fmt.Errorf("create indexes failed: %w", err)
+	}
+
+	return nil
+}
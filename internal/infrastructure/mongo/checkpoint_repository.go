@@ -0,0 +1,77 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-stock/internal/config"
+	"go-stock/internal/entity"
+	"go-stock/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"time"
+)
+
+type checkpointRepository struct {
+	cfg         config.Config
+	mongoClient MongoClient
+	collection  string
+}
+
+func NewCheckpointRepository(cfg config.Config, mongoClient MongoClient, collection string) repository.CheckpointRepository {
+	return &checkpointRepository{
+		cfg:         cfg,
+		mongoClient: mongoClient,
+		collection:  collection,
+	}
+}
+
+func (r *checkpointRepository) Find(ctx context.Context, name string) (*entity.Checkpoint, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	var checkpoint entity.Checkpoint
+	err := collection.FindOne(ctx, bson.M{"name": name}).Decode(&checkpoint)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find checkpoint: %w", err)
+	}
+	return &checkpoint, nil
+}
+
+func (r *checkpointRepository) AddDone(ctx context.Context, name string, items []string) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	now := time.Now()
+	filter := bson.M{"name": name}
+	update := bson.M{
+		"$addToSet":    bson.M{"done": bson.M{"$each": items}},
+		"$set":         bson.M{"updated_at": now},
+		"$setOnInsert": bson.M{"started_at": now},
+	}
+
+	_, err := collection.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to update checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+func (r *checkpointRepository) Delete(ctx context.Context, name string) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	if _, err := collection.DeleteOne(ctx, bson.M{"name": name}); err != nil {
+		return fmt.Errorf("failed to delete checkpoint: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-stock/internal/config"
+	"go-stock/internal/entity"
+	"go-stock/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type jobRunRepository struct {
+	cfg         config.Config
+	mongoClient MongoClient
+	collection  string
+}
+
+func NewJobRunRepository(cfg config.Config, mongoClient MongoClient, collection string) repository.JobRunRepository {
+	return &jobRunRepository{
+		cfg:         cfg,
+		mongoClient: mongoClient,
+		collection:  collection,
+	}
+}
+
+func (r *jobRunRepository) Save(ctx context.Context, run entity.JobRun) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	filter := bson.M{"run_id": run.RunID}
+	update := bson.M{"$set": run}
+
+	_, err := collection.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("save job run failed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *jobRunRepository) FindByJob(ctx context.Context, jobName string, limit int64) ([]entity.JobRun, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	cursor, err := collection.Find(
+		ctx,
+		bson.M{"job_name": jobName},
+		options.Find().
+			SetSort(bson.D{{Key: "started_at", Value: -1}}).
+			SetLimit(limit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find job runs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var runs []entity.JobRun
+	if err := cursor.All(ctx, &runs); err != nil {
+		return nil, fmt.Errorf("failed to decode job runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+func (r *jobRunRepository) FindLatest(ctx context.Context, jobName string) (*entity.JobRun, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	var run entity.JobRun
+	err := collection.FindOne(
+		ctx,
+		bson.M{"job_name": jobName},
+		options.FindOne().SetSort(bson.D{{Key: "started_at", Value: -1}}),
+	).Decode(&run)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find latest job run: %w", err)
+	}
+	return &run, nil
+}
@@ -0,0 +1,83 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"go-stock/internal/config"
+	"go-stock/internal/entity"
+	"go-stock/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"time"
+)
+
+type marketBreadthRepository struct {
+	cfg         config.Config
+	mongoClient MongoClient
+	collection  string
+}
+
+func NewMarketBreadthRepository(cfg config.Config, mongoClient MongoClient, collection string) repository.MarketBreadthRepository {
+	return &marketBreadthRepository{
+		cfg:         cfg,
+		mongoClient: mongoClient,
+		collection:  collection,
+	}
+}
+
+// Upsert stores the breadth, replacing the one of the same date.
+func (r *marketBreadthRepository) Upsert(ctx context.Context, breadth entity.MarketBreadth) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	filter := bson.M{"date": breadth.Date}
+	update := bson.M{"$set": breadth}
+
+	_, err := collection.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to upsert market breadth: %w", err)
+	}
+
+	return nil
+}
+
+// Find returns the breadth of the days between startDate and endDate, oldest first.
+func (r *marketBreadthRepository) Find(ctx context.Context, startDate, endDate time.Time) ([]entity.MarketBreadth, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	filter := bson.M{"date": bson.M{"$gte": startDate, "$lte": endDate}}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}})
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find failed: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []entity.MarketBreadth
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	return results, nil
+}
+
+func (r *marketBreadthRepository) EnsureIndexes(ctx context.Context) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	model := mongo.IndexModel{
+		Keys:    bson.D{{Key: "date", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := collection.Indexes().CreateOne(ctx, model); err != nil {
+		return fmt.Errorf("create indexes failed: %w", err)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package mongo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go-stock/internal/config"
 	"go-stock/internal/entity"
@@ -87,7 +88,8 @@ func (r *stockSummaryRepository) Find(ctx context.Context, stockCode string, sta
 		filter["stock_code"] = stockCode
 	}
 
-	cursor, err := collection.Find(ctx, filter)
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "stock_code", Value: 1}})
+	cursor, err := collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, fmt.Errorf("find failed: %w", err)
 	}
@@ -100,3 +102,278 @@ func (r *stockSummaryRepository) Find(ctx context.Context, stockCode string, sta
 
 	return results, nil
 }
+
+func (r *stockSummaryRepository) FindDates(ctx context.Context, startDate, endDate time.Time) ([]time.Time, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	filter := bson.M{
+		"date": bson.M{
+			"$gte": startDate,
+			"$lte": endDate,
+		},
+	}
+
+	var dates []time.Time
+	if err := collection.Distinct(ctx, "date", filter).Decode(&dates); err != nil {
+		return nil, fmt.Errorf("distinct dates failed: %w", err)
+	}
+
+	return dates, nil
+}
+
+// FindBefore returns the last limit summaries of the stock before the given date,
+// newest first.
+func (r *stockSummaryRepository) FindBefore(ctx context.Context, code string, before time.Time, limit int64) ([]entity.StockSummary, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	filter := bson.M{
+		"stock_code": code,
+		"date":       bson.M{"$lt": before},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "date", Value: -1}}).
+		SetLimit(limit)
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find failed: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []entity.StockSummary
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	return results, nil
+}
+
+func (r *stockSummaryRepository) FindLatest(ctx context.Context, code string) (*entity.StockSummary, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "date", Value: -1}})
+
+	var result entity.StockSummary
+	if err := collection.FindOne(ctx, bson.M{"stock_code": code}, opts).Decode(&result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find latest failed: %w", err)
+	}
+
+	return &result, nil
+}
+
+// FindLatestDate returns the date of the most recent summary, or the zero time
+// when none is stored.
+func (r *stockSummaryRepository) FindLatestDate(ctx context.Context) (time.Time, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "date", Value: -1}}).
+		SetProjection(bson.M{"date": 1})
+
+	var result entity.StockSummary
+	if err := collection.FindOne(ctx, bson.M{}, opts).Decode(&result); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("find latest date failed: %w", err)
+	}
+
+	return result.Date, nil
+}
+
+// FindLeaders ranks the summaries of a day by one of their stored metrics.
+// Summaries without a previous close have no change percent and are left out of
+// that ranking.
+func (r *stockSummaryRepository) FindLeaders(ctx context.Context, date time.Time, metric string, descending bool, filter entity.LeaderFilter, limit int64) ([]entity.MarketLeader, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	match := bson.M{"date": date}
+	if len(filter.StockCodes) > 0 {
+		match["stock_code"] = bson.M{"$in": filter.StockCodes}
+	}
+	if filter.MinValue > 0 {
+		match["value"] = bson.M{"$gte": filter.MinValue}
+	}
+	if filter.MinVolume > 0 {
+		match["volume"] = bson.M{"$gte": filter.MinVolume}
+	}
+	if metric == entity.LeaderMetricChangePct {
+		match["previous"] = bson.M{"$gt": 0}
+	}
+
+	direction := 1
+	if descending {
+		direction = -1
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: bson.D{{Key: metric, Value: direction}, {Key: "stock_code", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+		{{Key: "$addFields", Value: bson.M{"foreign_net": bson.M{"$subtract": bson.A{"$foreign_buy", "$foreign_sell"}}}}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate failed: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []entity.MarketLeader
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	return results, nil
+}
+
+// FindPeriodSummaries rolls the summaries of every stock between startDate and
+// endDate up into one per stock.
+func (r *stockSummaryRepository) FindPeriodSummaries(ctx context.Context, startDate, endDate time.Time) ([]entity.StockPeriodSummary, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	traded := bson.M{"$and": bson.A{
+		bson.M{"$gt": bson.A{"$previous", 0}},
+		bson.M{"$gt": bson.A{"$close", 0}},
+	}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"date": bson.M{"$gte": startDate, "$lte": endDate}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "stock_code", Value: 1}, {Key: "date", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":               "$stock_code",
+			"stock_name":        bson.M{"$last": "$stock_name"},
+			"first_date":        bson.M{"$first": "$date"},
+			"last_date":         bson.M{"$last": "$date"},
+			"previous":          bson.M{"$first": "$previous"},
+			"close":             bson.M{"$last": "$close"},
+			"log_return":        bson.M{"$sum": bson.M{"$cond": bson.A{traded, bson.M{"$ln": bson.M{"$divide": bson.A{"$close", "$previous"}}}, 0}}},
+			"market_cap":        bson.M{"$first": bson.M{"$multiply": bson.A{"$previous", "$listed_shares"}}},
+			"value":             bson.M{"$sum": "$value"},
+			"volume":            bson.M{"$sum": "$volume"},
+			"foreign_net_value": bson.M{"$sum": "$foreign_net_value"},
+			"days":              bson.M{"$sum": 1},
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"stock_code": "$_id",
+			"return":     bson.M{"$subtract": bson.A{bson.M{"$exp": "$log_return"}, 1}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "stock_code", Value: 1}}}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate failed: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []entity.StockPeriodSummary
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	return results, nil
+}
+
+// FindRanges returns the range of every stock traded between startDate and
+// endDate.
+func (r *stockSummaryRepository) FindRanges(ctx context.Context, startDate, endDate time.Time) ([]entity.StockRange, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"date": bson.M{"$gte": startDate, "$lte": endDate}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":  "$stock_code",
+			"high": bson.M{"$max": "$high"},
+			// $min skips the nulls of the days without trades.
+			"low":           bson.M{"$min": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$low", 0}}, "$low", nil}}},
+			"average_close": bson.M{"$avg": "$close"},
+			"days":          bson.M{"$sum": 1},
+		}}},
+		{{Key: "$set", Value: bson.M{"stock_code": "$_id"}}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate failed: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []entity.StockRange
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	return results, nil
+}
+
+// NormalizePercentages stores the persen and percentage fields, once kept as IDX
+// sent them, as doubles: numeric strings are converted, null, missing and other
+// values become zero. It returns the number of summaries changed.
+func (r *stockSummaryRepository) NormalizePercentages(ctx context.Context) (int64, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"persen": bson.M{"$not": bson.M{"$type": "double"}}},
+		bson.M{"percentage": bson.M{"$not": bson.M{"$type": "double"}}},
+	}}
+	toDouble := func(field string) bson.M {
+		return bson.M{"$convert": bson.M{"input": "$" + field, "to": "double", "onError": 0.0, "onNull": 0.0}}
+	}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"persen": toDouble("persen"), "percentage": toDouble("percentage")}}},
+	}
+
+	result, err := collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("update many failed: %w", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// EnsureIndexes creates the indexes of the collection: summaries are looked up by
+// stock and date, and a day is ranked by its traded value, volume, frequency,
+// change percent and foreign net value. It also normalizes the percentages of
+// summaries stored as strings, which would fail to decode otherwise.
+func (r *stockSummaryRepository) EnsureIndexes(ctx context.Context) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "stock_code", Value: 1}, {Key: "date", Value: 1}}},
+		{Keys: bson.D{{Key: "date", Value: 1}, {Key: "value", Value: -1}}},
+		{Keys: bson.D{{Key: "date", Value: 1}, {Key: "volume", Value: -1}}},
+		{Keys: bson.D{{Key: "date", Value: 1}, {Key: "frequency", Value: -1}}},
+		{Keys: bson.D{{Key: "date", Value: 1}, {Key: "change_pct", Value: -1}}},
+		{Keys: bson.D{{Key: "date", Value: 1}, {Key: "foreign_net_value", Value: -1}}},
+	}
+	if _, err := collection.Indexes().CreateMany(ctx, models); err != nil {
+		return fmt.Errorf("create indexes failed: %w", err)
+	}
+
+	if _, err := r.NormalizePercentages(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
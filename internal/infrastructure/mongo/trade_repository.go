@@ -0,0 +1,104 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-stock/internal/config"
+	"go-stock/internal/entity"
+	"go-stock/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type tradeRepository struct {
+	cfg         config.Config
+	mongoClient MongoClient
+	collection  string
+}
+
+func NewTradeRepository(cfg config.Config, mongoClient MongoClient, collection string) repository.TradeRepository {
+	return &tradeRepository{
+		cfg:         cfg,
+		mongoClient: mongoClient,
+		collection:  collection,
+	}
+}
+
+func (r *tradeRepository) Insert(ctx context.Context, trade entity.Trade) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	if _, err := collection.InsertOne(ctx, trade); err != nil {
+		return fmt.Errorf("failed to insert trade: %w", err)
+	}
+
+	return nil
+}
+
+// Find returns the trades of a portfolio in the order they happened.
+func (r *tradeRepository) Find(ctx context.Context, portfolio string) ([]entity.Trade, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "created_at", Value: 1}})
+	cursor, err := collection.Find(ctx, bson.M{"portfolio": portfolio}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find failed: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []entity.Trade
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	return results, nil
+}
+
+func (r *tradeRepository) FindOne(ctx context.Context, portfolio, id string) (*entity.Trade, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	var trade entity.Trade
+	err := collection.FindOne(ctx, bson.M{"portfolio": portfolio, "trade_id": id}).Decode(&trade)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find trade: %w", err)
+	}
+	return &trade, nil
+}
+
+func (r *tradeRepository) Delete(ctx context.Context, portfolio, id string) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	if _, err := collection.DeleteOne(ctx, bson.M{"portfolio": portfolio, "trade_id": id}); err != nil {
+		return fmt.Errorf("failed to delete trade: %w", err)
+	}
+
+	return nil
+}
+
+func (r *tradeRepository) EnsureIndexes(ctx context.Context) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "portfolio", Value: 1}, {Key: "date", Value: 1}}},
+		{Keys: bson.D{{Key: "trade_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+	}
+	if _, err := collection.Indexes().CreateMany(ctx, models); err != nil {
+		return fmt.Errorf("create indexes failed: %w", err)
+	}
+
+	return nil
+}
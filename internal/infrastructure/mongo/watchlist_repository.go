@@ -0,0 +1,105 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-stock/internal/config"
+	"go-stock/internal/entity"
+	"go-stock/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type watchlistRepository struct {
+	cfg         config.Config
+	mongoClient MongoClient
+	collection  string
+}
+
+func NewWatchlistRepository(cfg config.Config, mongoClient MongoClient, collection string) repository.WatchlistRepository {
+	return &watchlistRepository{
+		cfg:         cfg,
+		mongoClient: mongoClient,
+		collection:  collection,
+	}
+}
+
+func (r *watchlistRepository) Upsert(ctx context.Context, watchlist entity.Watchlist) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	filter := bson.M{"user_id": watchlist.UserID, "watchlist_id": watchlist.ID}
+	if _, err := collection.ReplaceOne(ctx, filter, watchlist, options.Replace().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to upsert watchlist: %w", err)
+	}
+
+	return nil
+}
+
+func (r *watchlistRepository) FindOne(ctx context.Context, userID, id string) (*entity.Watchlist, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	var watchlist entity.Watchlist
+	err := collection.FindOne(ctx, bson.M{"user_id": userID, "watchlist_id": id}).Decode(&watchlist)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find watchlist: %w", err)
+	}
+	return &watchlist, nil
+}
+
+// Find returns the watchlists of a user ordered by name.
+func (r *watchlistRepository) Find(ctx context.Context, userID string) ([]entity.Watchlist, error) {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find failed: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []entity.Watchlist
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	return results, nil
+}
+
+func (r *watchlistRepository) Delete(ctx context.Context, userID, id string) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	if _, err := collection.DeleteOne(ctx, bson.M{"user_id": userID, "watchlist_id": id}); err != nil {
+		return fmt.Errorf("failed to delete watchlist: %w", err)
+	}
+
+	return nil
+}
+
+func (r *watchlistRepository) EnsureIndexes(ctx context.Context) error {
+	collection := r.mongoClient.GetClient().
+		Database(r.cfg.GetMongo().Database).
+		Collection(r.collection)
+
+	models := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "name", Value: 1}}},
+		{Keys: bson.D{{Key: "watchlist_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+	}
+	if _, err := collection.Indexes().CreateMany(ctx, models); err != nil {
+		return fmt.Errorf("create indexes failed: %w", err)
+	}
+
+	return nil
+}
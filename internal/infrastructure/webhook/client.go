@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go-stock/internal/shared/rest"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers of a signed delivery. The signature is the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the secret of the webhook, prefixed "sha256=".
+const (
+	SignatureHeader = "X-Signature"
+	TimestampHeader = "X-Signature-Timestamp"
+)
+
+type WebhookClient interface {
+	Send(ctx context.Context, url, secret string, payload interface{}) error
+}
+
+type webhookClient struct {
+	httpClient *http.Client
+	retry      rest.RetryPolicy
+}
+
+type Config struct {
+	Timeout time.Duration
+	Retry   rest.RetryPolicy
+}
+
+// NewWebhookClient initializes a new WebhookClient.
+func NewWebhookClient(cfg Config, client *http.Client) WebhookClient {
+	if cfg.Timeout > 0 {
+		client = &http.Client{Transport: client.Transport, Timeout: cfg.Timeout}
+	}
+	return &webhookClient{
+		httpClient: client,
+		retry:      cfg.Retry,
+	}
+}
+
+// Send posts the payload as JSON to the webhook, retrying failed deliveries. Any
+// response other than 2xx is a failure.
+func (c *webhookClient) Send(ctx context.Context, url, secret string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error marshaling payload: %w", err)
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	if secret != "" {
+		timestamp := time.Now().Unix()
+		headers[TimestampHeader] = strconv.FormatInt(timestamp, 10)
+		headers[SignatureHeader] = Sign(secret, timestamp, body)
+	}
+
+	restClient := rest.NewRestClientBuilder().
+		WithBaseURL(url).
+		WithRetryPolicy(c.retry).
+		WithHTTPClient(c.httpClient).
+		Build()
+
+	_, statusCode, err := restClient.SendRequest(ctx, http.MethodPost, "", json.RawMessage(body), headers)
+	if err != nil {
+		return fmt.Errorf("error delivering webhook: %w", err)
+	}
+	if statusCode < 200 || statusCode >= 300 {
+		return fmt.Errorf("webhook responded with status code %d", statusCode)
+	}
+	return nil
+}
+
+// Sign returns the signature of a body sent at timestamp, for receivers to check.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"context"
+	"go-stock/internal/shared/rest"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSend(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		if r.Header.Get(SignatureHeader) != Sign("secret", timestamp, body) || string(body) != `{"rule":"BBCA above 10000"}` {
+			t.Errorf("got %s signed %q", body, r.Header.Get(SignatureHeader))
+		}
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewWebhookClient(Config{
+		Retry: rest.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, RetryableStatusCodes: rest.DefaultRetryableStatusCodes},
+	}, server.Client())
+
+	if err := client.Send(context.Background(), server.URL, "secret", map[string]string{"rule": "BBCA above 10000"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("delivered in %d attempts, want 2", attempts)
+	}
+}
+
+func TestSendFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(SignatureHeader) != "" {
+			t.Error("payload signed without a secret")
+		}
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewWebhookClient(Config{Retry: rest.NoRetry}, server.Client())
+	if err := client.Send(context.Background(), server.URL, "", struct{}{}); err == nil {
+		t.Error("Send succeeded on a 400 response")
+	}
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163"
+	if got := Sign("secret", 1700000000, []byte("{}")); got != want {
+		t.Errorf("Sign = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,108 @@
+package marketrules
+
+import "math"
+
+// Listing boards, as named in the stock profiles.
+const (
+	BoardMain         = "Utama"
+	BoardDevelopment  = "Pengembangan"
+	BoardNewEconomy   = "Ekonomi Baru"
+	BoardAcceleration = "Akselerasi"
+	BoardWatchlist    = "Pemantauan Khusus"
+)
+
+// Limits a day can reach.
+const (
+	// LimitUpper is the auto-rejection upper limit, ARA.
+	LimitUpper = "ara"
+	// LimitLower is the auto-rejection lower limit, ARB.
+	LimitLower = "arb"
+)
+
+// boardRule is the auto-rejection of a board. The upper percentage depends on
+// the band of the reference price; the limits move at least one tick and never
+// below the minimum price of the board.
+type boardRule struct {
+	minPrice float64
+	upper    []struct {
+		upTo    float64
+		percent float64
+	}
+	lower float64
+}
+
+// regular is the rule of the main, development and new economy boards: ARA of
+// 35%, 25% and 20% by price band and a 15% ARB.
+var regular = boardRule{
+	minPrice: 50,
+	upper: []struct {
+		upTo    float64
+		percent float64
+	}{
+		{200, 35},
+		{5000, 25},
+		{math.Inf(1), 20},
+	},
+	lower: 15,
+}
+
+// special is the rule of the acceleration and watchlist boards, 10% both ways.
+var special = boardRule{
+	minPrice: 1,
+	upper: []struct {
+		upTo    float64
+		percent float64
+	}{
+		{math.Inf(1), 10},
+	},
+	lower: 10,
+}
+
+func ruleOf(board string) boardRule {
+	switch board {
+	case BoardAcceleration, BoardWatchlist:
+		return special
+	}
+	return regular
+}
+
+// Limits are the lowest and highest prices of a session and their distance from
+// the reference price in percent.
+type Limits struct {
+	Reference    float64
+	Upper        float64
+	Lower        float64
+	UpperPercent float64
+	LowerPercent float64
+}
+
+// AutoRejection returns the limits of a session with the reference price, the
+// previous close, on a board. Unknown boards follow the main board.
+func AutoRejection(board string, reference float64) Limits {
+	rule := ruleOf(board)
+	limits := Limits{Reference: reference, LowerPercent: rule.lower}
+	for _, band := range rule.upper {
+		if reference <= band.upTo {
+			limits.UpperPercent = band.percent
+			break
+		}
+	}
+
+	// Limits are rounded toward the reference price.
+	limits.Upper = math.Max(RoundDown(reference*(1+limits.UpperPercent/100)), reference+TickSize(reference))
+	limits.Lower = math.Min(RoundUp(reference*(1-limits.LowerPercent/100)), reference-TickSize(reference))
+	limits.Lower = math.Max(limits.Lower, rule.minPrice)
+	return limits
+}
+
+// Hit tells which limit a day trading between low and high reached, LimitUpper,
+// LimitLower or "" for neither.
+func (l Limits) Hit(high, low float64) string {
+	switch {
+	case high > 0 && high >= l.Upper:
+		return LimitUpper
+	case low > 0 && low <= l.Lower:
+		return LimitLower
+	}
+	return ""
+}
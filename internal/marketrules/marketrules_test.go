@@ -0,0 +1,71 @@
+package marketrules
+
+import (
+	"math"
+	"testing"
+)
+
+func assertFloat(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-6 {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
+
+func TestTickSize(t *testing.T) {
+	for _, tc := range []struct {
+		price, tick, up, down, nearest float64
+	}{
+		{150.5, 1, 151, 150, 150},
+		{333, 2, 334, 332, 332},
+		{1234, 5, 1235, 1230, 1235},
+		{4999, 10, 5000, 4990, 5000},
+		{9010, 25, 9025, 9000, 9000},
+		{9025, 25, 9025, 9025, 9025},
+	} {
+		assertFloat(t, "tick", TickSize(tc.price), tc.tick)
+		assertFloat(t, "up", RoundUp(tc.price), tc.up)
+		assertFloat(t, "down", RoundDown(tc.price), tc.down)
+		assertFloat(t, "nearest", Round(tc.price), tc.nearest)
+	}
+	if !IsValid(9025) || IsValid(9010) || IsValid(0) {
+		t.Errorf("IsValid mismatch")
+	}
+}
+
+func TestAutoRejection(t *testing.T) {
+	for _, tc := range []struct {
+		board                   string
+		reference, upper, lower float64
+	}{
+		// 35% and 15% below 200, never below the minimum price of 50.
+		{BoardMain, 100, 135, 85},
+		{BoardMain, 55, 74, 50},
+		// 25% up to 5000, rounded toward the reference to the tick of the limit.
+		{BoardDevelopment, 1005, 1255, 855},
+		{BoardMain, 5000, 6250, 4250},
+		// 20% above 5000.
+		{BoardMain, 9025, 10825, 7675},
+		{"", 9025, 10825, 7675},
+		// 10% on the special boards, one tick at least.
+		{BoardAcceleration, 100, 110, 90},
+		{BoardWatchlist, 5, 6, 4},
+		{BoardWatchlist, 1, 2, 1},
+	} {
+		limits := AutoRejection(tc.board, tc.reference)
+		if limits.Upper != tc.upper || limits.Lower != tc.lower {
+			t.Errorf("AutoRejection(%q, %v) = %v - %v, want %v - %v", tc.board, tc.reference, limits.Lower, limits.Upper, tc.lower, tc.upper)
+		}
+	}
+
+	limits := AutoRejection(BoardMain, 1000)
+	if hit := limits.Hit(1250, 1100); hit != LimitUpper {
+		t.Errorf("Hit = %q, want ara", hit)
+	}
+	if hit := limits.Hit(900, 850); hit != LimitLower {
+		t.Errorf("Hit = %q, want arb", hit)
+	}
+	if hit := limits.Hit(1245, 855); hit != "" {
+		t.Errorf("Hit = %q, want none", hit)
+	}
+}
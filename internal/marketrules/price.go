@@ -0,0 +1,60 @@
+// Package marketrules encodes the IDX trading rules on prices: the price
+// fractions, or tick sizes, of each price band and the auto-rejection limits of
+// each listing board around the reference price of a session.
+package marketrules
+
+import "math"
+
+// LotSize is the number of shares in a lot on IDX.
+const LotSize = 100
+
+// priceFractions are the tick sizes by price band: a price below the upper bound
+// of a band moves in steps of its tick.
+var priceFractions = []struct {
+	below float64
+	tick  float64
+}{
+	{200, 1},
+	{500, 2},
+	{2000, 5},
+	{5000, 10},
+	{math.Inf(1), 25},
+}
+
+// TickSize returns the price step of the band the price falls in.
+func TickSize(price float64) float64 {
+	for _, fraction := range priceFractions {
+		if price < fraction.below {
+			return fraction.tick
+		}
+	}
+	return priceFractions[len(priceFractions)-1].tick
+}
+
+// RoundUp returns the lowest valid price at or above price, what a buy order
+// pays at least.
+func RoundUp(price float64) float64 {
+	tick := TickSize(price)
+	return math.Ceil(price/tick-1e-9) * tick
+}
+
+// RoundDown returns the highest valid price at or below price, what a sell order
+// gets at most.
+func RoundDown(price float64) float64 {
+	tick := TickSize(price)
+	return math.Floor(price/tick+1e-9) * tick
+}
+
+// Round returns the valid price nearest to price, the lower one on a tie.
+func Round(price float64) float64 {
+	down, up := RoundDown(price), RoundUp(price)
+	if up-price < price-down {
+		return up
+	}
+	return down
+}
+
+// IsValid tells whether price is a whole number of ticks of its band.
+func IsValid(price float64) bool {
+	return price > 0 && RoundDown(price) == price
+}
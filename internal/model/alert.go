@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+type AlertRuleRequest struct {
+	Name      string  `json:"name,omitempty" validate:"max=100" example:"BBCA above 10000"`
+	StockCode string  `json:"stock_code" validate:"required" example:"BBCA"`
+	Type      string  `json:"type" validate:"required,oneof=price_above price_below value_spike foreign_net_sell" example:"price_above"`
+	Threshold float64 `json:"threshold" validate:"gt=0" example:"10000"`
+	Period    int     `json:"period,omitempty" validate:"gte=0,lte=250" example:"20"`
+	Enabled   *bool   `json:"enabled,omitempty" example:"true"`
+}
+
+type AlertRuleResponse struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	StockCode     string    `json:"stock_code"`
+	Type          string    `json:"type"`
+	Threshold     float64   `json:"threshold"`
+	Period        int       `json:"period,omitempty"`
+	Enabled       bool      `json:"enabled"`
+	Triggered     bool      `json:"triggered"`
+	LastEvaluated string    `json:"last_evaluated,omitempty"`
+	LastTriggered string    `json:"last_triggered,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type AlertsRequest struct {
+	RuleID string `json:"rule_id,omitempty"`
+	Limit  int64  `json:"limit" validate:"min=1,max=100"`
+}
+
+type AlertResponse struct {
+	ID            string    `json:"id"`
+	RuleID        string    `json:"rule_id"`
+	RuleName      string    `json:"rule_name"`
+	StockCode     string    `json:"stock_code"`
+	Type          string    `json:"type"`
+	Date          string    `json:"date"`
+	Threshold     float64   `json:"threshold"`
+	Observed      float64   `json:"observed"`
+	Message       string    `json:"message"`
+	TriggeredAt   time.Time `json:"triggered_at"`
+	Delivered     bool      `json:"delivered"`
+	DeliveryError string    `json:"delivery_error,omitempty"`
+}
@@ -0,0 +1,81 @@
+package model
+
+type BacktestRequest struct {
+	StockCodes []string         `json:"stock_codes" validate:"required,min=1,max=50,dive,required" example:"BBCA,BBRI"`
+	StartDate  string           `json:"start_date,omitempty" validate:"omitempty,datetime=2006-01-02" example:"2024-01-01"`
+	EndDate    string           `json:"end_date,omitempty" validate:"omitempty,datetime=2006-01-02" example:"2024-12-31"`
+	Strategy   BacktestStrategy `json:"strategy"`
+	// InitialCapital defaults to 100,000,000.
+	InitialCapital float64 `json:"initial_capital,omitempty" validate:"omitempty,gt=0" example:"100000000"`
+	// BuyFee and SellFee are fractions of the traded value, 0.0015 and 0.0025 by
+	// default.
+	BuyFee  *float64 `json:"buy_fee,omitempty" validate:"omitempty,gte=0,lt=1" example:"0.0015"`
+	SellFee *float64 `json:"sell_fee,omitempty" validate:"omitempty,gte=0,lt=1" example:"0.0025"`
+}
+
+// BacktestStrategy enters when all entry conditions hold and exits when any exit
+// condition holds or the stop-loss or take-profit, fractions of the entry price,
+// is reached.
+type BacktestStrategy struct {
+	Entry        []BacktestCondition `json:"entry" validate:"required,min=1,max=10,dive"`
+	Exit         []BacktestCondition `json:"exit,omitempty" validate:"max=10,dive"`
+	StopLoss     float64             `json:"stop_loss,omitempty" validate:"gte=0,lt=1" example:"0.08"`
+	TakeProfit   float64             `json:"take_profit,omitempty" validate:"gte=0" example:"0.2"`
+	Sizing       *BacktestSizing     `json:"sizing,omitempty"`
+	MaxPositions int                 `json:"max_positions,omitempty" validate:"gte=0" example:"5"`
+}
+
+// BacktestCondition compares left with right, or with value when right is empty.
+type BacktestCondition struct {
+	Left  string  `json:"left" validate:"required" example:"sma:20"`
+	Op    string  `json:"op" validate:"required,oneof=crosses_above crosses_below gt gte lt lte" example:"crosses_above"`
+	Right string  `json:"right,omitempty" example:"sma:50"`
+	Value float64 `json:"value,omitempty"`
+}
+
+type BacktestSizing struct {
+	Type  string  `json:"type" validate:"required,oneof=percent_equity amount lots" example:"percent_equity"`
+	Value float64 `json:"value" validate:"gt=0" example:"20"`
+}
+
+type BacktestResponse struct {
+	StockCodes []string                 `json:"stock_codes"`
+	StartDate  string                   `json:"start_date"`
+	EndDate    string                   `json:"end_date"`
+	Metrics    BacktestMetricsResponse  `json:"metrics"`
+	Trades     []BacktestTradeResponse  `json:"trades"`
+	Equity     []BacktestEquityResponse `json:"equity"`
+}
+
+type BacktestMetricsResponse struct {
+	InitialCapital float64 `json:"initial_capital"`
+	FinalEquity    float64 `json:"final_equity"`
+	TotalReturnPct float64 `json:"total_return_pct"`
+	CAGRPct        float64 `json:"cagr_pct"`
+	MaxDrawdownPct float64 `json:"max_drawdown_pct"`
+	Sharpe         float64 `json:"sharpe"`
+	Trades         int     `json:"trades"`
+	WinRatePct     float64 `json:"win_rate_pct"`
+}
+
+type BacktestTradeResponse struct {
+	StockCode  string  `json:"stock_code"`
+	EntryDate  string  `json:"entry_date"`
+	EntryPrice float64 `json:"entry_price"`
+	ExitDate   string  `json:"exit_date"`
+	ExitPrice  float64 `json:"exit_price"`
+	ExitReason string  `json:"exit_reason"`
+	Lots       int     `json:"lots"`
+	Shares     float64 `json:"shares"`
+	Cost       float64 `json:"cost"`
+	Fees       float64 `json:"fees"`
+	PnL        float64 `json:"pnl"`
+	ReturnPct  float64 `json:"return_pct"`
+}
+
+type BacktestEquityResponse struct {
+	Date        string  `json:"date"`
+	Cash        float64 `json:"cash"`
+	Equity      float64 `json:"equity"`
+	DrawdownPct float64 `json:"drawdown_pct"`
+}
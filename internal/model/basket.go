@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+type BasketRequest struct {
+	Name       string   `json:"name" validate:"required,max=100" example:"Big banks"`
+	StockCodes []string `json:"stock_codes" validate:"required,min=1,max=100,dive,required" example:"BBCA,BBRI,BMRI"`
+	Weighting  string   `json:"weighting" validate:"required,oneof=equal market_cap free_float" example:"market_cap"`
+}
+
+type BasketResponse struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	StockCodes []string  `json:"stock_codes"`
+	Weighting  string    `json:"weighting"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type BasketIndexRequest struct {
+	StartDate string  `json:"start_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   string  `json:"end_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Base      float64 `json:"base,omitempty" validate:"gt=0"`
+}
+
+type BasketIndexResponse struct {
+	Basket        BasketResponse               `json:"basket"`
+	StartDate     string                       `json:"start_date"`
+	EndDate       string                       `json:"end_date"`
+	BaseLevel     float64                      `json:"base_level"`
+	ReturnPct     float64                      `json:"return_pct"`
+	Levels        []BasketIndexLevelResponse   `json:"levels"`
+	Contributions []BasketContributionResponse `json:"contributions"`
+}
+
+type BasketIndexLevelResponse struct {
+	Date      string  `json:"date"`
+	Level     float64 `json:"level"`
+	ReturnPct float64 `json:"return_pct"`
+}
+
+type BasketContributionResponse struct {
+	StockCode string `json:"stock_code"`
+	StockName string `json:"stock_name"`
+	// WeightPct is the weight of the stock on the last day it was traded.
+	WeightPct float64 `json:"weight_pct"`
+	ReturnPct float64 `json:"return_pct"`
+	// ContributionPct is the part of the index return from the stock, in percent
+	// points; the contributions add up to the return of the index.
+	ContributionPct float64 `json:"contribution_pct"`
+}
@@ -22,13 +22,16 @@ type BrokerSummaryResponse struct {
 type BrokerSummaryData struct {
 	BrokerCode string  `json:"broker_code"`
 	Lot        float64 `json:"lot"`
-	Val        string  `json:"val"`
+	Val        string  `json:"val" example:"47.1 B"`
+	ValAmount  float64 `json:"val_amount" example:"47100000000"`
 	Avg        float64 `json:"avg"`
 }
 
 type Summary struct {
-	TotalVal      string  `json:"total_val"`
-	ForeignNetVal string  `json:"foreign_net_val"`
-	TotalLot      float64 `json:"total_lot"`
-	Avg           float64 `json:"avg"`
+	TotalVal            string  `json:"total_val" example:"177.8 B"`
+	TotalValAmount      float64 `json:"total_val_amount" example:"177800000000"`
+	ForeignNetVal       string  `json:"foreign_net_val" example:"-3.8 B"`
+	ForeignNetValAmount float64 `json:"foreign_net_val_amount" example:"-3800000000"`
+	TotalLot            float64 `json:"total_lot"`
+	Avg                 float64 `json:"avg"`
 }
@@ -0,0 +1,18 @@
+package model
+
+type TradingDayRequest struct {
+	Date string `json:"date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type TradingDaysRequest struct {
+	StartDate string `json:"start_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   string `json:"end_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type TradingDayResponse struct {
+	Date string `json:"date"`
+	// Status is session, expected, weekend, holiday or closed.
+	Status  string `json:"status"`
+	Trading bool   `json:"trading"`
+	Holiday string `json:"holiday,omitempty"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type HealthResponse struct {
+	Status    string             `json:"status"`
+	Upstreams []UpstreamResponse `json:"upstreams"`
+}
+
+type UpstreamResponse struct {
+	Host                string     `json:"host"`
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at"`
+	LastError           string     `json:"last_error,omitempty"`
+}
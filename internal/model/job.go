@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+type JobRunsRequest struct {
+	Name  string `json:"name" validate:"required"`
+	Limit int64  `json:"limit" validate:"min=1,max=100"`
+}
+
+type JobTriggerRequest struct {
+	Name   string `json:"-" validate:"required"`
+	Date   string `json:"date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Period string `json:"period,omitempty" validate:"omitempty,oneof=TW1 TW2 TW3 Audit"`
+	Year   string `json:"year,omitempty" validate:"omitempty,len=4,numeric"`
+	// StartDate and EndDate bound the days of BackfillSummaries.
+	StartDate string `json:"start_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   string `json:"end_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type JobResponse struct {
+	Name        string          `json:"name"`
+	Schedule    string          `json:"schedule"`
+	NextRun     *time.Time      `json:"next_run"`
+	PreviousRun *time.Time      `json:"previous_run"`
+	LastRun     *JobRunResponse `json:"last_run"`
+	Running     bool            `json:"running"`
+}
+
+type JobRunResponse struct {
+	RunID      string     `json:"run_id"`
+	JobName    string     `json:"job_name"`
+	Status     string     `json:"status"`
+	Trigger    string     `json:"trigger"`
+	Params     JobParams  `json:"params"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	DurationMs int64      `json:"duration_ms"`
+	Records    int        `json:"records"`
+	Error      string     `json:"error,omitempty"`
+}
+
+type JobRunsResponse struct {
+	Job  JobResponse      `json:"job"`
+	Runs []JobRunResponse `json:"runs"`
+}
+
+type JobParams struct {
+	Date      string `json:"date,omitempty"`
+	Period    string `json:"period,omitempty"`
+	Year      string `json:"year,omitempty"`
+	StartDate string `json:"start_date,omitempty"`
+	EndDate   string `json:"end_date,omitempty"`
+}
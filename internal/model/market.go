@@ -0,0 +1,145 @@
+package model
+
+type MarketLeaderRequest struct {
+	Date      string   `json:"date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Types     []string `json:"type,omitempty" validate:"dive,oneof=gainers losers most_value most_volume most_frequency foreign_net_buy foreign_net_sell"`
+	Board     string   `json:"board,omitempty" example:"Utama"`
+	MinValue  float64  `json:"min_value,omitempty" validate:"min=0"`
+	MinVolume float64  `json:"min_volume,omitempty" validate:"min=0"`
+	Limit     int64    `json:"limit,omitempty" validate:"min=1,max=100"`
+}
+
+type MarketLeadersResponse struct {
+	Date         string              `json:"date"`
+	Leaderboards []MarketLeaderboard `json:"leaderboards"`
+}
+
+type MarketLeaderboard struct {
+	Name    string                 `json:"name" example:"gainers"`
+	Leaders []MarketLeaderResponse `json:"leaders"`
+}
+
+type MarketLeaderResponse struct {
+	StockCode       string  `json:"stock_code"`
+	StockName       string  `json:"stock_name"`
+	Previous        float64 `json:"previous"`
+	Close           float64 `json:"close"`
+	Change          float64 `json:"change"`
+	ChangePct       float64 `json:"change_pct"`
+	Volume          float64 `json:"volume"`
+	Value           float64 `json:"value"`
+	Frequency       float64 `json:"frequency"`
+	ForeignBuy      float64 `json:"foreign_buy"`
+	ForeignSell     float64 `json:"foreign_sell"`
+	ForeignNet      float64 `json:"foreign_net"`
+	ForeignNetValue float64 `json:"foreign_net_value"`
+}
+
+type SectorRequest struct {
+	Group     string `json:"group,omitempty" validate:"omitempty,oneof=sector sub_sector industry sub_industry"`
+	StartDate string `json:"start_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   string `json:"end_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type SectorOverviewResponse struct {
+	Group     string           `json:"group" example:"sector"`
+	StartDate string           `json:"start_date"`
+	EndDate   string           `json:"end_date"`
+	Sectors   []SectorResponse `json:"sectors"`
+}
+
+type SectorResponse struct {
+	Name string `json:"name" example:"Keuangan"`
+	// ReturnPct is weighted by the market capitalization before the range.
+	ReturnPct       float64                     `json:"return_pct"`
+	MarketCap       float64                     `json:"market_cap"`
+	Value           float64                     `json:"value"`
+	ForeignNetValue float64                     `json:"foreign_net_value"`
+	Advancers       int                         `json:"advancers"`
+	Decliners       int                         `json:"decliners"`
+	Unchanged       int                         `json:"unchanged"`
+	Constituents    []SectorConstituentResponse `json:"constituents,omitempty"`
+}
+
+type SectorConstituentResponse struct {
+	StockCode       string  `json:"stock_code"`
+	StockName       string  `json:"stock_name"`
+	Previous        float64 `json:"previous"`
+	Close           float64 `json:"close"`
+	ReturnPct       float64 `json:"return_pct"`
+	MarketCap       float64 `json:"market_cap"`
+	WeightPct       float64 `json:"weight_pct"`
+	Value           float64 `json:"value"`
+	Volume          float64 `json:"volume"`
+	ForeignNetValue float64 `json:"foreign_net_value"`
+}
+
+type MarketBreadthRequest struct {
+	StartDate string `json:"start_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   string `json:"end_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type MarketBreadthResponse struct {
+	Date      string `json:"date"`
+	Stocks    int    `json:"stocks"`
+	Advancers int    `json:"advancers"`
+	Decliners int    `json:"decliners"`
+	Unchanged int    `json:"unchanged"`
+	// AdvanceDecline is advancers minus decliners.
+	AdvanceDecline int `json:"advance_decline"`
+	NewHighs       int `json:"new_highs"`
+	NewLows        int `json:"new_lows"`
+	// AboveSMA20Pct is the percentage of the stocks that closed above their 20 day average.
+	AboveSMA20Pct  float64 `json:"above_sma20_pct"`
+	AboveSMA50Pct  float64 `json:"above_sma50_pct"`
+	AboveSMA200Pct float64 `json:"above_sma200_pct"`
+	Value          float64 `json:"value"`
+	Volume         float64 `json:"volume"`
+	Frequency      float64 `json:"frequency"`
+}
+
+type PriceLimitsRequest struct {
+	StockCode string  `json:"stock_code" validate:"required" example:"BBCA"`
+	Price     float64 `json:"price" validate:"gt=0" example:"9012"`
+}
+
+type PriceLimitsResponse struct {
+	StockCode    string  `json:"stock_code"`
+	Board        string  `json:"board"`
+	Price        float64 `json:"price"`
+	TickSize     float64 `json:"tick_size"`
+	Valid        bool    `json:"valid"`
+	RoundedPrice float64 `json:"rounded_price"`
+	RoundDown    float64 `json:"round_down"`
+	RoundUp      float64 `json:"round_up"`
+	// ReferenceDate and ReferenceClose are the latest stored close the limits of
+	// the next session are computed from; omitted without a stored summary.
+	ReferenceDate  string  `json:"reference_date,omitempty"`
+	ReferenceClose float64 `json:"reference_close,omitempty"`
+	ARA            float64 `json:"ara,omitempty"`
+	ARB            float64 `json:"arb,omitempty"`
+	ARAPct         float64 `json:"ara_pct,omitempty"`
+	ARBPct         float64 `json:"arb_pct,omitempty"`
+}
+
+type AutoRejectionRequest struct {
+	StockCode string `json:"stock_code,omitempty"`
+	StartDate string `json:"start_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   string `json:"end_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type AutoRejectionResponse struct {
+	Date      string  `json:"date"`
+	StockCode string  `json:"stock_code"`
+	StockName string  `json:"stock_name"`
+	Board     string  `json:"board"`
+	Previous  float64 `json:"previous"`
+	High      float64 `json:"high"`
+	Low       float64 `json:"low"`
+	Close     float64 `json:"close"`
+	ARA       float64 `json:"ara"`
+	ARB       float64 `json:"arb"`
+	// Limit is the limit reached, ara or arb.
+	Limit         string `json:"limit"`
+	ClosedAtLimit bool   `json:"closed_at_limit"`
+}
@@ -0,0 +1,78 @@
+package model
+
+import "time"
+
+type TradeRequest struct {
+	StockCode string  `json:"stock_code" validate:"required" example:"BBCA"`
+	Date      string  `json:"date" validate:"required,datetime=2006-01-02" example:"2025-01-02"`
+	Side      string  `json:"side" validate:"required,oneof=buy sell" example:"buy"`
+	Price     float64 `json:"price" validate:"gt=0" example:"9750"`
+	Lots      int     `json:"lots" validate:"min=1" example:"10"`
+	// Fee is the total of the broker fee and taxes.
+	Fee float64 `json:"fee" validate:"min=0" example:"14625"`
+}
+
+type TradeResponse struct {
+	ID        string    `json:"id"`
+	Portfolio string    `json:"portfolio"`
+	StockCode string    `json:"stock_code"`
+	Date      string    `json:"date"`
+	Side      string    `json:"side"`
+	Price     float64   `json:"price"`
+	Lots      int       `json:"lots"`
+	Shares    int       `json:"shares"`
+	Fee       float64   `json:"fee"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PortfolioRequest struct {
+	Date string `json:"date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type PortfolioResponse struct {
+	Name           string                   `json:"name"`
+	Date           string                   `json:"date"`
+	MarketValue    float64                  `json:"market_value"`
+	Cost           float64                  `json:"cost"`
+	UnrealizedPnL  float64                  `json:"unrealized_pnl"`
+	RealizedPnL    float64                  `json:"realized_pnl"`
+	DividendIncome float64                  `json:"dividend_income"`
+	Positions      []PositionResponse       `json:"positions"`
+	Dividends      []DividendIncomeResponse `json:"dividends"`
+}
+
+type PositionResponse struct {
+	StockCode      string  `json:"stock_code"`
+	StockName      string  `json:"stock_name"`
+	Shares         float64 `json:"shares"`
+	AverageCost    float64 `json:"average_cost"`
+	Cost           float64 `json:"cost"`
+	Close          float64 `json:"close"`
+	MarketValue    float64 `json:"market_value"`
+	UnrealizedPnL  float64 `json:"unrealized_pnl"`
+	RealizedPnL    float64 `json:"realized_pnl"`
+	DividendIncome float64 `json:"dividend_income"`
+}
+
+type DividendIncomeResponse struct {
+	StockCode    string  `json:"stock_code"`
+	ExDate       string  `json:"ex_date"`
+	PaymentDate  string  `json:"payment_date,omitempty"`
+	CashPerShare float64 `json:"cash_per_share"`
+	Shares       float64 `json:"shares"`
+	Amount       float64 `json:"amount"`
+}
+
+type PortfolioValuesRequest struct {
+	StartDate string `json:"start_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   string `json:"end_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type PortfolioValueResponse struct {
+	Date           string  `json:"date"`
+	MarketValue    float64 `json:"market_value"`
+	Cost           float64 `json:"cost"`
+	UnrealizedPnL  float64 `json:"unrealized_pnl"`
+	RealizedPnL    float64 `json:"realized_pnl"`
+	DividendIncome float64 `json:"dividend_income"`
+}
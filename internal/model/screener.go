@@ -0,0 +1,43 @@
+package model
+
+type ScreenerRequest struct {
+	Filter ScreenerFilter `json:"filter"`
+	Sort   []ScreenerSort `json:"sort" validate:"max=5,dive"`
+	Page   int64          `json:"page" validate:"omitempty,min=1,max=10000"`
+	Limit  int64          `json:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// ScreenerFilter takes one of all, any, not or field. A field is compared with
+// value, a list of values for op "in", or with the field named by value_field.
+type ScreenerFilter struct {
+	All        []ScreenerFilter `json:"all,omitempty"`
+	Any        []ScreenerFilter `json:"any,omitempty"`
+	Not        *ScreenerFilter  `json:"not,omitempty"`
+	Field      string           `json:"field,omitempty" example:"close"`
+	Op         string           `json:"op,omitempty" enums:"eq,ne,gt,gte,lt,lte,in" example:"gt"`
+	Value      any              `json:"value,omitempty"`
+	ValueField string           `json:"value_field,omitempty" example:"sma:50"`
+}
+
+type ScreenerSort struct {
+	Field string `json:"field" validate:"required" example:"value"`
+	Order string `json:"order" validate:"omitempty,oneof=asc desc" example:"desc"`
+}
+
+type ScreenerResponse struct {
+	Date       string             `json:"date"`
+	Data       []ScreenerStockRow `json:"data"`
+	Total      int64              `json:"total"`
+	Page       int64              `json:"page"`
+	Limit      int64              `json:"limit"`
+	TotalPages int64              `json:"total_pages"`
+}
+
+type ScreenerStockRow struct {
+	StockCode string             `json:"stock_code"`
+	StockName string             `json:"stock_name"`
+	Board     string             `json:"board"`
+	Sector    string             `json:"sector"`
+	SubSector string             `json:"sub_sector"`
+	Values    map[string]float64 `json:"values"`
+}
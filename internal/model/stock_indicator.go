@@ -0,0 +1,22 @@
+package model
+
+type StockIndicatorRequest struct {
+	StockCode  string `json:"stock_code" validate:"required,len=4"`
+	StartDate  string `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate    string `json:"end_date" validate:"required,datetime=2006-01-02"`
+	Indicators string `json:"indicators" validate:"required" example:"rsi:14,sma:50"`
+}
+
+type StockIndicatorResponse struct {
+	StockCode  string                 `json:"stock_code"`
+	Dates      []string               `json:"dates"`
+	Close      []float64              `json:"close"`
+	Indicators []StockIndicatorSeries `json:"indicators"`
+}
+
+type StockIndicatorSeries struct {
+	Name string `json:"name" example:"macd:12:26:9"`
+	// Lines maps each output of the indicator to its values, aligned with the
+	// dates; a value is null while the indicator warms up.
+	Lines map[string][]*float64 `json:"lines"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+type WatchlistRequest struct {
+	Name       string   `json:"name" validate:"required,max=100" example:"Banks"`
+	StockCodes []string `json:"stock_codes" validate:"max=200,dive,required" example:"BBCA,BBRI"`
+}
+
+type WatchlistResponse struct {
+	ID        string                   `json:"id"`
+	Name      string                   `json:"name"`
+	Stocks    []WatchlistStockResponse `json:"stocks"`
+	CreatedAt time.Time                `json:"created_at"`
+	UpdatedAt time.Time                `json:"updated_at"`
+}
+
+// WatchlistStockResponse is a stock of a watchlist with its latest summary; the
+// summary fields are empty when no summary is stored.
+type WatchlistStockResponse struct {
+	StockCode string  `json:"stock_code"`
+	StockName string  `json:"stock_name"`
+	Date      string  `json:"date,omitempty"`
+	Close     float64 `json:"close"`
+	Change    float64 `json:"change"`
+	ChangePct float64 `json:"change_pct"`
+	Value     float64 `json:"value"`
+	// ForeignNet is the foreign buy minus the foreign sell.
+	ForeignNet float64 `json:"foreign_net"`
+}
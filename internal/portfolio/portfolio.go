@@ -0,0 +1,197 @@
+// Package portfolio replays the trades of a portfolio into positions, profit and
+// loss and daily values.
+//
+// Positions are kept at average cost: buying adds the price and fee of the shares
+// to the cost, selling realizes the proceeds net of fees minus the average cost of
+// the shares sold. Stock splits and bonus shares change the shares held but not
+// their cost, and cash dividends are earned on the shares held before the ex-date.
+package portfolio
+
+import (
+	"errors"
+	"fmt"
+	"go-stock/internal/adjustment"
+	"go-stock/internal/entity"
+	"go-stock/internal/marketrules"
+	"sort"
+	"time"
+)
+
+var ErrOversold = errors.New("selling more shares than held")
+
+type Result struct {
+	// Positions are the stocks held or traded up to the end date, ordered by code.
+	Positions []entity.Position
+	Dividends []entity.DividendIncome
+	// Values are the values at the close of every trading day from the start date.
+	Values []entity.PortfolioValue
+}
+
+// event is what happens to a stock on a date; corporate actions apply at the
+// open of their ex-date, before the trades of the day.
+type event struct {
+	date      time.Time
+	order     int
+	stockCode string
+	trade     *entity.Trade
+	split     float64
+	dividend  *entity.Dividend
+	summary   *entity.StockSummary
+}
+
+const (
+	orderAction = iota
+	orderTrade
+	orderClose
+)
+
+// Run replays the trades and the corporate actions of the traded stocks up to
+// end, and values the portfolio with the summaries of every trading day.
+func Run(trades []entity.Trade, dividends map[string][]entity.Dividend, summaries []entity.StockSummary, start, end time.Time) (Result, error) {
+	var events []event
+	for i := range trades {
+		if !trades[i].Date.After(end) {
+			events = append(events, event{date: trades[i].Date, order: orderTrade, stockCode: trades[i].StockCode, trade: &trades[i]})
+		}
+	}
+	for code, stockDividends := range dividends {
+		actions, err := adjustment.Actions(stockDividends, func(time.Time) (float64, error) { return 0, nil })
+		if err != nil {
+			return Result{}, err
+		}
+		for _, action := range actions {
+			if action.Shares != 1 && !action.ExDate.After(end) {
+				events = append(events, event{date: action.ExDate, order: orderAction, stockCode: code, split: action.Shares})
+			}
+		}
+		for i := range stockDividends {
+			dividend := &stockDividends[i]
+			if dividend.CashDividendPerShare > 0 && !dividend.ExDate.IsZero() && !dividend.ExDate.After(end) {
+				events = append(events, event{date: dividend.ExDate, order: orderAction, stockCode: code, dividend: dividend})
+			}
+		}
+	}
+	for i := range summaries {
+		if !summaries[i].Date.After(end) {
+			events = append(events, event{date: summaries[i].Date, order: orderClose, stockCode: summaries[i].StockCode, summary: &summaries[i]})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].date.Equal(events[j].date) {
+			return events[i].date.Before(events[j].date)
+		}
+		return events[i].order < events[j].order
+	})
+
+	var result Result
+	positions := make(map[string]*entity.Position)
+	position := func(code string) *entity.Position {
+		if _, ok := positions[code]; !ok {
+			positions[code] = &entity.Position{StockCode: code}
+		}
+		return positions[code]
+	}
+
+	for i, e := range events {
+		switch {
+		case e.summary != nil:
+			// Only the stocks held or traded are valued.
+			if p, ok := positions[e.stockCode]; ok {
+				p.StockName = e.summary.StockName
+				if e.summary.Close > 0 {
+					p.Close = e.summary.Close
+				}
+			}
+		case e.split != 0:
+			if p, ok := positions[e.stockCode]; ok {
+				p.Shares *= e.split
+			}
+		case e.dividend != nil:
+			p, ok := positions[e.stockCode]
+			if !ok || p.Shares <= 0 {
+				break
+			}
+			income := entity.DividendIncome{
+				StockCode:    e.stockCode,
+				ExDate:       e.dividend.ExDate,
+				PaymentDate:  e.dividend.PaymentDate,
+				CashPerShare: e.dividend.CashDividendPerShare,
+				Shares:       p.Shares,
+				Amount:       p.Shares * e.dividend.CashDividendPerShare,
+			}
+			p.DividendIncome += income.Amount
+			result.Dividends = append(result.Dividends, income)
+		default:
+			if err := apply(position(e.stockCode), *e.trade); err != nil {
+				return Result{}, err
+			}
+		}
+
+		// Value the portfolio once the closes of a trading day are all in.
+		lastOfDay := i == len(events)-1 || !events[i+1].date.Equal(e.date)
+		if lastOfDay && e.summary != nil && !e.date.Before(start) {
+			result.Values = append(result.Values, value(e.date, positions))
+		}
+	}
+
+	codes := make([]string, 0, len(positions))
+	for code := range positions {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		result.Positions = append(result.Positions, mark(*positions[code]))
+	}
+	return result, nil
+}
+
+// apply books a trade on the position of its stock.
+func apply(p *entity.Position, trade entity.Trade) error {
+	shares := float64(trade.Lots * marketrules.LotSize)
+	switch trade.Side {
+	case entity.TradeSideBuy:
+		p.Shares += shares
+		p.Cost += shares*trade.Price + trade.Fee
+	case entity.TradeSideSell:
+		// Allow for the rounding of split ratios.
+		if shares > p.Shares+1e-6 {
+			return fmt.Errorf("%w: %s on %s, %v shares held", ErrOversold, trade.StockCode, trade.Date.Format("2006-01-02"), p.Shares)
+		}
+		cost := p.Cost * shares / p.Shares
+		p.RealizedPnL += shares*trade.Price - trade.Fee - cost
+		p.Cost -= cost
+		p.Shares -= shares
+		if p.Shares < 1e-6 {
+			p.Shares, p.Cost = 0, 0
+		}
+	default:
+		return fmt.Errorf("unknown trade side %q", trade.Side)
+	}
+	return nil
+}
+
+// mark values a position at its latest close.
+func mark(p entity.Position) entity.Position {
+	if p.Shares > 0 {
+		p.AverageCost = p.Cost / p.Shares
+	}
+	p.MarketValue = p.Shares * p.Close
+	p.UnrealizedPnL = 0
+	if p.Shares > 0 && p.Close > 0 {
+		p.UnrealizedPnL = p.MarketValue - p.Cost
+	}
+	return p
+}
+
+func value(date time.Time, positions map[string]*entity.Position) entity.PortfolioValue {
+	total := entity.PortfolioValue{Date: date}
+	for _, p := range positions {
+		marked := mark(*p)
+		total.MarketValue += marked.MarketValue
+		total.Cost += marked.Cost
+		total.UnrealizedPnL += marked.UnrealizedPnL
+		total.RealizedPnL += marked.RealizedPnL
+		total.DividendIncome += marked.DividendIncome
+	}
+	return total
+}
@@ -0,0 +1,97 @@
+package portfolio
+
+import (
+	"errors"
+	"go-stock/internal/entity"
+	"math"
+	"testing"
+	"time"
+)
+
+func day(n int) time.Time {
+	return time.Date(2025, time.March, n, 0, 0, 0, 0, time.UTC)
+}
+
+func closes(code string, prices map[int]float64) []entity.StockSummary {
+	var summaries []entity.StockSummary
+	for n, price := range prices {
+		summaries = append(summaries, entity.StockSummary{Date: day(n), StockCode: code, StockName: code + " Tbk", Close: price})
+	}
+	return summaries
+}
+
+func assertFloat(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-6 {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
+
+func TestRun(t *testing.T) {
+	trades := []entity.Trade{
+		{StockCode: "AAAA", Date: day(3), Side: entity.TradeSideBuy, Price: 1000, Lots: 10, Fee: 1500},
+		{StockCode: "AAAA", Date: day(4), Side: entity.TradeSideBuy, Price: 1100, Lots: 10, Fee: 1650},
+		{StockCode: "AAAA", Date: day(6), Side: entity.TradeSideSell, Price: 1200, Lots: 5, Fee: 1500},
+	}
+	dividends := map[string][]entity.Dividend{
+		// Earned on the 2000 shares held before the sale.
+		"AAAA": {{ExDate: day(5), CashDividendPerShare: 20}},
+	}
+	summaries := closes("AAAA", map[int]float64{3: 1000, 4: 1100, 5: 1150, 6: 1200, 7: 1250})
+
+	result, err := Run(trades, dividends, summaries, day(4), day(7))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	// 2000 shares cost 2,103,150; selling 500 realizes 598,500 - 525,787.5.
+	position := result.Positions[0]
+	assertFloat(t, "shares", position.Shares, 1500)
+	assertFloat(t, "cost", position.Cost, 2103150*0.75)
+	assertFloat(t, "average cost", position.AverageCost, 2103150.0/2000)
+	assertFloat(t, "realized", position.RealizedPnL, 598500-525787.5)
+	assertFloat(t, "unrealized", position.UnrealizedPnL, 1500*1250-2103150*0.75)
+	assertFloat(t, "dividend income", position.DividendIncome, 40000)
+	if position.StockName != "AAAA Tbk" || len(result.Dividends) != 1 || result.Dividends[0].Shares != 2000 {
+		t.Errorf("position = %+v, dividends = %+v", position, result.Dividends)
+	}
+
+	if len(result.Values) != 4 || !result.Values[0].Date.Equal(day(4)) {
+		t.Fatalf("values = %+v, want the 4 days from the start", result.Values)
+	}
+	assertFloat(t, "value on the 4th", result.Values[0].MarketValue, 2000*1100)
+	assertFloat(t, "dividends on the 5th", result.Values[1].DividendIncome, 40000)
+	assertFloat(t, "value on the 7th", result.Values[3].MarketValue, 1500*1250)
+}
+
+func TestRunSplit(t *testing.T) {
+	trades := []entity.Trade{
+		{StockCode: "AAAA", Date: day(3), Side: entity.TradeSideBuy, Price: 1000, Lots: 1},
+		// After the 1:5 split the 100 shares became 500.
+		{StockCode: "AAAA", Date: day(6), Side: entity.TradeSideSell, Price: 220, Lots: 5},
+	}
+	dividends := map[string][]entity.Dividend{
+		"AAAA": {{Type: "sp", ExDate: day(5), Ratio1: 1, Ratio2: 5}},
+	}
+
+	result, err := Run(trades, dividends, closes("AAAA", map[int]float64{6: 220}), day(1), day(6))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	assertFloat(t, "shares", result.Positions[0].Shares, 0)
+	assertFloat(t, "realized", result.Positions[0].RealizedPnL, 500*220-100*1000)
+}
+
+func TestRunOversold(t *testing.T) {
+	trades := []entity.Trade{
+		{StockCode: "AAAA", Date: day(3), Side: entity.TradeSideBuy, Price: 1000, Lots: 1},
+		{StockCode: "AAAA", Date: day(4), Side: entity.TradeSideSell, Price: 1000, Lots: 2},
+	}
+	if _, err := Run(trades, nil, nil, day(1), day(4)); !errors.Is(err, ErrOversold) {
+		t.Errorf("Run = %v, want ErrOversold", err)
+	}
+	// Trades after the end date are not replayed.
+	if _, err := Run(trades, nil, nil, day(1), day(3)); err != nil {
+		t.Errorf("Run up to the 3rd = %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"go-stock/internal/entity"
+)
+
+type AlertRuleRepository interface {
+	Upsert(ctx context.Context, rule entity.AlertRule) error
+	// UpdateState stores the evaluation state of a rule, its triggered flag and
+	// evaluation dates, and reports false when the rule no longer exists.
+	UpdateState(ctx context.Context, rule entity.AlertRule) (bool, error)
+	FindOne(ctx context.Context, id string) (*entity.AlertRule, error)
+	All(ctx context.Context) ([]entity.AlertRule, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type AlertRepository interface {
+	// Insert stores an alert unless the rule already fired on the same date, and
+	// reports whether it was stored.
+	Insert(ctx context.Context, alert entity.Alert) (bool, error)
+	Update(ctx context.Context, alert entity.Alert) error
+	// Find returns the latest alerts, of one rule when ruleID is set.
+	Find(ctx context.Context, ruleID string, limit int64) ([]entity.Alert, error)
+	EnsureIndexes(ctx context.Context) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"go-stock/internal/entity"
+)
+
+type BasketRepository interface {
+	Upsert(ctx context.Context, basket entity.Basket) error
+	FindOne(ctx context.Context, id string) (*entity.Basket, error)
+	All(ctx context.Context) ([]entity.Basket, error)
+	Delete(ctx context.Context, id string) error
+}
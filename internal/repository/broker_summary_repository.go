@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"go-stock/internal/entity"
+	"time"
+)
+
+type BrokerSummaryRepository interface {
+	Upsert(ctx context.Context, summary entity.BrokerSummary) error
+	FindOne(ctx context.Context, stockCode string, startDate, endDate time.Time, investorType, board string) (*entity.BrokerSummary, error)
+	EnsureIndexes(ctx context.Context) error
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"go-stock/internal/entity"
+)
+
+type CheckpointRepository interface {
+	Find(ctx context.Context, name string) (*entity.Checkpoint, error)
+	AddDone(ctx context.Context, name string, items []string) error
+	Delete(ctx context.Context, name string) error
+}
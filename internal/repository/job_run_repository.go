@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"go-stock/internal/entity"
+)
+
+type JobRunRepository interface {
+	Save(ctx context.Context, run entity.JobRun) error
+	FindByJob(ctx context.Context, jobName string, limit int64) ([]entity.JobRun, error)
+	FindLatest(ctx context.Context, jobName string) (*entity.JobRun, error)
+}
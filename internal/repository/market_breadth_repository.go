@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"go-stock/internal/entity"
+	"time"
+)
+
+type MarketBreadthRepository interface {
+	Upsert(ctx context.Context, breadth entity.MarketBreadth) error
+	Find(ctx context.Context, startDate, endDate time.Time) ([]entity.MarketBreadth, error)
+	EnsureIndexes(ctx context.Context) error
+}
@@ -3,9 +3,21 @@ package repository
 import (
 	"context"
 	"go-stock/internal/entity"
+	"time"
 )
 
 type StockSummaryRepository interface {
 	BulkUpsert(ctx context.Context, summaries []entity.StockSummary) error
 	Find(ctx context.Context, code string, startDate, endDate string) ([]entity.StockSummary, error)
+	FindDates(ctx context.Context, startDate, endDate time.Time) ([]time.Time, error)
+	FindBefore(ctx context.Context, code string, before time.Time, limit int64) ([]entity.StockSummary, error)
+	// FindLatest returns the latest summary of a stock, whichever day it traded,
+	// or nil when none is stored.
+	FindLatest(ctx context.Context, code string) (*entity.StockSummary, error)
+	FindLatestDate(ctx context.Context) (time.Time, error)
+	FindLeaders(ctx context.Context, date time.Time, metric string, descending bool, filter entity.LeaderFilter, limit int64) ([]entity.MarketLeader, error)
+	FindPeriodSummaries(ctx context.Context, startDate, endDate time.Time) ([]entity.StockPeriodSummary, error)
+	FindRanges(ctx context.Context, startDate, endDate time.Time) ([]entity.StockRange, error)
+	NormalizePercentages(ctx context.Context) (int64, error)
+	EnsureIndexes(ctx context.Context) error
 }
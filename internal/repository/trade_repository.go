@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"go-stock/internal/entity"
+)
+
+type TradeRepository interface {
+	Insert(ctx context.Context, trade entity.Trade) error
+	Find(ctx context.Context, portfolio string) ([]entity.Trade, error)
+	FindOne(ctx context.Context, portfolio, id string) (*entity.Trade, error)
+	Delete(ctx context.Context, portfolio, id string) error
+	EnsureIndexes(ctx context.Context) error
+}
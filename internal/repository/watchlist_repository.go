@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"go-stock/internal/entity"
+)
+
+type WatchlistRepository interface {
+	Upsert(ctx context.Context, watchlist entity.Watchlist) error
+	FindOne(ctx context.Context, userID, id string) (*entity.Watchlist, error)
+	Find(ctx context.Context, userID string) ([]entity.Watchlist, error)
+	Delete(ctx context.Context, userID, id string) error
+	EnsureIndexes(ctx context.Context) error
+}
@@ -0,0 +1,380 @@
+// Package screener filters and sorts stocks by the fields of their latest summary,
+// metrics derived from it, their listing attributes and technical indicators.
+//
+// A filter is a tree: All and Any combine filters, Not negates one and a leaf
+// compares Field with Value or with the field ValueField. Indicator fields are
+// written like the indicator specs, e.g. "sma:50" or "macd:12:26:9.signal" for a
+// line of an indicator with several.
+package screener
+
+import (
+	"fmt"
+	"go-stock/internal/indicator"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Operators of a leaf filter. In takes a list of values.
+const (
+	OpEq  = "eq"
+	OpNe  = "ne"
+	OpGt  = "gt"
+	OpGte = "gte"
+	OpLt  = "lt"
+	OpLte = "lte"
+	OpIn  = "in"
+)
+
+// MaxDepth bounds the nesting of a filter.
+const MaxDepth = 8
+
+// MaxLookback bounds the trading days of history an indicator of a screen may
+// need, since the history of every listed stock is loaded for it.
+const MaxLookback = 250
+
+// Numeric fields read from the latest summary or derived from it. The
+// turnover_ratio is the volume over the tradable shares, the free float, and
+// listed_turnover_ratio the volume over the listed shares.
+var numberFields = []string{
+	"previous", "open_price", "high", "low", "close", "change", "change_pct",
+	"volume", "value", "frequency", "foreign_buy", "foreign_sell", "foreign_net",
+	"foreign_net_value", "listed_shares", "tradeble_shares", "typical_price",
+	"spread", "turnover_ratio", "listed_turnover_ratio", "market_cap",
+}
+
+// Text fields read from the stock.
+var textFields = []string{
+	"stock_code", "stock_name", "board", "sector", "sub_sector", "industry", "sub_industry",
+}
+
+type Filter struct {
+	All        []Filter
+	Any        []Filter
+	Not        *Filter
+	Field      string
+	Op         string
+	Value      any
+	ValueField string
+}
+
+type Sort struct {
+	Field      string
+	Descending bool
+}
+
+// Row holds the values of one stock. Numbers are keyed by field name, a missing
+// number, e.g. an indicator without enough history, fails every comparison.
+type Row struct {
+	StockCode string
+	Numbers   map[string]float64
+	Texts     map[string]string
+}
+
+func (r Row) text(name string) string {
+	if name == "stock_code" {
+		return r.StockCode
+	}
+	return r.Texts[name]
+}
+
+// Field is a field referenced by a filter or a sort.
+type Field struct {
+	Name string
+	// Indicator and Line are set for an indicator field.
+	Indicator *indicator.Spec
+	Line      string
+	Text      bool
+}
+
+// ParseField resolves a field name; indicator names are normalized, e.g. "RSI"
+// becomes "rsi:14.value".
+func ParseField(name string) (Field, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if slices.Contains(numberFields, name) {
+		return Field{Name: name}, nil
+	}
+	if slices.Contains(textFields, name) {
+		return Field{Name: name, Text: true}, nil
+	}
+
+	specName, line, _ := strings.Cut(name, ".")
+	specs, err := indicator.ParseSpecs(specName)
+	if err != nil || len(specs) != 1 {
+		return Field{}, fmt.Errorf("unknown field %q", name)
+	}
+	spec := specs[0]
+
+	lines := indicator.Lines(spec)
+	switch {
+	case line == "" && len(lines) == 1:
+		line = lines[0]
+	case !slices.Contains(lines, line):
+		return Field{}, fmt.Errorf("field %q: %s has the lines %s", name, spec.Name, strings.Join(lines, ", "))
+	}
+	return Field{Name: spec.String() + "." + line, Indicator: &spec, Line: line}, nil
+}
+
+// Predicate tells whether a row matches a filter.
+type Predicate func(row Row) bool
+
+// Compile validates the filter and returns its predicate with the fields it reads.
+// An empty filter matches every row.
+func Compile(filter Filter) (Predicate, []Field, error) {
+	var fields []Field
+	predicate, err := compile(filter, 0, &fields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return predicate, fields, nil
+}
+
+func compile(filter Filter, depth int, fields *[]Field) (Predicate, error) {
+	if depth > MaxDepth {
+		return nil, fmt.Errorf("filter is nested deeper than %d levels", MaxDepth)
+	}
+
+	kinds := 0
+	for _, set := range []bool{len(filter.All) > 0, len(filter.Any) > 0, filter.Not != nil, filter.Field != ""} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds > 1 {
+		return nil, fmt.Errorf("a filter takes one of all, any, not or field")
+	}
+
+	switch {
+	case len(filter.All) > 0 || len(filter.Any) > 0:
+		children := filter.All
+		if len(filter.Any) > 0 {
+			children = filter.Any
+		}
+		predicates := make([]Predicate, 0, len(children))
+		for _, child := range children {
+			predicate, err := compile(child, depth+1, fields)
+			if err != nil {
+				return nil, err
+			}
+			predicates = append(predicates, predicate)
+		}
+		if len(filter.All) > 0 {
+			return func(row Row) bool {
+				for _, predicate := range predicates {
+					if !predicate(row) {
+						return false
+					}
+				}
+				return true
+			}, nil
+		}
+		return func(row Row) bool {
+			for _, predicate := range predicates {
+				if predicate(row) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	case filter.Not != nil:
+		predicate, err := compile(*filter.Not, depth+1, fields)
+		if err != nil {
+			return nil, err
+		}
+		return func(row Row) bool { return !predicate(row) }, nil
+	case filter.Field != "":
+		return compileLeaf(filter, fields)
+	default:
+		return func(Row) bool { return true }, nil
+	}
+}
+
+func compileLeaf(filter Filter, fields *[]Field) (Predicate, error) {
+	field, err := ParseField(filter.Field)
+	if err != nil {
+		return nil, err
+	}
+	*fields = append(*fields, field)
+
+	if filter.ValueField != "" {
+		if filter.Value != nil {
+			return nil, fmt.Errorf("field %q: value and value_field are exclusive", filter.Field)
+		}
+		other, err := ParseField(filter.ValueField)
+		if err != nil {
+			return nil, err
+		}
+		if other.Text != field.Text {
+			return nil, fmt.Errorf("field %q cannot be compared with %q", filter.Field, filter.ValueField)
+		}
+		if filter.Op == OpIn {
+			return nil, fmt.Errorf("field %q: %s takes a list of values", filter.Field, OpIn)
+		}
+		*fields = append(*fields, other)
+
+		if field.Text {
+			compare, err := textComparison(filter.Op)
+			if err != nil {
+				return nil, err
+			}
+			return func(row Row) bool {
+				return compare(row.text(field.Name), row.text(other.Name))
+			}, nil
+		}
+		compare, err := numberComparison(filter.Op)
+		if err != nil {
+			return nil, err
+		}
+		return func(row Row) bool {
+			value, ok := row.Numbers[field.Name]
+			otherValue, otherOk := row.Numbers[other.Name]
+			return ok && otherOk && compare(value, otherValue)
+		}, nil
+	}
+
+	if field.Text {
+		return textLeaf(field, filter)
+	}
+	return numberLeaf(field, filter)
+}
+
+func numberLeaf(field Field, filter Filter) (Predicate, error) {
+	if filter.Op == OpIn {
+		values, ok := filter.Value.([]any)
+		if !ok || len(values) == 0 {
+			return nil, fmt.Errorf("field %q: %s takes a list of values", filter.Field, OpIn)
+		}
+		numbers := make([]float64, 0, len(values))
+		for _, value := range values {
+			number, ok := toNumber(value)
+			if !ok {
+				return nil, fmt.Errorf("field %q: %v is not a number", filter.Field, value)
+			}
+			numbers = append(numbers, number)
+		}
+		return func(row Row) bool {
+			value, ok := row.Numbers[field.Name]
+			return ok && slices.Contains(numbers, value)
+		}, nil
+	}
+
+	compare, err := numberComparison(filter.Op)
+	if err != nil {
+		return nil, err
+	}
+	number, ok := toNumber(filter.Value)
+	if !ok {
+		return nil, fmt.Errorf("field %q: %v is not a number", filter.Field, filter.Value)
+	}
+	return func(row Row) bool {
+		value, ok := row.Numbers[field.Name]
+		return ok && compare(value, number)
+	}, nil
+}
+
+func textLeaf(field Field, filter Filter) (Predicate, error) {
+	if filter.Op == OpIn {
+		values, ok := filter.Value.([]any)
+		if !ok || len(values) == 0 {
+			return nil, fmt.Errorf("field %q: %s takes a list of values", filter.Field, OpIn)
+		}
+		texts := make([]string, 0, len(values))
+		for _, value := range values {
+			text, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("field %q: %v is not a string", filter.Field, value)
+			}
+			texts = append(texts, strings.ToLower(text))
+		}
+		return func(row Row) bool {
+			return slices.Contains(texts, strings.ToLower(row.text(field.Name)))
+		}, nil
+	}
+
+	compare, err := textComparison(filter.Op)
+	if err != nil {
+		return nil, err
+	}
+	text, ok := filter.Value.(string)
+	if !ok {
+		return nil, fmt.Errorf("field %q: %v is not a string", filter.Field, filter.Value)
+	}
+	return func(row Row) bool {
+		return compare(row.text(field.Name), text)
+	}, nil
+}
+
+func numberComparison(op string) (func(a, b float64) bool, error) {
+	switch op {
+	case OpEq:
+		return func(a, b float64) bool { return a == b }, nil
+	case OpNe:
+		return func(a, b float64) bool { return a != b }, nil
+	case OpGt:
+		return func(a, b float64) bool { return a > b }, nil
+	case OpGte:
+		return func(a, b float64) bool { return a >= b }, nil
+	case OpLt:
+		return func(a, b float64) bool { return a < b }, nil
+	case OpLte:
+		return func(a, b float64) bool { return a <= b }, nil
+	default:
+		return nil, fmt.Errorf("unknown operator %q", op)
+	}
+}
+
+// textComparison compares case-insensitively; texts only support eq and ne.
+func textComparison(op string) (func(a, b string) bool, error) {
+	switch op {
+	case OpEq:
+		return strings.EqualFold, nil
+	case OpNe:
+		return func(a, b string) bool { return !strings.EqualFold(a, b) }, nil
+	default:
+		return nil, fmt.Errorf("operator %q does not apply to text", op)
+	}
+}
+
+func toNumber(value any) (float64, bool) {
+	number, ok := value.(float64)
+	if !ok || math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, false
+	}
+	return number, true
+}
+
+// SortRows sorts rows by the given fields, then by stock code. Rows missing a
+// numeric sort field come last.
+func SortRows(rows []Row, sorts []Sort) error {
+	fields := make([]Field, 0, len(sorts))
+	for _, s := range sorts {
+		field, err := ParseField(s.Field)
+		if err != nil {
+			return err
+		}
+		fields = append(fields, field)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		for k, field := range fields {
+			if field.Text {
+				a, b := strings.ToLower(rows[i].text(field.Name)), strings.ToLower(rows[j].text(field.Name))
+				if a != b {
+					return (a < b) != sorts[k].Descending
+				}
+				continue
+			}
+			a, aOk := rows[i].Numbers[field.Name]
+			b, bOk := rows[j].Numbers[field.Name]
+			switch {
+			case aOk != bOk:
+				return aOk
+			case aOk && a != b:
+				return (a < b) != sorts[k].Descending
+			}
+		}
+		return rows[i].StockCode < rows[j].StockCode
+	})
+	return nil
+}
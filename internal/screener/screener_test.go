@@ -0,0 +1,142 @@
+package screener
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func rows() []Row {
+	return []Row{
+		{StockCode: "BBCA", Numbers: map[string]float64{"close": 9775, "value": 52e9, "foreign_net": 100, "sma:50.value": 9600}, Texts: map[string]string{"board": "Utama", "sector": "Keuangan"}},
+		{StockCode: "BBRI", Numbers: map[string]float64{"close": 4000, "value": 30e9, "foreign_net": -50, "sma:50.value": 4100}, Texts: map[string]string{"board": "Utama", "sector": "Keuangan"}},
+		{StockCode: "GOTO", Numbers: map[string]float64{"close": 70, "value": 5e9, "foreign_net": 10}, Texts: map[string]string{"board": "Utama", "sector": "Teknologi"}},
+		{StockCode: "TLKM", Numbers: map[string]float64{"close": 2800, "value": 20e9, "foreign_net": 20, "sma:50.value": 2700}, Texts: map[string]string{"board": "Utama", "sector": "Infrastruktur"}},
+	}
+}
+
+// decode builds a filter the way the handler does from a JSON body.
+func decode(t *testing.T, body string) Filter {
+	t.Helper()
+	var raw struct {
+		All        []json.RawMessage
+		Any        []json.RawMessage
+		Not        json.RawMessage
+		Field      string
+		Op         string
+		Value      any
+		ValueField string `json:"value_field"`
+	}
+	if err := json.Unmarshal([]byte(body), &raw); err != nil {
+		t.Fatalf("decode %s: %v", body, err)
+	}
+	filter := Filter{Field: raw.Field, Op: raw.Op, Value: raw.Value, ValueField: raw.ValueField}
+	for _, child := range raw.All {
+		filter.All = append(filter.All, decode(t, string(child)))
+	}
+	for _, child := range raw.Any {
+		filter.Any = append(filter.Any, decode(t, string(child)))
+	}
+	if raw.Not != nil {
+		not := decode(t, string(raw.Not))
+		filter.Not = &not
+	}
+	return filter
+}
+
+func match(t *testing.T, body string) string {
+	t.Helper()
+	predicate, _, err := Compile(decode(t, body))
+	if err != nil {
+		t.Fatalf("Compile(%s): %v", body, err)
+	}
+	var codes []string
+	for _, row := range rows() {
+		if predicate(row) {
+			codes = append(codes, row.StockCode)
+		}
+	}
+	return strings.Join(codes, ",")
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   string
+	}{
+		{`{}`, "BBCA,BBRI,GOTO,TLKM"},
+		{`{"field": "value", "op": "gt", "value": 10e9}`, "BBCA,BBRI,TLKM"},
+		{`{"all": [
+			{"field": "close", "op": "gt", "value_field": "SMA:50"},
+			{"field": "value", "op": "gt", "value": 10e9},
+			{"field": "foreign_net", "op": "gt", "value": 0}
+		]}`, "BBCA,TLKM"},
+		{`{"any": [{"field": "sector", "op": "eq", "value": "teknologi"}, {"field": "close", "op": "lte", "value": 2800}]}`, "GOTO,TLKM"},
+		{`{"not": {"field": "sector", "op": "in", "value": ["Keuangan", "Infrastruktur"]}}`, "GOTO"},
+		{`{"field": "stock_code", "op": "ne", "value": "BBCA"}`, "BBRI,GOTO,TLKM"},
+		// GOTO has no SMA, which fails the comparison either way.
+		{`{"field": "sma:50", "op": "lt", "value_field": "close"}`, "BBCA,TLKM"},
+	}
+	for _, tt := range tests {
+		if got := match(t, tt.filter); got != tt.want {
+			t.Errorf("%s matched %s, want %s", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, body := range []string{
+		`{"field": "price", "op": "gt", "value": 1}`,
+		`{"field": "close", "op": "between", "value": 1}`,
+		`{"field": "close", "op": "gt", "value": "1"}`,
+		`{"field": "sector", "op": "gt", "value": "Keuangan"}`,
+		`{"field": "close", "op": "in", "value": 1}`,
+		`{"field": "close", "op": "gt", "value_field": "sector"}`,
+		`{"field": "macd.slow", "op": "gt", "value": 0}`,
+		`{"field": "close", "op": "gt", "value": 1, "all": [{}]}`,
+	} {
+		if _, _, err := Compile(decode(t, body)); err == nil {
+			t.Errorf("Compile(%s) succeeded, want an error", body)
+		}
+	}
+}
+
+func TestParseField(t *testing.T) {
+	for name, want := range map[string]string{
+		"Close":           "close",
+		"rsi":             "rsi:14.value",
+		"macd.signal":     "macd:12:26:9.signal",
+		"bb:20:2.upper":   "bb:20:2.upper",
+		"bollinger.lower": "bb:20:2.lower",
+	} {
+		field, err := ParseField(name)
+		if err != nil || field.Name != want {
+			t.Errorf("ParseField(%q) = %q, %v; want %q", name, field.Name, err, want)
+		}
+	}
+}
+
+func TestSortRows(t *testing.T) {
+	sorted := rows()
+	if err := SortRows(sorted, []Sort{{Field: "sma:50", Descending: true}}); err != nil {
+		t.Fatalf("SortRows: %v", err)
+	}
+	var codes []string
+	for _, row := range sorted {
+		codes = append(codes, row.StockCode)
+	}
+	if got := strings.Join(codes, ","); got != "BBCA,BBRI,TLKM,GOTO" {
+		t.Errorf("sorted = %s, want the row without the field last", got)
+	}
+
+	if err := SortRows(sorted, []Sort{{Field: "sector"}, {Field: "close", Descending: true}}); err != nil {
+		t.Fatalf("SortRows: %v", err)
+	}
+	codes = codes[:0]
+	for _, row := range sorted {
+		codes = append(codes, row.StockCode)
+	}
+	if got := strings.Join(codes, ","); got != "TLKM,BBCA,BBRI,GOTO" {
+		t.Errorf("sorted = %s", got)
+	}
+}
@@ -51,6 +51,14 @@ func Created(w http.ResponseWriter, data interface{}, message string) {
 	Write(w, http.StatusCreated, message, data, nil)
 }
 
+// Accepted returns a 202 Accepted JSON response with data.
+func Accepted(w http.ResponseWriter, data interface{}, message string) {
+	if message == "" {
+		message = "Accepted"
+	}
+	Write(w, http.StatusAccepted, message, data, nil)
+}
+
 // BadRequest returns a 400 Bad Request response with error details.
 func BadRequest(w http.ResponseWriter, message string, errs []Error) {
 	if message == "" {
@@ -91,6 +99,30 @@ func NotFound(w http.ResponseWriter, message string) {
 	Write(w, http.StatusNotFound, message, nil, nil)
 }
 
+// MethodNotAllowed returns a 405 Method Not Allowed response.
+func MethodNotAllowed(w http.ResponseWriter, message string) {
+	if message == "" {
+		message = "Method Not Allowed"
+	}
+	Write(w, http.StatusMethodNotAllowed, message, nil, nil)
+}
+
+// Conflict returns a 409 Conflict response.
+func Conflict(w http.ResponseWriter, message string) {
+	if message == "" {
+		message = "Conflict"
+	}
+	Write(w, http.StatusConflict, message, nil, nil)
+}
+
+// ServiceUnavailable returns a 503 Service Unavailable response.
+func ServiceUnavailable(w http.ResponseWriter, message string) {
+	if message == "" {
+		message = "Service Unavailable"
+	}
+	Write(w, http.StatusServiceUnavailable, message, nil, nil)
+}
+
 // defaultMessage maps HTTP status codes to default messages.
 func defaultMessage(code int) string {
 	switch code {
@@ -98,6 +130,8 @@ func defaultMessage(code int) string {
 		return "Request successful"
 	case http.StatusCreated:
 		return "Resource created"
+	case http.StatusAccepted:
+		return "Request accepted"
 	case http.StatusBadRequest:
 		return "Invalid request"
 	case http.StatusUnauthorized:
@@ -106,8 +140,14 @@ func defaultMessage(code int) string {
 		return "Access denied"
 	case http.StatusNotFound:
 		return "Resource not found"
+	case http.StatusMethodNotAllowed:
+		return "Method not allowed"
+	case http.StatusConflict:
+		return "Resource conflict"
 	case http.StatusInternalServerError:
 		return "Something went wrong on the server"
+	case http.StatusServiceUnavailable:
+		return "Service temporarily unavailable"
 	default:
 		return http.StatusText(code)
 	}
@@ -0,0 +1,203 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrUpstreamUnavailable is returned without sending the request while the circuit
+// breaker of the host is open.
+var ErrUpstreamUnavailable = errors.New("upstream unavailable")
+
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// CircuitBreakerConfig configures when a host is considered down and how it is probed.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit,
+	// zero disables the breaker.
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before trial requests are let through.
+	OpenTimeout time.Duration
+	// HalfOpenMaxRequests is the number of concurrent trial requests while half-open.
+	HalfOpenMaxRequests int
+	// FailureStatusCodes are the response status codes counted as failures, next to
+	// transport errors and Cloudflare challenges.
+	FailureStatusCodes []int
+}
+
+// DefaultFailureStatusCodes cover blocked, throttled and failing upstreams.
+var DefaultFailureStatusCodes = []int{
+	http.StatusForbidden,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// CircuitBreakerStatus is a snapshot of a breaker for health reporting.
+type CircuitBreakerStatus struct {
+	Host                string
+	State               CircuitState
+	ConsecutiveFailures int
+	OpenedAt            time.Time
+	LastError           string
+}
+
+// CircuitBreaker stops requests to a host after repeated failures. It is safe for
+// concurrent use.
+type CircuitBreaker interface {
+	// Allow reports whether a request may be sent; every allowed request must be
+	// followed by Record with the context it was sent with.
+	Allow() error
+	Record(ctx context.Context, statusCode int, header http.Header, err error)
+	Status() CircuitBreakerStatus
+}
+
+type circuitBreaker struct {
+	host   string
+	config CircuitBreakerConfig
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	trials    int
+	lastError string
+}
+
+func NewCircuitBreaker(host string, config CircuitBreakerConfig) CircuitBreaker {
+	if config.HalfOpenMaxRequests < 1 {
+		config.HalfOpenMaxRequests = 1
+	}
+	if len(config.FailureStatusCodes) == 0 {
+		config.FailureStatusCodes = DefaultFailureStatusCodes
+	}
+	return &circuitBreaker{
+		host:   host,
+		config: config,
+		state:  CircuitClosed,
+	}
+}
+
+func (b *circuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen {
+		remaining := b.config.OpenTimeout - time.Since(b.openedAt)
+		if remaining > 0 {
+			return fmt.Errorf("%w: %s is failing (%s), retry in %s", ErrUpstreamUnavailable, b.host, b.lastError, remaining.Round(time.Second))
+		}
+		b.state = CircuitHalfOpen
+		b.trials = 0
+	}
+
+	if b.state == CircuitHalfOpen {
+		if b.trials >= b.config.HalfOpenMaxRequests {
+			return fmt.Errorf("%w: %s is being probed", ErrUpstreamUnavailable, b.host)
+		}
+		b.trials++
+	}
+
+	return nil
+}
+
+func (b *circuitBreaker) Record(ctx context.Context, statusCode int, header http.Header, err error) {
+	failure := ""
+	switch {
+	case err != nil:
+		failure = err.Error()
+	case header.Get("cf-mitigated") != "":
+		failure = fmt.Sprintf("cloudflare %s", header.Get("cf-mitigated"))
+	case slices.Contains(b.config.FailureStatusCodes, statusCode):
+		failure = fmt.Sprintf("status code %d", statusCode)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitHalfOpen {
+		b.trials--
+	}
+
+	// A request whose context was cancelled or timed out says nothing about the
+	// upstream.
+	if ctx.Err() != nil {
+		return
+	}
+
+	if failure == "" {
+		b.state = CircuitClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	b.lastError = failure
+	if b.state == CircuitHalfOpen || b.failures >= b.config.FailureThreshold {
+		b.state = CircuitOpen
+		b.openedAt = time.Now()
+	}
+}
+
+func (b *circuitBreaker) Status() CircuitBreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return CircuitBreakerStatus{
+		Host:                b.host,
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		OpenedAt:            b.openedAt,
+		LastError:           b.lastError,
+	}
+}
+
+var sharedBreakers = struct {
+	sync.Mutex
+	byHost map[string]CircuitBreaker
+}{byHost: make(map[string]CircuitBreaker)}
+
+// SharedCircuitBreaker returns the breaker of the host in baseURL, creating it with
+// config on first use, so every client of a host trips the same breaker.
+func SharedCircuitBreaker(baseURL string, config CircuitBreakerConfig) CircuitBreaker {
+	host := hostOf(baseURL)
+
+	sharedBreakers.Lock()
+	defer sharedBreakers.Unlock()
+
+	breaker, ok := sharedBreakers.byHost[host]
+	if !ok {
+		breaker = NewCircuitBreaker(host, config)
+		sharedBreakers.byHost[host] = breaker
+	}
+	return breaker
+}
+
+// CircuitBreakers returns the status of every shared breaker, ordered by host.
+func CircuitBreakers() []CircuitBreakerStatus {
+	sharedBreakers.Lock()
+	statuses := make([]CircuitBreakerStatus, 0, len(sharedBreakers.byHost))
+	for _, breaker := range sharedBreakers.byHost {
+		statuses = append(statuses, breaker.Status())
+	}
+	sharedBreakers.Unlock()
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Host < statuses[j].Host
+	})
+	return statuses
+}
@@ -0,0 +1,136 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	ctx := context.Background()
+	breaker := NewCircuitBreaker("idx.example", CircuitBreakerConfig{
+		FailureThreshold:    2,
+		OpenTimeout:         50 * time.Millisecond,
+		HalfOpenMaxRequests: 2,
+	})
+	state := func() CircuitState { return breaker.Status().State }
+
+	// A success resets the consecutive failures while closed.
+	for _, status := range []int{http.StatusBadGateway, http.StatusOK, http.StatusBadGateway} {
+		if err := breaker.Allow(); err != nil {
+			t.Fatalf("Allow while closed: %v", err)
+		}
+		breaker.Record(ctx, status, nil, nil)
+	}
+	if state() != CircuitClosed || breaker.Status().ConsecutiveFailures != 1 {
+		t.Fatalf("status = %+v, want closed with 1 failure", breaker.Status())
+	}
+
+	breaker.Allow()
+	breaker.Record(ctx, 0, nil, errors.New("connection reset"))
+	if state() != CircuitOpen || breaker.Status().LastError != "connection reset" {
+		t.Fatalf("status = %+v, want open after 2 failures", breaker.Status())
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Fatalf("Allow while open = %v, want ErrUpstreamUnavailable", err)
+	}
+
+	// Once the timeout passed, only HalfOpenMaxRequests trials are let through.
+	time.Sleep(60 * time.Millisecond)
+	for i := range 2 {
+		if err := breaker.Allow(); err != nil {
+			t.Fatalf("trial %d: %v", i+1, err)
+		}
+	}
+	if state() != CircuitHalfOpen {
+		t.Fatalf("state = %s, want half-open", state())
+	}
+	if err := breaker.Allow(); !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Fatalf("third trial = %v, want ErrUpstreamUnavailable", err)
+	}
+
+	// A failed trial opens the circuit again at once.
+	breaker.Record(ctx, http.StatusForbidden, nil, nil)
+	if state() != CircuitOpen {
+		t.Fatalf("state = %s, want open after a failed trial", state())
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if err := breaker.Allow(); err != nil {
+		t.Fatalf("trial: %v", err)
+	}
+	breaker.Record(ctx, http.StatusOK, nil, nil)
+	if status := breaker.Status(); status.State != CircuitClosed || status.ConsecutiveFailures != 0 {
+		t.Fatalf("status = %+v, want closed after a successful trial", status)
+	}
+}
+
+func TestCircuitBreakerFailures(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	challenge := http.Header{}
+	challenge.Set("cf-mitigated", "challenge")
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		status  int
+		header  http.Header
+		err     error
+		failure bool
+	}{
+		{"server error", context.Background(), http.StatusServiceUnavailable, nil, nil, true},
+		{"cloudflare challenge", context.Background(), http.StatusOK, challenge, nil, true},
+		{"transport error", context.Background(), 0, nil, errors.New("connection refused"), true},
+		{"client timeout", context.Background(), 0, nil, context.DeadlineExceeded, true},
+		{"not found", context.Background(), http.StatusNotFound, nil, nil, false},
+		{"cancelled by the caller", cancelled, 0, nil, context.Canceled, false},
+		{"deadline of the caller", expired, 0, nil, context.DeadlineExceeded, false},
+	}
+	for _, tt := range tests {
+		breaker := NewCircuitBreaker("idx.example", CircuitBreakerConfig{FailureThreshold: 5, OpenTimeout: time.Minute})
+		breaker.Allow()
+		breaker.Record(tt.ctx, tt.status, tt.header, tt.err)
+		if failures := breaker.Status().ConsecutiveFailures; (failures == 1) != tt.failure {
+			t.Errorf("%s: %d failures, want failure %v", tt.name, failures, tt.failure)
+		}
+	}
+}
+
+func TestSendRequestCircuitOpen(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewRestClientBuilder().
+		WithBaseURL(server.URL).
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond, RetryableStatusCodes: DefaultRetryableStatusCodes}).
+		WithCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute}).
+		WithRateLimit(RateLimit{RequestsPerSecond: 1, Burst: 2}).
+		Build()
+
+	// The retries stop once the breaker opens after the second failure.
+	_, _, err := client.SendRequest(context.Background(), http.MethodGet, "/", nil, nil)
+	if !errors.Is(err, ErrUpstreamUnavailable) || calls.Load() != 2 {
+		t.Fatalf("err = %v after %d requests, want ErrUpstreamUnavailable after 2", err, calls.Load())
+	}
+
+	// The open circuit fails the request without waiting for the spent rate limit.
+	start := time.Now()
+	if _, _, err := client.SendRequest(context.Background(), http.MethodGet, "/", nil, nil); !errors.Is(err, ErrUpstreamUnavailable) || calls.Load() != 2 {
+		t.Errorf("err = %v after %d requests, want the request failed fast", err, calls.Load())
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("failed after %s, want no wait for the rate limit", elapsed)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
@@ -11,18 +13,23 @@ import (
 )
 
 type RestClient interface {
-	SendRequest(ctx context.Context, method, url string, body interface{}, headers map[string]string) (responseBody []byte, statusCode int, err error)
+	SendRequest(ctx context.Context, method, url string, body interface{}, headers map[string]string, opts ...RequestOption) (responseBody []byte, statusCode int, err error)
 }
 
 type restClient struct {
 	httpClient *http.Client
-	delay      time.Duration
+	limiter    RateLimiter
+	breaker    CircuitBreaker
 	baseURL    string
 	headers    map[string]string
+	retry      RetryPolicy
 }
 
 type RestClientBuilder struct {
-	client *restClient
+	client    *restClient
+	delay     time.Duration
+	rateLimit RateLimit
+	breaker   CircuitBreakerConfig
 }
 
 func NewRestClientBuilder() *RestClientBuilder {
@@ -30,6 +37,7 @@ func NewRestClientBuilder() *RestClientBuilder {
 		client: &restClient{
 			httpClient: &http.Client{},
 			headers:    make(map[string]string),
+			retry:      NoRetry,
 		},
 	}
 }
@@ -49,29 +57,130 @@ func (b *RestClientBuilder) WithHTTPClient(httpClient *http.Client) *RestClientB
 	return b
 }
 
+// WithDelay spaces requests to the host at least delay apart. It is ignored when a
+// rate limit is set.
 func (b *RestClientBuilder) WithDelay(delay time.Duration) *RestClientBuilder {
-	b.client.delay = delay
+	b.delay = delay
+	return b
+}
+
+// WithRateLimit limits the requests to the host of the base URL, shared with every
+// other client of that host.
+func (b *RestClientBuilder) WithRateLimit(limit RateLimit) *RestClientBuilder {
+	b.rateLimit = limit
+	return b
+}
+
+func (b *RestClientBuilder) WithRetryPolicy(policy RetryPolicy) *RestClientBuilder {
+	b.client.retry = policy
+	return b
+}
+
+// WithCircuitBreaker fails requests to the host fast while it is down, sharing the
+// breaker with every other client of that host.
+func (b *RestClientBuilder) WithCircuitBreaker(config CircuitBreakerConfig) *RestClientBuilder {
+	b.breaker = config
 	return b
 }
 
 func (b *RestClientBuilder) Build() RestClient {
+	if b.breaker.FailureThreshold > 0 {
+		b.client.breaker = SharedCircuitBreaker(b.client.baseURL, b.breaker)
+	}
+
+	switch {
+	case b.rateLimit.RequestsPerSecond > 0:
+		b.client.limiter = SharedRateLimiter(b.client.baseURL, b.rateLimit)
+	case b.delay > 0:
+		b.client.limiter = SharedRateLimiter(b.client.baseURL, RateLimit{
+			RequestsPerSecond: float64(time.Second) / float64(b.delay),
+			Burst:             1,
+		})
+	}
 	return b.client
 }
 
-func (r *restClient) SendRequest(ctx context.Context, method, path string, body interface{}, headers map[string]string) (responseBody []byte, statusCode int, err error) {
-	var reqBody io.Reader
+func (r *restClient) SendRequest(ctx context.Context, method, path string, body interface{}, headers map[string]string, opts ...RequestOption) (responseBody []byte, statusCode int, err error) {
+	options := requestOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	policy := r.retry
+	if options.retry != nil {
+		policy = *options.retry
+	}
+
+	var jsonBody []byte
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
+		jsonBody, err = json.Marshal(body)
 		if err != nil {
 			return nil, 0, err
 		}
-		reqBody = bytes.NewBuffer(jsonBody)
 	}
 
 	fullURL := r.baseURL + path
+	maxAttempts := policy.attempts()
+	for attempt := 1; ; attempt++ {
+		if options.attempts != nil {
+			*options.attempts = attempt
+		}
+
+		var header http.Header
+		responseBody, statusCode, header, err = r.do(ctx, method, fullURL, body, jsonBody, headers, attempt)
+
+		retryable := err != nil || policy.retryableStatus(statusCode)
+		if errors.Is(err, ErrUpstreamUnavailable) {
+			retryable = false
+		}
+		if !retryable || attempt >= maxAttempts || ctx.Err() != nil {
+			if err != nil && attempt > 1 {
+				err = fmt.Errorf("after %d attempts: %w", attempt, err)
+			}
+			return responseBody, statusCode, err
+		}
+
+		wait := policy.backoff(attempt)
+		if after, ok := retryAfter(header); ok {
+			if policy.MaxBackoff > 0 && after > policy.MaxBackoff {
+				slog.Warn("HTTP Retry-After exceeds max backoff, giving up",
+					"url", fullURL,
+					"retryAfter", after,
+					"attempt", attempt,
+				)
+				return responseBody, statusCode, err
+			}
+			wait = max(wait, after)
+		}
+
+		slog.Warn("HTTP Request failed, retrying",
+			"method", method,
+			"url", fullURL,
+			"statusCode", statusCode,
+			"error", err,
+			"attempt", attempt,
+			"wait", wait,
+		)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, 0, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// do performs a single attempt of the request.
+func (r *restClient) do(ctx context.Context, method, fullURL string, body interface{}, jsonBody []byte, headers map[string]string, attempt int) ([]byte, int, http.Header, error) {
+	var reqBody io.Reader
+	if jsonBody != nil {
+		reqBody = bytes.NewReader(jsonBody)
+	}
+
 	req, err := http.NewRequestWithContext(ctx, method, fullURL, reqBody)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, nil, err
 	}
 
 	for key, value := range r.headers {
@@ -88,20 +197,36 @@ func (r *restClient) SendRequest(ctx context.Context, method, path string, body
 		"url", fullURL,
 		"headers", r.headers,
 		"body", body,
+		"attempt", attempt,
 	)
 
-	time.Sleep(r.delay)
+	// An open circuit fails fast instead of queueing for a rate limit token.
+	if r.breaker != nil {
+		if err := r.breaker.Allow(); err != nil {
+			return nil, 0, nil, err
+		}
+	}
+
+	if r.limiter != nil {
+		if err := r.limiter.Wait(ctx); err != nil {
+			r.record(ctx, 0, nil, err)
+			return nil, 0, nil, err
+		}
+	}
 
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
-		return nil, 0, err
+		r.record(ctx, 0, nil, err)
+		return nil, 0, nil, err
 	}
 	defer resp.Body.Close()
 
-	responseBody, err = io.ReadAll(resp.Body)
+	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, 0, err
+		r.record(ctx, 0, nil, err)
+		return nil, 0, nil, err
 	}
+	r.record(ctx, resp.StatusCode, resp.Header, nil)
 
 	// Log the response
 	slog.Info("HTTP Response",
@@ -110,5 +235,11 @@ func (r *restClient) SendRequest(ctx context.Context, method, path string, body
 		"body", string(responseBody),
 	)
 
-	return responseBody, resp.StatusCode, nil
+	return responseBody, resp.StatusCode, resp.Header, nil
+}
+
+func (r *restClient) record(ctx context.Context, statusCode int, header http.Header, err error) {
+	if r.breaker != nil {
+		r.breaker.Record(ctx, statusCode, header, err)
+	}
 }
@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// RateLimit is the sustained request rate and the burst allowed on top of it.
+type RateLimit struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+// RateLimiter blocks until a request may be sent. It is safe for concurrent use.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // tokens added per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a token bucket that starts full.
+func NewRateLimiter(limit RateLimit) RateLimiter {
+	burst := float64(max(limit.Burst, 1))
+	return &tokenBucket{
+		rate:   limit.RequestsPerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// Wait takes a token, waiting for the bucket to refill when it is empty. Tokens are
+// reserved in call order, so concurrent callers are spaced out instead of racing.
+// A caller whose ctx ends while waiting hands its reservation back.
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	var wait time.Duration
+	if b.tokens < 0 {
+		wait = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+var sharedLimiters = struct {
+	sync.Mutex
+	byHost map[string]RateLimiter
+}{byHost: make(map[string]RateLimiter)}
+
+// SharedRateLimiter returns the limiter of the host in baseURL, creating it with
+// limit on first use. Every client of a host shares its limiter, so the limit
+// holds across clients; later limits for the same host are ignored.
+func SharedRateLimiter(baseURL string, limit RateLimit) RateLimiter {
+	host := hostOf(baseURL)
+
+	sharedLimiters.Lock()
+	defer sharedLimiters.Unlock()
+
+	limiter, ok := sharedLimiters.byHost[host]
+	if !ok {
+		limiter = NewRateLimiter(limit)
+		sharedLimiters.byHost[host] = limiter
+	}
+	return limiter
+}
+
+// hostOf returns the host of baseURL, or baseURL itself when it has none.
+func hostOf(baseURL string) string {
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return baseURL
+}
@@ -0,0 +1,110 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurst(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{RequestsPerSecond: 10, Burst: 3})
+
+	start := time.Now()
+	for range 3 {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("burst of 3 took %s, want no wait", elapsed)
+	}
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("fourth request after %s, want it to wait for a refill of 100ms", elapsed)
+	}
+
+	// An idle bucket refills up to its burst, not beyond.
+	time.Sleep(500 * time.Millisecond)
+	start = time.Now()
+	for range 4 {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("4 requests after idling took %s, want the fourth to wait", elapsed)
+	}
+}
+
+func TestRateLimiterConcurrentCallers(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{RequestsPerSecond: 20, Burst: 1})
+
+	var (
+		mu    sync.Mutex
+		times []time.Duration
+		wg    sync.WaitGroup
+	)
+	start := time.Now()
+	for range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := limiter.Wait(context.Background()); err != nil {
+				t.Errorf("Wait: %v", err)
+				return
+			}
+			mu.Lock()
+			times = append(times, time.Since(start))
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// The callers are spaced 50ms apart instead of passing together.
+	slices.Sort(times)
+	for i := 1; i < len(times); i++ {
+		if gap := times[i] - times[i-1]; gap < 40*time.Millisecond {
+			t.Errorf("callers %d and %d passed %s apart, want about 50ms", i-1, i, gap)
+		}
+	}
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{RequestsPerSecond: 10, Burst: 1})
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want the deadline", err)
+	}
+
+	// The cancelled caller handed its token back, so the next one waits for a
+	// single refill rather than two.
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("next request after %s, want about 100ms", elapsed)
+	}
+}
+
+func TestSharedRateLimiter(t *testing.T) {
+	first := SharedRateLimiter("https://limiter.example/api", RateLimit{RequestsPerSecond: 1})
+	second := SharedRateLimiter("https://limiter.example/other", RateLimit{RequestsPerSecond: 100})
+	if first != second {
+		t.Error("clients of the same host got different limiters")
+	}
+	if SharedRateLimiter("https://other.example", RateLimit{RequestsPerSecond: 1}) == first {
+		t.Error("clients of different hosts share a limiter")
+	}
+}
@@ -0,0 +1,208 @@
+// Package recorder provides an http.RoundTripper that records upstream responses
+// to fixture files and replays them, so the upstream clients can be tested offline.
+//
+// Plug it into the *http.Client given to a client:
+//
+//	httpClient := &http.Client{Transport: recorder.NewTransport(recorder.ModeFromEnv(), "testdata/fixtures", nil)}
+//	idxClient := idx.NewIdxClient(cfg, httpClient)
+//
+// Run the tests with GOSTOCK_HTTP_FIXTURES=record to refresh the fixtures from the
+// live sites.
+package recorder
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type Mode string
+
+const (
+	// ModeReplay serves every request from its fixture and fails when there is none.
+	ModeReplay Mode = "replay"
+	// ModeRecord sends every request upstream and stores the response as its fixture.
+	ModeRecord Mode = "record"
+)
+
+// ModeFromEnv returns ModeRecord when GOSTOCK_HTTP_FIXTURES is "record", and
+// ModeReplay otherwise.
+func ModeFromEnv() Mode {
+	if Mode(os.Getenv("GOSTOCK_HTTP_FIXTURES")) == ModeRecord {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// Fixture is a recorded request and its response.
+type Fixture struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+type FixtureRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// FixtureResponse keeps a JSON body as-is for readable fixtures, any other body
+// is stored as text.
+type FixtureResponse struct {
+	StatusCode int             `json:"status_code"`
+	Header     http.Header     `json:"header"`
+	Body       string          `json:"body,omitempty"`
+	JSONBody   json.RawMessage `json:"json_body,omitempty"`
+}
+
+// volatileHeaders change on every response and are not worth recording.
+var volatileHeaders = map[string]bool{
+	"Connection":        true,
+	"Content-Length":    true,
+	"Date":              true,
+	"Set-Cookie":        true,
+	"Transfer-Encoding": true,
+}
+
+type transport struct {
+	mode Mode
+	dir  string
+	next http.RoundTripper
+}
+
+// NewTransport returns a transport that records to or replays from the fixtures in
+// dir. next sends the requests while recording, http.DefaultTransport when nil.
+func NewTransport(mode Mode, dir string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{
+		mode: mode,
+		dir:  dir,
+		next: next,
+	}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := filepath.Join(t.dir, FixtureName(req.Method, req.URL.RequestURI()))
+
+	if t.mode == ModeRecord {
+		return t.record(req, path)
+	}
+	return t.replay(req, path)
+}
+
+func (t *transport) replay(req *http.Request, path string) (*http.Response, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("recorder: no fixture for %s %s (expected %s)", req.Method, req.URL.RequestURI(), path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("recorder: %w", err)
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("recorder: invalid fixture %s: %w", path, err)
+	}
+
+	body := []byte(fixture.Response.Body)
+	if len(fixture.Response.JSONBody) > 0 {
+		body = fixture.Response.JSONBody
+	}
+
+	header := fixture.Response.Header
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Response.StatusCode, http.StatusText(fixture.Response.StatusCode)),
+		StatusCode:    fixture.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (t *transport) record(req *http.Request, path string) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	fixture := Fixture{
+		Request: FixtureRequest{
+			Method: req.Method,
+			URL:    req.URL.RequestURI(),
+		},
+		Response: FixtureResponse{
+			StatusCode: resp.StatusCode,
+			Header:     make(http.Header),
+		},
+	}
+	for key, values := range resp.Header {
+		if !volatileHeaders[key] {
+			fixture.Response.Header[key] = values
+		}
+	}
+	if json.Valid(body) {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, body, "", "  "); err != nil {
+			return nil, err
+		}
+		fixture.Response.JSONBody = indented.Bytes()
+	} else {
+		fixture.Response.Body = string(body)
+	}
+
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(fixture); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("recorder: %w", err)
+	}
+	if err := os.WriteFile(path, data.Bytes(), 0o644); err != nil {
+		return nil, fmt.Errorf("recorder: %w", err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	return resp, nil
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// FixtureName returns the file name of the fixture for a request: a readable part
+// of the path and query followed by a hash of the full request URI.
+func FixtureName(method, requestURI string) string {
+	sum := sha1.Sum([]byte(method + " " + requestURI))
+
+	name := strings.Trim(unsafeChars.ReplaceAllString(requestURI, "_"), "_")
+	if len(name) > 80 {
+		name = name[:80]
+	}
+
+	return fmt.Sprintf("%s_%s_%s.json", strings.ToLower(method), name, hex.EncodeToString(sum[:4]))
+}
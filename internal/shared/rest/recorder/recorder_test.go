@@ -0,0 +1,109 @@
+package recorder
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordThenReplay(t *testing.T) {
+	dir := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Cf-Mitigated", "challenge")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, "<html>blocked</html>")
+	}))
+	defer server.Close()
+
+	recording := &http.Client{Transport: NewTransport(ModeRecord, dir, nil)}
+	resp, err := recording.Get(server.URL + "/stock?code=BBCA")
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "<html>blocked</html>" {
+		t.Fatalf("recorded body = %q, want the upstream body", body)
+	}
+
+	fixture, err := os.ReadFile(filepath.Join(dir, FixtureName(http.MethodGet, "/stock?code=BBCA")))
+	if err != nil {
+		t.Fatalf("fixture not written: %v", err)
+	}
+	if strings.Contains(string(fixture), "secret") {
+		t.Errorf("fixture contains the Set-Cookie header: %s", fixture)
+	}
+
+	// The server is gone, the response must come from the fixture.
+	server.Close()
+	replaying := &http.Client{Transport: NewTransport(ModeReplay, dir, nil)}
+	resp, err = replaying.Get("https://upstream.example/stock?code=BBCA")
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ = io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusForbidden)
+	}
+	if resp.Header.Get("Cf-Mitigated") != "challenge" {
+		t.Errorf("Cf-Mitigated header = %q, want challenge", resp.Header.Get("Cf-Mitigated"))
+	}
+	if string(body) != "<html>blocked</html>" {
+		t.Errorf("replayed body = %q, want the recorded body", body)
+	}
+}
+
+func TestRecordJSONBody(t *testing.T) {
+	dir := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"data":[{"Code":"BBCA"}]}`)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(ModeRecord, dir, nil)}
+	resp, err := client.Get(server.URL + "/list")
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	resp.Body.Close()
+
+	fixture, err := os.ReadFile(filepath.Join(dir, FixtureName(http.MethodGet, "/list")))
+	if err != nil {
+		t.Fatalf("fixture not written: %v", err)
+	}
+	if !strings.Contains(string(fixture), `"json_body": {`) {
+		t.Errorf("JSON body is not stored as JSON: %s", fixture)
+	}
+}
+
+func TestReplayMissingFixture(t *testing.T) {
+	client := &http.Client{Transport: NewTransport(ModeReplay, t.TempDir(), nil)}
+
+	_, err := client.Get("https://upstream.example/missing")
+	if err == nil || !strings.Contains(err.Error(), "no fixture for GET /missing") {
+		t.Fatalf("err = %v, want a missing fixture error", err)
+	}
+}
+
+func TestFixtureName(t *testing.T) {
+	a := FixtureName(http.MethodGet, "/data?start=01/02/2025&end=01/02/2025")
+	b := FixtureName(http.MethodGet, "/data?start=01/02/2025&end=01/03/2025")
+
+	if a == b {
+		t.Errorf("different requests share the fixture %s", a)
+	}
+	if a != FixtureName(http.MethodGet, "/data?start=01/02/2025&end=01/02/2025") {
+		t.Errorf("fixture name is not stable")
+	}
+	if strings.ContainsAny(a, "/?&=") {
+		t.Errorf("fixture name %s is not a safe file name", a)
+	}
+}
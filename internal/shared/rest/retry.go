@@ -0,0 +1,102 @@
+package rest
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how often and how fast a failed request is retried.
+// Transport errors and responses with one of RetryableStatusCodes are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, a value below 2 disables retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, it doubles on every retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts. A Retry-After header asking for a
+	// longer wait ends the retries instead.
+	MaxBackoff time.Duration
+	// Jitter randomly shortens each wait by up to this fraction (0 to 1).
+	Jitter float64
+	// RetryableStatusCodes lists the response status codes worth retrying.
+	RetryableStatusCodes []int
+}
+
+// DefaultRetryableStatusCodes are the throttling and transient server errors.
+var DefaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// NoRetry makes a single attempt.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) retryableStatus(statusCode int) bool {
+	return slices.Contains(p.RetryableStatusCodes, statusCode)
+}
+
+// backoff returns the wait before the given retry (1 for the first retry).
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	wait := p.InitialBackoff
+	for i := 1; i < retry && (p.MaxBackoff <= 0 || wait < p.MaxBackoff); i++ {
+		wait *= 2
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+
+	if p.Jitter > 0 && wait > 0 {
+		jitter := min(p.Jitter, 1)
+		wait -= time.Duration(rand.Float64() * jitter * float64(wait))
+	}
+	return wait
+}
+
+// retryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// RequestOption customizes a single SendRequest call.
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	retry    *RetryPolicy
+	attempts *int
+}
+
+// WithRetry overrides the client's retry policy for one request.
+func WithRetry(policy RetryPolicy) RequestOption {
+	return func(o *requestOptions) {
+		o.retry = &policy
+	}
+}
+
+// WithAttemptCount stores the number of attempts the request took in attempts.
+func WithAttemptCount(attempts *int) RequestOption {
+	return func(o *requestOptions) {
+		o.attempts = attempts
+	}
+}
@@ -0,0 +1,145 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for retry, want := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		if got := policy.backoff(retry + 1); got != want*time.Millisecond {
+			t.Errorf("backoff(%d) = %s, want %s", retry+1, got, want*time.Millisecond)
+		}
+	}
+
+	// The cap holds long after doubling would overflow.
+	if got := policy.backoff(100); got != time.Second {
+		t.Errorf("backoff(100) = %s, want the 1s cap", got)
+	}
+
+	policy.Jitter = 0.5
+	for range 100 {
+		if got := policy.backoff(4); got < 400*time.Millisecond || got > 800*time.Millisecond {
+			t.Fatalf("backoff(4) with jitter = %s, want between 400ms and 800ms", got)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		ok    bool
+		min   time.Duration
+		max   time.Duration
+	}{
+		{"", false, 0, 0},
+		{"3", true, 3 * time.Second, 3 * time.Second},
+		{"-1", false, 0, 0},
+		{"soon", false, 0, 0},
+		{time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat), true, 8 * time.Second, 10 * time.Second},
+		{time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), true, 0, 0},
+	}
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.value != "" {
+			header.Set("Retry-After", tt.value)
+		}
+		got, ok := retryAfter(header)
+		if ok != tt.ok || got < tt.min || got > tt.max {
+			t.Errorf("retryAfter(%q) = %s, %v; want %s to %s, %v", tt.value, got, ok, tt.min, tt.max, tt.ok)
+		}
+	}
+}
+
+// newFlakyServer fails the first failures requests with status, sending
+// retryAfter when set, and succeeds afterwards.
+func newFlakyServer(t *testing.T, failures int32, status int, retryAfter string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestSendRequestRetries(t *testing.T) {
+	server, calls := newFlakyServer(t, 2, http.StatusBadGateway, "")
+	client := NewRestClientBuilder().
+		WithBaseURL(server.URL).
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, RetryableStatusCodes: DefaultRetryableStatusCodes}).
+		Build()
+
+	var attempts int
+	body, status, err := client.SendRequest(context.Background(), http.MethodGet, "/", nil, nil, WithAttemptCount(&attempts))
+	if err != nil || status != http.StatusOK || string(body) != `{"ok":true}` {
+		t.Fatalf("got %d %s, %v; want the third attempt to succeed", status, body, err)
+	}
+	if attempts != 3 || calls.Load() != 3 {
+		t.Errorf("took %d attempts and %d requests, want 3", attempts, calls.Load())
+	}
+
+	// A status not worth retrying is returned right away.
+	server, calls = newFlakyServer(t, 1, http.StatusNotFound, "")
+	client = NewRestClientBuilder().
+		WithBaseURL(server.URL).
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, RetryableStatusCodes: DefaultRetryableStatusCodes}).
+		Build()
+	if _, status, err := client.SendRequest(context.Background(), http.MethodGet, "/", nil, nil); err != nil || status != http.StatusNotFound || calls.Load() != 1 {
+		t.Errorf("got %d, %v after %d requests; want the 404 without retries", status, err, calls.Load())
+	}
+}
+
+func TestSendRequestRetryAfter(t *testing.T) {
+	server, calls := newFlakyServer(t, 1, http.StatusTooManyRequests, "1")
+	client := NewRestClientBuilder().
+		WithBaseURL(server.URL).
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Second, RetryableStatusCodes: DefaultRetryableStatusCodes}).
+		Build()
+
+	start := time.Now()
+	if _, status, err := client.SendRequest(context.Background(), http.MethodGet, "/", nil, nil); err != nil || status != http.StatusOK {
+		t.Fatalf("got %d, %v; want the retry to succeed", status, err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want the 1s of Retry-After", elapsed)
+	}
+
+	// A Retry-After beyond the backoff cap ends the retries.
+	server, calls = newFlakyServer(t, 1, http.StatusServiceUnavailable, "60")
+	client = NewRestClientBuilder().
+		WithBaseURL(server.URL).
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Second, RetryableStatusCodes: DefaultRetryableStatusCodes}).
+		Build()
+	if _, status, err := client.SendRequest(context.Background(), http.MethodGet, "/", nil, nil); err != nil || status != http.StatusServiceUnavailable || calls.Load() != 1 {
+		t.Errorf("got %d, %v after %d requests; want the 503 without retries", status, err, calls.Load())
+	}
+}
+
+func TestSendRequestCancelledDuringBackoff(t *testing.T) {
+	server, calls := newFlakyServer(t, 3, http.StatusInternalServerError, "")
+	client := NewRestClientBuilder().
+		WithBaseURL(server.URL).
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Minute, RetryableStatusCodes: DefaultRetryableStatusCodes}).
+		Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := client.SendRequest(ctx, http.MethodGet, "/", nil, nil); !errors.Is(err, context.DeadlineExceeded) || calls.Load() != 1 {
+		t.Errorf("err = %v after %d requests, want the deadline while backing off", err, calls.Load())
+	}
+}
@@ -0,0 +1,277 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-stock/internal/alert"
+	"go-stock/internal/config"
+	"go-stock/internal/entity"
+	"go-stock/internal/infrastructure/webhook"
+	"go-stock/internal/repository"
+	"log"
+	"strings"
+	"time"
+)
+
+var ErrInvalidAlertRule = errors.New("invalid alert rule")
+
+type AlertUseCase interface {
+	CreateRule(ctx context.Context, rule entity.AlertRule) (*entity.AlertRule, error)
+	UpdateRule(ctx context.Context, rule entity.AlertRule) (*entity.AlertRule, error)
+	DeleteRule(ctx context.Context, id string) (bool, error)
+	FindRule(ctx context.Context, id string) (*entity.AlertRule, error)
+	FindRules(ctx context.Context) ([]entity.AlertRule, error)
+	FindAlerts(ctx context.Context, ruleID string, limit int64) ([]entity.Alert, error)
+	EvaluateAlerts(ctx context.Context, date time.Time) (int, error)
+}
+
+type alertUseCase struct {
+	webhooks               []config.Webhook
+	webhookClient          webhook.WebhookClient
+	alertRuleRepository    repository.AlertRuleRepository
+	alertRepository        repository.AlertRepository
+	stockSummaryRepository repository.StockSummaryRepository
+	stockRepository        repository.StockRepository
+}
+
+func NewAlertUseCase(
+	cfg config.Config,
+	webhookClient webhook.WebhookClient,
+	alertRuleRepository repository.AlertRuleRepository,
+	alertRepository repository.AlertRepository,
+	stockSummaryRepository repository.StockSummaryRepository,
+	stockRepository repository.StockRepository,
+) AlertUseCase {
+	return &alertUseCase{
+		webhooks:               cfg.GetAlert().Webhooks,
+		webhookClient:          webhookClient,
+		alertRuleRepository:    alertRuleRepository,
+		alertRepository:        alertRepository,
+		stockSummaryRepository: stockSummaryRepository,
+		stockRepository:        stockRepository,
+	}
+}
+
+func (u *alertUseCase) CreateRule(ctx context.Context, rule entity.AlertRule) (*entity.AlertRule, error) {
+	if err := u.normalize(ctx, &rule); err != nil {
+		return nil, err
+	}
+
+	rule.ID = newID()
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = rule.CreatedAt
+	if err := u.alertRuleRepository.Upsert(ctx, rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// UpdateRule replaces the condition of a rule and rearms it. It returns nil when
+// the rule does not exist.
+func (u *alertUseCase) UpdateRule(ctx context.Context, rule entity.AlertRule) (*entity.AlertRule, error) {
+	existing, err := u.alertRuleRepository.FindOne(ctx, rule.ID)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+	if err := u.normalize(ctx, &rule); err != nil {
+		return nil, err
+	}
+
+	rule.CreatedAt = existing.CreatedAt
+	rule.UpdatedAt = time.Now()
+	rule.LastEvaluated = existing.LastEvaluated
+	rule.LastTriggered = existing.LastTriggered
+	if err := u.alertRuleRepository.Upsert(ctx, rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// DeleteRule deletes a rule and reports whether it existed. Its alerts are kept.
+func (u *alertUseCase) DeleteRule(ctx context.Context, id string) (bool, error) {
+	rule, err := u.alertRuleRepository.FindOne(ctx, id)
+	if err != nil || rule == nil {
+		return false, err
+	}
+	if err := u.alertRuleRepository.Delete(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (u *alertUseCase) FindRule(ctx context.Context, id string) (*entity.AlertRule, error) {
+	return u.alertRuleRepository.FindOne(ctx, id)
+}
+
+func (u *alertUseCase) FindRules(ctx context.Context) ([]entity.AlertRule, error) {
+	return u.alertRuleRepository.All(ctx)
+}
+
+func (u *alertUseCase) FindAlerts(ctx context.Context, ruleID string, limit int64) ([]entity.Alert, error) {
+	return u.alertRepository.Find(ctx, ruleID, limit)
+}
+
+// EvaluateAlerts checks the enabled rules against the summaries of a trading day
+// and delivers the alerts that fire, returning their number. A rule fires when
+// its condition starts to hold and is evaluated once per day, so evaluating the
+// same or an earlier day again fires nothing. Only the latest stored day is
+// evaluated, so backfilling past days never fires alerts for old prices; a zero
+// date evaluates it too.
+func (u *alertUseCase) EvaluateAlerts(ctx context.Context, date time.Time) (int, error) {
+	latest, err := u.stockSummaryRepository.FindLatestDate(ctx)
+	if err != nil || latest.IsZero() {
+		return 0, err
+	}
+	if date.IsZero() {
+		date = latest
+	}
+	date = truncateDate(date)
+	if date.Before(truncateDate(latest)) {
+		return 0, nil
+	}
+	rules, err := u.alertRuleRepository.All(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	fired := 0
+	for _, rule := range rules {
+		if !rule.Enabled || !date.After(rule.LastEvaluated) {
+			continue
+		}
+
+		summaries, err := u.stockSummaryRepository.Find(ctx, rule.StockCode, date.Format("2006-01-02"), date.Format("2006-01-02"))
+		if err != nil {
+			return fired, err
+		}
+		if len(summaries) == 0 {
+			continue // not traded, the rule keeps its state
+		}
+
+		var history []entity.StockSummary
+		if rule.Type == entity.AlertValueSpike {
+			history, err = u.stockSummaryRepository.FindBefore(ctx, rule.StockCode, date, int64(alert.Period(rule)))
+			if err != nil {
+				return fired, err
+			}
+		}
+		held, observed, err := alert.Evaluate(rule, summaries[0], history)
+		if err != nil {
+			return fired, fmt.Errorf("rule %s: %w", rule.ID, err)
+		}
+
+		if held && !rule.Triggered {
+			stored, err := u.fire(ctx, rule, date, observed)
+			if err != nil {
+				return fired, err
+			}
+			if stored {
+				fired++
+			}
+			rule.LastTriggered = date
+		}
+		rule.Triggered = held
+		rule.LastEvaluated = date
+		// Only the state is written, so a rule updated meanwhile keeps its
+		// condition and a deleted one stays deleted.
+		if _, err := u.alertRuleRepository.UpdateState(ctx, rule); err != nil {
+			return fired, err
+		}
+	}
+	return fired, nil
+}
+
+// fire stores the alert of a rule and delivers it to the webhooks. It reports
+// false when the rule already fired on the date. Failed deliveries are recorded
+// on the alert and do not fail the evaluation.
+func (u *alertUseCase) fire(ctx context.Context, rule entity.AlertRule, date time.Time, observed float64) (bool, error) {
+	firing := entity.Alert{
+		ID:          newID(),
+		RuleID:      rule.ID,
+		RuleName:    rule.Name,
+		StockCode:   rule.StockCode,
+		Type:        rule.Type,
+		Date:        date,
+		Threshold:   rule.Threshold,
+		Observed:    observed,
+		Message:     alert.Message(rule, observed),
+		TriggeredAt: time.Now(),
+	}
+	stored, err := u.alertRepository.Insert(ctx, firing)
+	if err != nil || !stored {
+		return false, err
+	}
+
+	firing.Delivered = len(u.webhooks) > 0
+	for _, hook := range u.webhooks {
+		if err := u.webhookClient.Send(ctx, hook.URL, hook.Secret, toAlertPayload(firing)); err != nil {
+			log.Printf("⚠️ Alert %s not delivered to %s: %v", firing.ID, hook.URL, err)
+			firing.Delivered = false
+			firing.DeliveryError = err.Error()
+		}
+	}
+	if err := u.alertRepository.Update(ctx, firing); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// normalize checks the condition of a rule and that its stock is listed, and
+// names unnamed rules after their condition.
+func (u *alertUseCase) normalize(ctx context.Context, rule *entity.AlertRule) error {
+	switch rule.Type {
+	case entity.AlertPriceAbove, entity.AlertPriceBelow, entity.AlertValueSpike, entity.AlertForeignNetSell:
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidAlertRule, rule.Type)
+	}
+	if rule.Threshold <= 0 {
+		return fmt.Errorf("%w: threshold must be positive", ErrInvalidAlertRule)
+	}
+
+	codes, unknown, err := listedStockCodes(ctx, u.stockRepository, []string{rule.StockCode})
+	if err != nil {
+		return err
+	}
+	if unknown != "" || len(codes) == 0 {
+		return fmt.Errorf("%w: unknown stock %q", ErrInvalidAlertRule, rule.StockCode)
+	}
+	rule.StockCode = codes[0]
+
+	rule.Name = strings.TrimSpace(rule.Name)
+	if rule.Name == "" {
+		rule.Name = fmt.Sprintf("%s %s %g", rule.StockCode, rule.Type, rule.Threshold)
+	}
+	return nil
+}
+
+// alertPayload is the JSON body posted to the webhooks.
+type alertPayload struct {
+	Event       string    `json:"event"`
+	AlertID     string    `json:"alert_id"`
+	RuleID      string    `json:"rule_id"`
+	RuleName    string    `json:"rule_name"`
+	StockCode   string    `json:"stock_code"`
+	Type        string    `json:"type"`
+	Date        string    `json:"date"`
+	Threshold   float64   `json:"threshold"`
+	Observed    float64   `json:"observed"`
+	Message     string    `json:"message"`
+	TriggeredAt time.Time `json:"triggered_at"`
+}
+
+func toAlertPayload(firing entity.Alert) alertPayload {
+	return alertPayload{
+		Event:       "alert",
+		AlertID:     firing.ID,
+		RuleID:      firing.RuleID,
+		RuleName:    firing.RuleName,
+		StockCode:   firing.StockCode,
+		Type:        firing.Type,
+		Date:        firing.Date.Format("2006-01-02"),
+		Threshold:   firing.Threshold,
+		Observed:    firing.Observed,
+		Message:     firing.Message,
+		TriggeredAt: firing.TriggeredAt,
+	}
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"go-stock/internal/config"
+	"go-stock/internal/entity"
+	"testing"
+	"time"
+)
+
+func TestEvaluateAlerts(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.alert.Webhooks = []config.Webhook{{URL: "https://example.com/hook", Secret: "secret"}}
+	stocks := newMemoryStockRepository()
+	stocks.BulkUpsert(context.Background(), []entity.Stock{{StockCode: "BBCA"}})
+	summaries := newMemoryStockSummaryRepository()
+	closes := []float64{9000, 9600, 9700, 9200, 9800}
+	days := make([]time.Time, len(closes))
+	for i, close := range closes {
+		days[i] = time.Date(2025, 1, 6+i, 0, 0, 0, 0, time.UTC)
+		if i == 0 {
+			summaries.BulkUpsert(context.Background(), []entity.StockSummary{{StockCode: "BBCA", Date: days[i], Close: close}})
+		}
+	}
+	webhookClient := &fakeWebhookClient{}
+	alerts := newMemoryAlertRepository()
+	uc := NewAlertUseCase(cfg, webhookClient, newMemoryAlertRuleRepository(), alerts, summaries, stocks)
+
+	if _, err := uc.CreateRule(context.Background(), entity.AlertRule{StockCode: "ZZZZ", Type: entity.AlertPriceAbove, Threshold: 1}); !errors.Is(err, ErrInvalidAlertRule) {
+		t.Errorf("CreateRule with an unknown stock = %v, want ErrInvalidAlertRule", err)
+	}
+	rule, err := uc.CreateRule(context.Background(), entity.AlertRule{StockCode: "bbca", Type: entity.AlertPriceAbove, Threshold: 9500, Enabled: true})
+	if err != nil {
+		t.Fatalf("CreateRule: %v", err)
+	}
+	if rule.StockCode != "BBCA" || rule.Name != "BBCA price_above 9500" {
+		t.Errorf("rule = %+v, want BBCA named after its condition", rule)
+	}
+
+	// The rule fires when the close crosses the threshold, not again while it
+	// stays above nor when a day is evaluated again, and once more after the
+	// close fell back below. Each day is evaluated once it is stored.
+	for _, step := range []struct {
+		day  time.Time
+		want int
+	}{
+		{days[0], 0},
+		{days[1], 1},
+		{days[1], 0},
+		{days[2], 0},
+		{days[3], 0},
+		{days[4], 1},
+	} {
+		summaries.BulkUpsert(context.Background(), []entity.StockSummary{{StockCode: "BBCA", Date: step.day, Close: closes[step.day.Day()-6]}})
+		fired, err := uc.EvaluateAlerts(context.Background(), step.day)
+		if err != nil {
+			t.Fatalf("EvaluateAlerts(%s): %v", step.day.Format("2006-01-02"), err)
+		}
+		if fired != step.want {
+			t.Errorf("EvaluateAlerts(%s) = %d, want %d", step.day.Format("2006-01-02"), fired, step.want)
+		}
+	}
+	if len(webhookClient.sent) != 2 {
+		t.Fatalf("sent %d payloads, want 2", len(webhookClient.sent))
+	}
+	// A rule never evaluated does not fire for the days before the latest one,
+	// as when past days are backfilled.
+	if _, err := uc.CreateRule(context.Background(), entity.AlertRule{StockCode: "BBCA", Type: entity.AlertPriceAbove, Threshold: 9000, Enabled: true}); err != nil {
+		t.Fatalf("CreateRule: %v", err)
+	}
+	if fired, err := uc.EvaluateAlerts(context.Background(), days[2]); err != nil || fired != 0 {
+		t.Errorf("EvaluateAlerts(%s) = %d, %v, want 0 before the latest day", days[2].Format("2006-01-02"), fired, err)
+	}
+	payload := webhookClient.sent[0].(alertPayload)
+	if payload.RuleID != rule.ID || payload.Date != "2025-01-07" || payload.Observed != 9600 {
+		t.Errorf("payload = %+v", payload)
+	}
+
+	// Failed deliveries are recorded on the alert.
+	if _, err := uc.UpdateRule(context.Background(), entity.AlertRule{ID: rule.ID, StockCode: "BBCA", Type: entity.AlertPriceBelow, Threshold: 9000, Enabled: true}); err != nil {
+		t.Fatalf("UpdateRule: %v", err)
+	}
+	summaries.BulkUpsert(context.Background(), []entity.StockSummary{{StockCode: "BBCA", Date: days[4].AddDate(0, 0, 3), Close: 8900}})
+	webhookClient.err = errors.New("connection refused")
+	if fired, err := uc.EvaluateAlerts(context.Background(), time.Time{}); err != nil || fired != 1 {
+		t.Fatalf("EvaluateAlerts(latest) = %d, %v, want 1", fired, err)
+	}
+	found, err := uc.FindAlerts(context.Background(), rule.ID, 10)
+	if err != nil || len(found) != 3 {
+		t.Fatalf("FindAlerts = %+v, %v, want 3 alerts", found, err)
+	}
+	if found[0].Delivered || found[0].DeliveryError != "connection refused" || !found[1].Delivered {
+		t.Errorf("delivery = %v %q, previous %v", found[0].Delivered, found[0].DeliveryError, found[1].Delivered)
+	}
+}
+
+// staleAlertRuleRepository lists the rules as they were before a concurrent
+// change, made right after All returns.
+type staleAlertRuleRepository struct {
+	*memoryAlertRuleRepository
+	change func()
+}
+
+func (r *staleAlertRuleRepository) All(ctx context.Context) ([]entity.AlertRule, error) {
+	rules, err := r.memoryAlertRuleRepository.All(ctx)
+	r.change()
+	return rules, err
+}
+
+func TestEvaluateAlertsKeepsConcurrentChanges(t *testing.T) {
+	cfg := newTestConfig()
+	stocks := newMemoryStockRepository()
+	stocks.BulkUpsert(context.Background(), []entity.Stock{{StockCode: "BBCA"}, {StockCode: "BBRI"}})
+	summaries := newMemoryStockSummaryRepository()
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	summaries.BulkUpsert(context.Background(), []entity.StockSummary{
+		{StockCode: "BBCA", Date: day, Close: 9600},
+		{StockCode: "BBRI", Date: day, Close: 4000},
+	})
+	rules := &staleAlertRuleRepository{memoryAlertRuleRepository: newMemoryAlertRuleRepository()}
+	uc := NewAlertUseCase(cfg, &fakeWebhookClient{}, rules, newMemoryAlertRepository(), summaries, stocks)
+
+	deleted, err := uc.CreateRule(context.Background(), entity.AlertRule{StockCode: "BBCA", Type: entity.AlertPriceAbove, Threshold: 9500, Enabled: true})
+	if err != nil {
+		t.Fatalf("CreateRule: %v", err)
+	}
+	updated, err := uc.CreateRule(context.Background(), entity.AlertRule{StockCode: "BBRI", Type: entity.AlertPriceBelow, Threshold: 4500, Enabled: true})
+	if err != nil {
+		t.Fatalf("CreateRule: %v", err)
+	}
+	rules.change = func() {
+		rules.Delete(context.Background(), deleted.ID)
+		updated.Threshold = 3500
+		rules.Upsert(context.Background(), *updated)
+	}
+
+	if _, err := uc.EvaluateAlerts(context.Background(), day); err != nil {
+		t.Fatalf("EvaluateAlerts: %v", err)
+	}
+	if rule, _ := rules.FindOne(context.Background(), deleted.ID); rule != nil {
+		t.Errorf("deleted rule came back: %+v", rule)
+	}
+	rule, _ := rules.FindOne(context.Background(), updated.ID)
+	if rule == nil || rule.Threshold != 3500 || !rule.LastEvaluated.Equal(day) {
+		t.Errorf("updated rule = %+v, want threshold 3500 evaluated on %s", rule, day.Format("2006-01-02"))
+	}
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-stock/internal/backtest"
+	"go-stock/internal/entity"
+	"go-stock/internal/indicator"
+	"go-stock/internal/repository"
+	"slices"
+	"time"
+)
+
+var ErrInvalidBacktest = errors.New("invalid backtest")
+
+type BacktestUseCase interface {
+	RunBacktest(ctx context.Context, stockCodes []string, startDate, endDate time.Time, strategy backtest.Strategy, account backtest.Account) (*entity.Backtest, error)
+}
+
+type backtestUseCase struct {
+	stockSummaryRepository repository.StockSummaryRepository
+	stockRepository        repository.StockRepository
+}
+
+func NewBacktestUseCase(stockSummaryRepository repository.StockSummaryRepository, stockRepository repository.StockRepository) BacktestUseCase {
+	return &backtestUseCase{
+		stockSummaryRepository: stockSummaryRepository,
+		stockRepository:        stockRepository,
+	}
+}
+
+// RunBacktest simulates the strategy over the stored summaries of the stocks
+// between startDate and endDate, as traded. A zero endDate is the latest stored
+// day and a zero startDate a year before the end. The summaries before startDate
+// the indicators need to warm up are loaded as well.
+func (u *backtestUseCase) RunBacktest(ctx context.Context, stockCodes []string, startDate, endDate time.Time, strategy backtest.Strategy, account backtest.Account) (*entity.Backtest, error) {
+	lookback, err := backtest.Lookback(strategy)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBacktest, err)
+	}
+	codes, unknown, err := listedStockCodes(ctx, u.stockRepository, stockCodes)
+	if err != nil {
+		return nil, err
+	}
+	if unknown != "" {
+		return nil, fmt.Errorf("%w: unknown stock %q", ErrInvalidBacktest, unknown)
+	}
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("%w: no stock given", ErrInvalidBacktest)
+	}
+
+	if endDate.IsZero() {
+		if endDate, err = u.stockSummaryRepository.FindLatestDate(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if startDate.IsZero() {
+		startDate = endDate.AddDate(-1, 0, 0)
+	}
+
+	bars := make(map[string][]indicator.Bar, len(codes))
+	for _, code := range codes {
+		summaries, err := u.stockSummaryRepository.Find(ctx, code, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+		if err != nil {
+			return nil, err
+		}
+		if len(summaries) == 0 {
+			continue
+		}
+		warmUp, err := u.stockSummaryRepository.FindBefore(ctx, code, summaries[0].Date, int64(lookback))
+		if err != nil {
+			return nil, err
+		}
+		slices.Reverse(warmUp)
+
+		for _, summary := range slices.Concat(warmUp, summaries) {
+			bars[code] = append(bars[code], toBar(summary))
+		}
+	}
+
+	result, err := backtest.Run(strategy, account, bars, startDate)
+	if errors.Is(err, backtest.ErrInvalidStrategy) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBacktest, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.Backtest{
+		StockCodes: codes,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Metrics:    result.Metrics,
+		Trades:     result.Trades,
+		Equity:     result.Equity,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"go-stock/internal/backtest"
+	"go-stock/internal/entity"
+	"testing"
+	"time"
+)
+
+func TestRunBacktest(t *testing.T) {
+	stocks := newMemoryStockRepository()
+	stocks.BulkUpsert(context.Background(), []entity.Stock{{StockCode: "BBCA"}})
+	summaries := newMemoryStockSummaryRepository()
+	closes := []float64{1000, 990, 980, 1000, 1050, 1100, 1080, 1050, 1040}
+	for i, close := range closes {
+		previous := close
+		if i > 0 {
+			previous = closes[i-1]
+		}
+		summaries.BulkUpsert(context.Background(), []entity.StockSummary{{
+			StockCode: "BBCA",
+			Date:      time.Date(2025, 1, 6+i, 0, 0, 0, 0, time.UTC),
+			OpenPrice: previous,
+			High:      max(previous, close),
+			Low:       min(previous, close),
+			Close:     close,
+			Volume:    1000,
+		}})
+	}
+	uc := NewBacktestUseCase(summaries, stocks)
+
+	strategy := backtest.Strategy{
+		Entry: []backtest.Condition{{Left: "close", Op: backtest.OpCrossesAbove, Right: "sma:3"}},
+		Exit:  []backtest.Condition{{Left: "close", Op: backtest.OpCrossesBelow, Right: "sma:3"}},
+	}
+	account := backtest.Account{InitialCapital: 10_000_000}
+
+	if _, err := uc.RunBacktest(context.Background(), []string{"ZZZZ"}, time.Time{}, time.Time{}, strategy, account); !errors.Is(err, ErrInvalidBacktest) {
+		t.Errorf("RunBacktest with an unknown stock = %v, want ErrInvalidBacktest", err)
+	}
+	if _, err := uc.RunBacktest(context.Background(), []string{"BBCA"}, time.Time{}, time.Time{}, backtest.Strategy{}, account); !errors.Is(err, ErrInvalidBacktest) {
+		t.Errorf("RunBacktest without entry = %v, want ErrInvalidBacktest", err)
+	}
+
+	// The bars before the start warm up the average, so the crossing of the 9th
+	// is seen on the start date.
+	start := time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC)
+	result, err := uc.RunBacktest(context.Background(), []string{"bbca"}, start, time.Time{}, strategy, account)
+	if err != nil {
+		t.Fatalf("RunBacktest: %v", err)
+	}
+	if !result.EndDate.Equal(time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC)) || len(result.Equity) != 6 {
+		t.Errorf("range %s - %s with %d days", result.StartDate, result.EndDate, len(result.Equity))
+	}
+	if len(result.Trades) != 1 {
+		t.Fatalf("trades = %+v, want 1", result.Trades)
+	}
+	trade := result.Trades[0]
+	if !trade.EntryDate.Equal(time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)) || trade.EntryPrice != 1000 || trade.ExitPrice != 1050 || trade.ExitReason != entity.ExitSignal {
+		t.Errorf("trade = %+v", trade)
+	}
+}
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-stock/internal/composite"
+	"go-stock/internal/entity"
+	"go-stock/internal/repository"
+	"sort"
+	"strings"
+	"time"
+)
+
+var ErrInvalidBasket = errors.New("invalid basket")
+
+type BasketUseCase interface {
+	CreateBasket(ctx context.Context, basket entity.Basket) (*entity.Basket, error)
+	UpdateBasket(ctx context.Context, basket entity.Basket) (*entity.Basket, error)
+	DeleteBasket(ctx context.Context, id string) (bool, error)
+	FindBasket(ctx context.Context, id string) (*entity.Basket, error)
+	FindBaskets(ctx context.Context) ([]entity.Basket, error)
+	FindIndex(ctx context.Context, id string, startDate, endDate time.Time, base float64) (*entity.BasketIndex, error)
+}
+
+type basketUseCase struct {
+	basketRepository       repository.BasketRepository
+	stockSummaryRepository repository.StockSummaryRepository
+	stockRepository        repository.StockRepository
+}
+
+func NewBasketUseCase(basketRepository repository.BasketRepository, stockSummaryRepository repository.StockSummaryRepository, stockRepository repository.StockRepository) BasketUseCase {
+	return &basketUseCase{
+		basketRepository:       basketRepository,
+		stockSummaryRepository: stockSummaryRepository,
+		stockRepository:        stockRepository,
+	}
+}
+
+func (u *basketUseCase) CreateBasket(ctx context.Context, basket entity.Basket) (*entity.Basket, error) {
+	if err := u.normalize(ctx, &basket); err != nil {
+		return nil, err
+	}
+
+	basket.ID = newID()
+	basket.CreatedAt = time.Now()
+	basket.UpdatedAt = basket.CreatedAt
+	if err := u.basketRepository.Upsert(ctx, basket); err != nil {
+		return nil, err
+	}
+	return &basket, nil
+}
+
+// UpdateBasket replaces the name, stocks and weighting of a basket. It returns
+// nil when the basket does not exist.
+func (u *basketUseCase) UpdateBasket(ctx context.Context, basket entity.Basket) (*entity.Basket, error) {
+	existing, err := u.basketRepository.FindOne(ctx, basket.ID)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+	if err := u.normalize(ctx, &basket); err != nil {
+		return nil, err
+	}
+
+	basket.CreatedAt = existing.CreatedAt
+	basket.UpdatedAt = time.Now()
+	if err := u.basketRepository.Upsert(ctx, basket); err != nil {
+		return nil, err
+	}
+	return &basket, nil
+}
+
+// DeleteBasket deletes a basket and reports whether it existed.
+func (u *basketUseCase) DeleteBasket(ctx context.Context, id string) (bool, error) {
+	basket, err := u.basketRepository.FindOne(ctx, id)
+	if err != nil || basket == nil {
+		return false, err
+	}
+	if err := u.basketRepository.Delete(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (u *basketUseCase) FindBasket(ctx context.Context, id string) (*entity.Basket, error) {
+	return u.basketRepository.FindOne(ctx, id)
+}
+
+func (u *basketUseCase) FindBaskets(ctx context.Context) ([]entity.Basket, error) {
+	return u.basketRepository.All(ctx)
+}
+
+// FindIndex computes the index of a basket from its stock summaries, based at
+// base on the first trading day of the range. Without an end date the range ends
+// on the latest stored day, without a start date it covers the year before. It
+// returns nil when the basket does not exist.
+func (u *basketUseCase) FindIndex(ctx context.Context, id string, startDate, endDate time.Time, base float64) (*entity.BasketIndex, error) {
+	basket, err := u.basketRepository.FindOne(ctx, id)
+	if err != nil || basket == nil {
+		return nil, err
+	}
+
+	if endDate.IsZero() {
+		endDate, err = u.stockSummaryRepository.FindLatestDate(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if startDate.IsZero() {
+		startDate = endDate.AddDate(-1, 0, 0)
+	}
+
+	var summaries []entity.StockSummary
+	for _, code := range basket.StockCodes {
+		stockSummaries, err := u.stockSummaryRepository.Find(ctx, code, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, stockSummaries...)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if !summaries[i].Date.Equal(summaries[j].Date) {
+			return summaries[i].Date.Before(summaries[j].Date)
+		}
+		return summaries[i].StockCode < summaries[j].StockCode
+	})
+
+	result, err := composite.Compute(summaries, basket.Weighting, base)
+	if err != nil {
+		return nil, err
+	}
+
+	index := &entity.BasketIndex{
+		Basket:        *basket,
+		StartDate:     startDate,
+		EndDate:       endDate,
+		BaseLevel:     base,
+		Levels:        result.Levels,
+		Contributions: result.Contributions,
+	}
+	if len(result.Levels) > 0 {
+		index.StartDate = result.Levels[0].Date
+		index.EndDate = result.Levels[len(result.Levels)-1].Date
+		index.Return = result.Levels[len(result.Levels)-1].Level/base - 1
+	}
+	return index, nil
+}
+
+// normalize upper-cases and deduplicates the stock codes of a basket and checks
+// that every stock is listed.
+func (u *basketUseCase) normalize(ctx context.Context, basket *entity.Basket) error {
+	basket.Name = strings.TrimSpace(basket.Name)
+	if basket.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidBasket)
+	}
+	switch basket.Weighting {
+	case entity.WeightingEqual, entity.WeightingMarketCap, entity.WeightingFreeFloat:
+	default:
+		return fmt.Errorf("%w: unknown weighting %q", ErrInvalidBasket, basket.Weighting)
+	}
+
+	codes, unknown, err := listedStockCodes(ctx, u.stockRepository, basket.StockCodes)
+	if err != nil {
+		return err
+	}
+	if unknown != "" {
+		return fmt.Errorf("%w: unknown stock %s", ErrInvalidBasket, unknown)
+	}
+	if len(codes) == 0 {
+		return fmt.Errorf("%w: no stock given", ErrInvalidBasket)
+	}
+	basket.StockCodes = codes
+	return nil
+}
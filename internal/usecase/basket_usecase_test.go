@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"go-stock/internal/entity"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBasketIndex(t *testing.T) {
+	stocks := newMemoryStockRepository()
+	stocks.BulkUpsert(context.Background(), []entity.Stock{{StockCode: "AAAA"}, {StockCode: "BBBB"}})
+	summaries := newMemoryStockSummaryRepository()
+	day := func(n int) time.Time { return time.Date(2025, time.January, n, 0, 0, 0, 0, time.UTC) }
+	summaries.BulkUpsert(context.Background(), []entity.StockSummary{
+		{Date: day(2), StockCode: "AAAA", Previous: 100, Close: 100, ListedShares: 10},
+		{Date: day(2), StockCode: "BBBB", Previous: 100, Close: 100, ListedShares: 30},
+		{Date: day(3), StockCode: "AAAA", Previous: 100, Close: 110, ListedShares: 10},
+		{Date: day(3), StockCode: "BBBB", Previous: 100, Close: 90, ListedShares: 30},
+	})
+	uc := NewBasketUseCase(newMemoryBasketRepository(), summaries, stocks)
+
+	if _, err := uc.CreateBasket(context.Background(), entity.Basket{Name: "Unknown", StockCodes: []string{"ZZZZ"}, Weighting: entity.WeightingEqual}); !errors.Is(err, ErrInvalidBasket) {
+		t.Errorf("CreateBasket with an unknown stock = %v, want ErrInvalidBasket", err)
+	}
+	basket, err := uc.CreateBasket(context.Background(), entity.Basket{Name: " Pair ", StockCodes: []string{"aaaa", "BBBB", "AAAA"}, Weighting: entity.WeightingMarketCap})
+	if err != nil {
+		t.Fatalf("CreateBasket: %v", err)
+	}
+	if basket.ID == "" || basket.Name != "Pair" || len(basket.StockCodes) != 2 {
+		t.Errorf("basket = %+v, want the trimmed name and 2 unique codes", basket)
+	}
+
+	index, err := uc.FindIndex(context.Background(), basket.ID, time.Time{}, time.Time{}, 1000)
+	if err != nil {
+		t.Fatalf("FindIndex: %v", err)
+	}
+	// AAAA weighs a quarter of the market cap: 0.25*10% + 0.75*-10% = -5%.
+	if len(index.Levels) != 2 || math.Abs(index.Levels[1].Level-950) > 1e-9 || math.Abs(index.Return+0.05) > 1e-9 {
+		t.Fatalf("index = %+v, want 1000 then 950", index)
+	}
+	if !index.StartDate.Equal(day(2)) || len(index.Contributions) != 2 || index.Contributions[1].StockCode != "BBBB" {
+		t.Errorf("index = %+v", index)
+	}
+
+	basket.Weighting = entity.WeightingEqual
+	if _, err := uc.UpdateBasket(context.Background(), *basket); err != nil {
+		t.Fatalf("UpdateBasket: %v", err)
+	}
+	if index, _ = uc.FindIndex(context.Background(), basket.ID, time.Time{}, time.Time{}, 100); index.Levels[1].Level != 100 {
+		t.Errorf("equal weighted level = %v, want 100", index.Levels[1].Level)
+	}
+
+	if deleted, err := uc.DeleteBasket(context.Background(), basket.ID); err != nil || !deleted {
+		t.Fatalf("DeleteBasket = %v, %v", deleted, err)
+	}
+	if index, err := uc.FindIndex(context.Background(), basket.ID, time.Time{}, time.Time{}, 100); err != nil || index != nil {
+		t.Errorf("FindIndex of a deleted basket = %+v, %v", index, err)
+	}
+}
@@ -2,28 +2,109 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"go-stock/internal/config"
 	"go-stock/internal/entity"
 	"go-stock/internal/infrastructure/indopremier"
+	"go-stock/internal/repository"
+	"go-stock/internal/shared/rest"
+	"log"
+	"time"
+)
+
+const (
+	// brokerSummaryFinalAfter is the time after the start of the end date from
+	// which a fetched summary is final; trading and its corrections are over by then.
+	brokerSummaryFinalAfter = 17 * time.Hour
+
+	// The daily snapshot stores the summary of all investors on all boards.
+	snapshotInvestorType = "ALL"
+	snapshotBoard        = "ALL"
 )
 
 type BrokerSummaryUseCase interface {
 	Find(ctx context.Context, stockCode, startDate, endDate, investorType, board string) (*entity.BrokerSummary, error)
+	SnapshotBrokerSummaries(ctx context.Context, date time.Time) (int, error)
 }
 
 type brokerSummaryUseCase struct {
-	indopremierClient indopremier.IndopremierClient
+	brokerSummaryRepository repository.BrokerSummaryRepository
+	stockRepository         repository.StockRepository
+	indopremierClient       indopremier.IndopremierClient
+	cacheTTL                time.Duration
+	location                *time.Location
 }
 
-func NewBrokerSummaryUseCase(indopremierClient indopremier.IndopremierClient) BrokerSummaryUseCase {
+func NewBrokerSummaryUseCase(cfg config.Config, indopremierClient indopremier.IndopremierClient, brokerSummaryRepository repository.BrokerSummaryRepository, stockRepository repository.StockRepository) BrokerSummaryUseCase {
+	location, err := time.LoadLocation(cfg.GetApplication().Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
 	return &brokerSummaryUseCase{
-		indopremierClient: indopremierClient,
+		brokerSummaryRepository: brokerSummaryRepository,
+		stockRepository:         stockRepository,
+		indopremierClient:       indopremierClient,
+		cacheTTL:                time.Duration(cfg.GetService().IndoPremierService.BrokerSummaryCacheTTL) * time.Millisecond,
+		location:                location,
 	}
 }
 
+// Find returns the broker summary of a stock, dates given as MM/DD/YYYY. Stored
+// summaries are served while they are fresh; a summary fetched after its last day
+// closed never changes and is always served. It returns nil when no broker traded
+// the stock in the date range, which is stored as an empty summary under the same
+// rules.
 func (b *brokerSummaryUseCase) Find(ctx context.Context, stockCode, startDate, endDate, investorType, board string) (*entity.BrokerSummary, error) {
+	summary, _, err := b.find(ctx, stockCode, startDate, endDate, investorType, board)
+	return summary, err
+}
+
+// find is the read-through lookup of Find, reporting whether the summary was
+// fetched from Indopremier.
+func (b *brokerSummaryUseCase) find(ctx context.Context, stockCode, startDate, endDate, investorType, board string) (*entity.BrokerSummary, bool, error) {
+	start, err := time.Parse("01/02/2006", startDate)
+	if err != nil {
+		return nil, false, fmt.Errorf("error parsing start date: %w", err)
+	}
+	end, err := time.Parse("01/02/2006", endDate)
+	if err != nil {
+		return nil, false, fmt.Errorf("error parsing end date: %w", err)
+	}
+
+	cached, err := b.brokerSummaryRepository.FindOne(ctx, stockCode, start, end, investorType, board)
+	if err != nil {
+		return nil, false, err
+	}
+	if cached != nil {
+		fillAmounts(cached)
+	}
+	if cached != nil && (b.final(*cached) || time.Since(cached.FetchedAt) < b.cacheTTL) {
+		return nonEmpty(cached), false, nil
+	}
+
 	result, err := b.indopremierClient.GetBrokerSummary(ctx, stockCode, startDate, endDate, investorType, board)
+	if errors.Is(err, indopremier.ErrEmptyBrokerSummary) {
+		empty := entity.BrokerSummary{
+			StockCode:    stockCode,
+			StartDate:    start,
+			EndDate:      end,
+			InvestorType: investorType,
+			Board:        board,
+			FetchedAt:    time.Now(),
+		}
+		if err := b.brokerSummaryRepository.Upsert(ctx, empty); err != nil {
+			return nil, false, err
+		}
+		return nil, true, nil
+	}
+	if errors.Is(err, rest.ErrUpstreamUnavailable) && cached != nil {
+		log.Printf("Serving stale broker summary of %s: %v", stockCode, err)
+		return nonEmpty(cached), false, nil
+	}
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	buyers := make([]entity.BrokerSummaryData, 0, len(result.Buyers))
@@ -32,6 +113,7 @@ func (b *brokerSummaryUseCase) Find(ctx context.Context, stockCode, startDate, e
 			BrokerCode: buyer.BrokerCode,
 			Lot:        buyer.Lot,
 			Val:        buyer.Val,
+			ValAmount:  buyer.ValAmount,
 			Avg:        buyer.Avg,
 		})
 	}
@@ -42,21 +124,99 @@ func (b *brokerSummaryUseCase) Find(ctx context.Context, stockCode, startDate, e
 			BrokerCode: seller.BrokerCode,
 			Lot:        seller.Lot,
 			Val:        seller.Val,
+			ValAmount:  seller.ValAmount,
 			Avg:        seller.Avg,
 		})
 	}
-	return &entity.BrokerSummary{
-		StockCode: result.StockCode,
-		StartDate: result.StartDate,
-		EndDate:   result.EndDate,
-		Buyers:    buyers,
-		Sellers:   sellers,
+
+	summary := &entity.BrokerSummary{
+		StockCode:    result.StockCode,
+		StartDate:    result.StartDate,
+		EndDate:      result.EndDate,
+		InvestorType: investorType,
+		Board:        board,
+		Buyers:       buyers,
+		Sellers:      sellers,
 		Summary: entity.Summary{
-			TotalVal:      result.Summary.TotalVal,
-			ForeignNetVal: result.Summary.ForeignNetVal,
-			TotalLot:      result.Summary.TotalLot,
-			Avg:           result.Summary.Avg,
+			TotalVal:            result.Summary.TotalVal,
+			TotalValAmount:      result.Summary.TotalValAmount,
+			ForeignNetVal:       result.Summary.ForeignNetVal,
+			ForeignNetValAmount: result.Summary.ForeignNetValAmount,
+			TotalLot:            result.Summary.TotalLot,
+			Avg:                 result.Summary.Avg,
 		},
-	}, nil
+		FetchedAt: time.Now(),
+	}
+
+	if err := b.brokerSummaryRepository.Upsert(ctx, *summary); err != nil {
+		return nil, false, err
+	}
+
+	return summary, true, nil
+}
+
+// nonEmpty returns the summary, or nil when no broker traded.
+func nonEmpty(summary *entity.BrokerSummary) *entity.BrokerSummary {
+	if summary.Empty() {
+		return nil
+	}
+	return summary
+}
+
+// fillAmounts parses the rupiah amounts of a summary stored before they were kept.
+func fillAmounts(summary *entity.BrokerSummary) {
+	for _, data := range [][]entity.BrokerSummaryData{summary.Buyers, summary.Sellers} {
+		for i := range data {
+			if data[i].ValAmount == 0 {
+				data[i].ValAmount = indopremier.ParseAmount(data[i].Val)
+			}
+		}
+	}
+	if summary.Summary.TotalValAmount == 0 {
+		summary.Summary.TotalValAmount = indopremier.ParseAmount(summary.Summary.TotalVal)
+	}
+	if summary.Summary.ForeignNetValAmount == 0 {
+		summary.Summary.ForeignNetValAmount = indopremier.ParseAmount(summary.Summary.ForeignNetVal)
+	}
+}
+
+// final reports whether the summary was fetched after the market closed on its
+// end date, so it can be cached forever.
+func (b *brokerSummaryUseCase) final(summary entity.BrokerSummary) bool {
+	end := time.Date(summary.EndDate.Year(), summary.EndDate.Month(), summary.EndDate.Day(), 0, 0, 0, 0, b.location)
+	return !summary.FetchedAt.Before(end.Add(brokerSummaryFinalAfter))
+}
+
+// SnapshotBrokerSummaries stores the broker summary of every stock for date.
+// Stocks with a final summary already stored are skipped, so a failed snapshot can
+// be run again. It stops when Indopremier becomes unavailable.
+func (b *brokerSummaryUseCase) SnapshotBrokerSummaries(ctx context.Context, date time.Time) (int, error) {
+	stocks, err := b.stockRepository.All(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	day := date.Format("01/02/2006")
+
+	var (
+		stored   int
+		failures []error
+	)
+	for _, stock := range stocks {
+		_, fetched, err := b.find(ctx, stock.StockCode, day, day, snapshotInvestorType, snapshotBoard)
+		switch {
+		case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded), errors.Is(err, rest.ErrUpstreamUnavailable):
+			return stored, err
+		case err != nil:
+			failures = append(failures, fmt.Errorf("broker summary %s: %w", stock.StockCode, err))
+		case fetched:
+			stored++
+		}
+	}
+
+	if len(failures) > 0 {
+		return stored, fmt.Errorf("%d of %d broker summaries failed: %w", len(failures), len(stocks), errors.Join(failures...))
+	}
 
+	return stored, nil
 }
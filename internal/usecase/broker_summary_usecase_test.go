@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"go-stock/internal/entity"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestBrokerSummaryUseCase(cacheTTL time.Duration) (BrokerSummaryUseCase, *countingIndopremierClient, *memoryBrokerSummaryRepository, *memoryStockRepository) {
+	cfg := newTestConfig()
+	cfg.application.Timezone = "Asia/Jakarta"
+	cfg.service.IndoPremierService.BrokerSummaryCacheTTL = int(cacheTTL.Milliseconds())
+
+	client := &countingIndopremierClient{IndopremierClient: newTestIndopremierClient(cfg)}
+	summaries := newMemoryBrokerSummaryRepository()
+	stocks := newMemoryStockRepository()
+	return NewBrokerSummaryUseCase(cfg, client, summaries, stocks), client, summaries, stocks
+}
+
+func TestFindBrokerSummary(t *testing.T) {
+	uc, client, summaries, _ := newTestBrokerSummaryUseCase(time.Minute)
+
+	result, err := uc.Find(context.Background(), "BBCA", "01/02/2025", "01/02/2025", "ALL", "ALL")
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+
+	if result.StockCode != "BBCA" || len(result.Buyers) != 4 || len(result.Sellers) != 4 {
+		t.Fatalf("got %s with %d buyers and %d sellers, want BBCA with 4 each", result.StockCode, len(result.Buyers), len(result.Sellers))
+	}
+	seller := entity.BrokerSummaryData{BrokerCode: "AK", Lot: 39870, Val: "39.0 B", ValAmount: 39_000_000_000, Avg: 9781.2}
+	if result.Sellers[0] != seller {
+		t.Errorf("top seller = %+v, want %+v", result.Sellers[0], seller)
+	}
+	if result.Summary.TotalValAmount != 177_800_000_000 || result.Summary.ForeignNetValAmount != -3_800_000_000 || result.Summary.TotalLot != 182345 {
+		t.Errorf("summary = %+v", result.Summary)
+	}
+
+	if client.calls != 1 || len(summaries.summaries) != 1 {
+		t.Fatalf("got %d requests and %d stored summaries, want the summary fetched once and stored", client.calls, len(summaries.summaries))
+	}
+	for _, stored := range summaries.summaries {
+		if stored.InvestorType != "ALL" || stored.Board != "ALL" || stored.FetchedAt.IsZero() {
+			t.Errorf("stored summary = %+v, want the investor type, board and fetch time", stored)
+		}
+	}
+}
+
+func TestFindBrokerSummaryCache(t *testing.T) {
+	tests := []struct {
+		name      string
+		fetchedAt time.Time
+		wantCalls int
+	}{
+		{"closed day is never refetched", time.Date(2025, time.January, 2, 19, 0, 0, 0, jakarta(t)), 0},
+		{"intraday summary is refetched when stale", time.Date(2025, time.January, 2, 10, 0, 0, 0, jakarta(t)), 1},
+		{"intraday summary is served while fresh", time.Now(), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, client, summaries, _ := newTestBrokerSummaryUseCase(time.Minute)
+			day := time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC)
+			summaries.Upsert(context.Background(), entity.BrokerSummary{
+				StockCode:    "BBCA",
+				StartDate:    day,
+				EndDate:      day,
+				InvestorType: "ALL",
+				Board:        "ALL",
+				Buyers:       []entity.BrokerSummaryData{{BrokerCode: "BK"}},
+				Summary:      entity.Summary{TotalVal: "cached"},
+				FetchedAt:    tt.fetchedAt,
+			})
+
+			result, err := uc.Find(context.Background(), "BBCA", "01/02/2025", "01/02/2025", "ALL", "ALL")
+			if err != nil {
+				t.Fatalf("Find: %v", err)
+			}
+			if client.calls != tt.wantCalls {
+				t.Errorf("got %d requests, want %d", client.calls, tt.wantCalls)
+			}
+			if cached := result.Summary.TotalVal == "cached"; cached != (tt.wantCalls == 0) {
+				t.Errorf("total val = %s, cached summary served = %v", result.Summary.TotalVal, cached)
+			}
+		})
+	}
+}
+
+func TestFindBrokerSummaryParsesStoredAmounts(t *testing.T) {
+	uc, _, summaries, _ := newTestBrokerSummaryUseCase(time.Minute)
+	day := time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC)
+	// Stored before the rupiah amounts were kept.
+	summaries.Upsert(context.Background(), entity.BrokerSummary{
+		StockCode:    "BBCA",
+		StartDate:    day,
+		EndDate:      day,
+		InvestorType: "ALL",
+		Board:        "ALL",
+		Buyers:       []entity.BrokerSummaryData{{BrokerCode: "BK", Val: "47.1 B"}},
+		Sellers:      []entity.BrokerSummaryData{{BrokerCode: "AK", Val: "39.0 B"}},
+		Summary:      entity.Summary{TotalVal: "177.8 B", ForeignNetVal: "-3.8 B"},
+		FetchedAt:    time.Date(2025, time.January, 3, 0, 0, 0, 0, time.UTC),
+	})
+
+	result, err := uc.Find(context.Background(), "BBCA", "01/02/2025", "01/02/2025", "ALL", "ALL")
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if result.Buyers[0].ValAmount != 47_100_000_000 || result.Sellers[0].ValAmount != 39_000_000_000 {
+		t.Errorf("broker amounts = %v, %v", result.Buyers[0].ValAmount, result.Sellers[0].ValAmount)
+	}
+	if result.Summary.TotalValAmount != 177_800_000_000 || result.Summary.ForeignNetValAmount != -3_800_000_000 {
+		t.Errorf("summary amounts = %+v", result.Summary)
+	}
+}
+
+func TestFindBrokerSummaryEmpty(t *testing.T) {
+	uc, client, summaries, _ := newTestBrokerSummaryUseCase(time.Minute)
+
+	result, err := uc.Find(context.Background(), "BBCA", "01/04/2025", "01/04/2025", "ALL", "ALL")
+	if err != nil || result != nil {
+		t.Fatalf("got %+v, %v; want no summary for a day without trades", result, err)
+	}
+	if len(summaries.summaries) != 1 {
+		t.Fatalf("stored %d summaries, want the empty day stored", len(summaries.summaries))
+	}
+
+	// The empty day is served from the repository while fresh, and forever once
+	// it was fetched after the day closed.
+	for key, summary := range summaries.summaries {
+		summary.FetchedAt = time.Date(2025, time.January, 4, 19, 0, 0, 0, jakarta(t))
+		summaries.summaries[key] = summary
+	}
+	result, err = uc.Find(context.Background(), "BBCA", "01/04/2025", "01/04/2025", "ALL", "ALL")
+	if err != nil || result != nil || client.calls != 1 {
+		t.Errorf("got %+v, %v after %d requests; want the stored empty day", result, err, client.calls)
+	}
+}
+
+func TestSnapshotBrokerSummaries(t *testing.T) {
+	uc, client, summaries, stocks := newTestBrokerSummaryUseCase(time.Minute)
+	stocks.BulkUpsert(context.Background(), []entity.Stock{{StockCode: "BBCA"}, {StockCode: "BBRI"}})
+
+	// BBRI has no recorded response, its failure is reported after BBCA is stored.
+	stored, err := uc.SnapshotBrokerSummaries(context.Background(), time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC))
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 broker summaries failed") || !strings.Contains(err.Error(), "BBRI") {
+		t.Fatalf("err = %v, want the BBRI failure reported", err)
+	}
+	if stored != 1 || len(summaries.summaries) != 1 {
+		t.Fatalf("stored = %d (%d in the repository), want BBCA stored", stored, len(summaries.summaries))
+	}
+
+	// Rerunning the snapshot after the day closed does not fetch BBCA again.
+	for key, summary := range summaries.summaries {
+		summary.FetchedAt = time.Date(2025, time.January, 2, 19, 0, 0, 0, jakarta(t))
+		summaries.summaries[key] = summary
+	}
+	stocks.stocks = map[string]entity.Stock{"BBCA": {StockCode: "BBCA"}}
+	client.calls = 0
+	stored, err = uc.SnapshotBrokerSummaries(context.Background(), time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil || stored != 0 || client.calls != 0 {
+		t.Fatalf("got %d stored, %d requests, %v; want the final summary reused", stored, client.calls, err)
+	}
+}
+
+func jakarta(t *testing.T) *time.Location {
+	t.Helper()
+	location, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	return location
+}
@@ -9,7 +9,7 @@ import (
 )
 
 type BrokerUseCase interface {
-	UpdateBroker(ctx context.Context) error
+	UpdateBroker(ctx context.Context) (int, error)
 	Find(ctx context.Context, code string) ([]entity.Broker, error)
 }
 
@@ -25,10 +25,10 @@ func NewBrokerUseCase(idxClient idx.IdxClient, brokerRepository repository.Broke
 	}
 }
 
-func (b *brokerUseCase) UpdateBroker(ctx context.Context) error {
+func (b *brokerUseCase) UpdateBroker(ctx context.Context) (int, error) {
 	list, err := b.idxClient.GetBrokerList(ctx)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	var brokers []entity.Broker
@@ -41,14 +41,14 @@ func (b *brokerUseCase) UpdateBroker(ctx context.Context) error {
 	}
 
 	if len(brokers) == 0 {
-		return nil // no broker data to update
+		return 0, nil // no broker data to update
 	}
 
 	if err := b.brokerRepository.BulkUpsert(ctx, brokers); err != nil {
-		return fmt.Errorf("bulk upsert failed: %w", err)
+		return 0, fmt.Errorf("bulk upsert failed: %w", err)
 	}
 
-	return nil
+	return len(brokers), nil
 }
 
 func (b *brokerUseCase) Find(ctx context.Context, code string) ([]entity.Broker, error) {
@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUpdateBroker(t *testing.T) {
+	cfg := newTestConfig()
+	brokers := newMemoryBrokerRepository()
+	uc := NewBrokerUseCase(newTestIdxClient(cfg), brokers)
+
+	records, err := uc.UpdateBroker(context.Background())
+	if err != nil {
+		t.Fatalf("UpdateBroker: %v", err)
+	}
+	if records != 4 {
+		t.Errorf("records = %d, want 4", records)
+	}
+
+	result, err := uc.Find(context.Background(), "YP")
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if len(result) != 1 || result[0].Name != "Mirae Asset Sekuritas Indonesia" {
+		t.Errorf("YP = %+v", result)
+	}
+}
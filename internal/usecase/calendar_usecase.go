@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"go-stock/internal/calendar"
+	"go-stock/internal/config"
+	"go-stock/internal/entity"
+	"go-stock/internal/repository"
+	"log"
+	"time"
+)
+
+// CalendarUseCase answers which days IDX trades on. Zero dates mean today in the
+// timezone of the application.
+type CalendarUseCase interface {
+	FindDay(ctx context.Context, date time.Time) (*entity.TradingDay, error)
+	FindDays(ctx context.Context, startDate, endDate time.Time) ([]entity.TradingDay, error)
+	NextTradingDay(ctx context.Context, date time.Time) (*entity.TradingDay, error)
+	PreviousTradingDay(ctx context.Context, date time.Time) (*entity.TradingDay, error)
+}
+
+type calendarUseCase struct {
+	holidays               []entity.Holiday
+	stockSummaryRepository repository.StockSummaryRepository
+	location               *time.Location
+}
+
+// NewCalendarUseCase returns the calendar of the holidays, usually loaded from the
+// configured holiday file, and of the dates stored in stock summaries.
+func NewCalendarUseCase(cfg config.Config, holidays []entity.Holiday, stockSummaryRepository repository.StockSummaryRepository) CalendarUseCase {
+	location, err := time.LoadLocation(cfg.GetApplication().Timezone)
+	if err != nil {
+		log.Printf("⚠️ Invalid timezone %q: defaulting to UTC", cfg.GetApplication().Timezone)
+		location = time.UTC
+	}
+
+	return &calendarUseCase{
+		holidays:               holidays,
+		stockSummaryRepository: stockSummaryRepository,
+		location:               location,
+	}
+}
+
+// calendar builds the calendar from every stored session, so weekdays missing
+// amid them are inferred as closed.
+func (u *calendarUseCase) calendar(ctx context.Context) (*calendar.Calendar, error) {
+	sessions, err := u.stockSummaryRepository.FindDates(ctx, time.Time{}, u.today().AddDate(1, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+	return calendar.New(u.holidays, sessions), nil
+}
+
+func (u *calendarUseCase) today() time.Time {
+	now := time.Now().In(u.location)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (u *calendarUseCase) orToday(date time.Time) time.Time {
+	if date.IsZero() {
+		return u.today()
+	}
+	return date
+}
+
+func (u *calendarUseCase) FindDay(ctx context.Context, date time.Time) (*entity.TradingDay, error) {
+	c, err := u.calendar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	day := c.Day(u.orToday(date))
+	return &day, nil
+}
+
+// FindDays classifies every day between startDate and endDate, inclusive; the end
+// defaults to 30 days after the start.
+func (u *calendarUseCase) FindDays(ctx context.Context, startDate, endDate time.Time) ([]entity.TradingDay, error) {
+	c, err := u.calendar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	startDate = u.orToday(startDate)
+	if endDate.IsZero() {
+		endDate = startDate.AddDate(0, 0, 30)
+	}
+	return c.Days(startDate, endDate), nil
+}
+
+// NextTradingDay returns the first trading day after date.
+func (u *calendarUseCase) NextTradingDay(ctx context.Context, date time.Time) (*entity.TradingDay, error) {
+	c, err := u.calendar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	day := c.Next(u.orToday(date))
+	return &day, nil
+}
+
+// PreviousTradingDay returns the last trading day before date.
+func (u *calendarUseCase) PreviousTradingDay(ctx context.Context, date time.Time) (*entity.TradingDay, error) {
+	c, err := u.calendar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	day := c.Previous(u.orToday(date))
+	return &day, nil
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"go-stock/internal/entity"
+	"testing"
+	"time"
+)
+
+func TestCalendarInfersClosedDays(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, time.March, d, 0, 0, 0, 0, time.UTC) }
+	summaries := newMemoryStockSummaryRepository()
+	summaries.BulkUpsert(context.Background(), []entity.StockSummary{
+		{StockCode: "BBCA", Date: day(26)},
+		{StockCode: "BBRI", Date: day(26)},
+		{StockCode: "BBCA", Date: day(27)},
+		// Friday the 28th and Tuesday the 1st were not stored, Monday the 31st
+		// is in the holiday file.
+		{StockCode: "BBCA", Date: time.Date(2025, time.April, 2, 0, 0, 0, 0, time.UTC)},
+	})
+	holidays := []entity.Holiday{{Date: day(31), Name: "Hari Raya Idul Fitri 1446 H"}}
+	uc := NewCalendarUseCase(newTestConfig(), holidays, summaries)
+
+	days, err := uc.FindDays(context.Background(), day(27), day(31))
+	if err != nil {
+		t.Fatalf("FindDays: %v", err)
+	}
+	want := []entity.TradingDayStatus{
+		entity.TradingDayStatusSession,
+		entity.TradingDayStatusClosed,
+		entity.TradingDayStatusWeekend,
+		entity.TradingDayStatusWeekend,
+		entity.TradingDayStatusHoliday,
+	}
+	if len(days) != len(want) {
+		t.Fatalf("got %d days, want %d", len(days), len(want))
+	}
+	for i, d := range days {
+		if d.Status != want[i] {
+			t.Errorf("%s = %s, want %s", d.Date.Format("2006-01-02"), d.Status, want[i])
+		}
+	}
+
+	april2 := time.Date(2025, time.April, 2, 0, 0, 0, 0, time.UTC)
+	previous, err := uc.PreviousTradingDay(context.Background(), april2)
+	if err != nil {
+		t.Fatalf("PreviousTradingDay: %v", err)
+	}
+	if !previous.Date.Equal(day(27)) {
+		t.Errorf("previous trading day = %s, want 2025-03-27", previous.Date.Format("2006-01-02"))
+	}
+
+	next, err := uc.NextTradingDay(context.Background(), day(27))
+	if err != nil {
+		t.Fatalf("NextTradingDay: %v", err)
+	}
+	if !next.Date.Equal(april2) || next.Status != entity.TradingDayStatusSession {
+		t.Errorf("next trading day = %s %s, want 2025-04-02 session", next.Date.Format("2006-01-02"), next.Status)
+	}
+}
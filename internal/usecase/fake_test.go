@@ -0,0 +1,787 @@
+package usecase
+
+import (
+	"context"
+	"go-stock/internal/config"
+	"go-stock/internal/entity"
+	"go-stock/internal/infrastructure/idx"
+	"go-stock/internal/infrastructure/indopremier"
+	"go-stock/internal/shared/rest/recorder"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The usecase tests run against the recorded upstream responses of the clients
+// and keep their data in the in-memory repositories below.
+
+const (
+	idxFixtures         = "../infrastructure/idx/testdata/fixtures"
+	indopremierFixtures = "../infrastructure/indopremier/testdata/fixtures"
+)
+
+func newTestConfig() *testConfig {
+	cfg := &testConfig{}
+	cfg.service.IDXService.BaseURL = "https://www.idx.co.id"
+	cfg.service.IDXService.ProfileWorkers = 2
+	cfg.service.IDXService.ProfileBatchSize = 2
+	cfg.service.IDXService.Path.StockList = "/primary/StockData/GetSecuritiesStock?start=0&length=9999&code=&sector=&board=&language=id-id"
+	cfg.service.IDXService.Path.StockSummaryList = "/primary/TradingSummary/GetStockSummary?length=9999&start=0&date={DATE}"
+	cfg.service.IDXService.Path.BrokerList = "/primary/ExchangeMember/GetBrokerSearch?option=0&license=&start=0&length=9999&language=id-id"
+	cfg.service.IDXService.Path.CompanyProfile = "/primary/ListedCompany/GetCompanyProfilesDetail?KodeEmiten={CODE}"
+	cfg.service.IDXService.Path.FinancialReport = "/primary/ListedCompany/GetFinancialReport?periode={PERIOD}&year={YEAR}&indexFrom=0&pageSize=1000&reportType=rdf"
+	cfg.service.IndoPremierService.BaseURL = "https://www.indopremier.com"
+	cfg.service.IndoPremierService.Path.BrokerSummary = "/module/saham/include/data-brokersummary.php?code={CODE}&start={START_DATE}&end={END_DATE}&fd={INVESTOR_TYPE}&board={BOARD}"
+	return cfg
+}
+
+type testConfig struct {
+	application config.Application
+	mongo       config.Mongo
+	service     config.Service
+	cronJob     config.CronJob
+	alert       config.Alert
+	calendar    config.Calendar
+}
+
+func (c *testConfig) GetApplication() config.Application { return c.application }
+func (c *testConfig) GetMongo() config.Mongo             { return c.mongo }
+func (c *testConfig) GetService() config.Service         { return c.service }
+func (c *testConfig) GetCronJob() config.CronJob         { return c.cronJob }
+func (c *testConfig) GetAlert() config.Alert             { return c.alert }
+func (c *testConfig) GetCalendar() config.Calendar       { return c.calendar }
+
+func newTestIdxClient(cfg config.Config) idx.IdxClient {
+	service := cfg.GetService().IDXService
+	return idx.NewIdxClient(idx.Config{
+		BaseURL: service.BaseURL,
+		Path: idx.Path{
+			StockList:        service.Path.StockList,
+			StockSummaryList: service.Path.StockSummaryList,
+			BrokerList:       service.Path.BrokerList,
+			CompanyProfile:   service.Path.CompanyProfile,
+			FinancialReport:  service.Path.FinancialReport,
+		},
+	}, &http.Client{Transport: recorder.NewTransport(recorder.ModeFromEnv(), idxFixtures, nil)})
+}
+
+func newTestIndopremierClient(cfg config.Config) indopremier.IndopremierClient {
+	service := cfg.GetService().IndoPremierService
+	return indopremier.NewIndopremierClient(indopremier.Config{
+		BaseURL: service.BaseURL,
+		Path: indopremier.Path{
+			BrokerSummary: service.Path.BrokerSummary,
+		},
+	}, &http.Client{Transport: recorder.NewTransport(recorder.ModeFromEnv(), indopremierFixtures, nil)})
+}
+
+type memoryStockRepository struct {
+	mu     sync.Mutex
+	stocks map[string]entity.Stock
+}
+
+func newMemoryStockRepository() *memoryStockRepository {
+	return &memoryStockRepository{stocks: make(map[string]entity.Stock)}
+}
+
+func (r *memoryStockRepository) BulkUpsert(ctx context.Context, stocks []entity.Stock) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stock := range stocks {
+		r.stocks[stock.StockCode] = stock
+	}
+	return nil
+}
+
+func (r *memoryStockRepository) All(ctx context.Context) ([]entity.Stock, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stocks := make([]entity.Stock, 0, len(r.stocks))
+	for _, stock := range r.stocks {
+		stocks = append(stocks, stock)
+	}
+	sort.Slice(stocks, func(i, j int) bool {
+		return stocks[i].StockCode < stocks[j].StockCode
+	})
+	return stocks, nil
+}
+
+func (r *memoryStockRepository) FindOne(ctx context.Context, code string) (*entity.Stock, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stock, ok := r.stocks[code]
+	if !ok {
+		return nil, nil
+	}
+	return &stock, nil
+}
+
+func (r *memoryStockRepository) FindWithPagination(ctx context.Context, limit, offset int64) ([]entity.Stock, int64, error) {
+	stocks, _ := r.All(ctx)
+	total := int64(len(stocks))
+	start := min(offset, total)
+	end := min(start+limit, total)
+	return stocks[start:end], total, nil
+}
+
+func (r *memoryStockRepository) Search(ctx context.Context, query string) ([]entity.Stock, error) {
+	stocks, _ := r.All(ctx)
+	var result []entity.Stock
+	for _, stock := range stocks {
+		if strings.Contains(strings.ToLower(stock.StockCode+" "+stock.StockName), strings.ToLower(query)) {
+			result = append(result, stock)
+		}
+	}
+	return result, nil
+}
+
+type memoryCheckpointRepository struct {
+	mu          sync.Mutex
+	checkpoints map[string]entity.Checkpoint
+}
+
+func newMemoryCheckpointRepository() *memoryCheckpointRepository {
+	return &memoryCheckpointRepository{checkpoints: make(map[string]entity.Checkpoint)}
+}
+
+func (r *memoryCheckpointRepository) Find(ctx context.Context, name string) (*entity.Checkpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	checkpoint, ok := r.checkpoints[name]
+	if !ok {
+		return nil, nil
+	}
+	checkpoint.Done = slices.Clone(checkpoint.Done)
+	return &checkpoint, nil
+}
+
+func (r *memoryCheckpointRepository) AddDone(ctx context.Context, name string, items []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	checkpoint, ok := r.checkpoints[name]
+	if !ok {
+		checkpoint = entity.Checkpoint{Name: name, StartedAt: now}
+	}
+	for _, item := range items {
+		if !slices.Contains(checkpoint.Done, item) {
+			checkpoint.Done = append(checkpoint.Done, item)
+		}
+	}
+	checkpoint.UpdatedAt = now
+	r.checkpoints[name] = checkpoint
+	return nil
+}
+
+func (r *memoryCheckpointRepository) Delete(ctx context.Context, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.checkpoints, name)
+	return nil
+}
+
+type memoryStockSummaryRepository struct {
+	mu        sync.Mutex
+	summaries map[string]entity.StockSummary
+}
+
+func newMemoryStockSummaryRepository() *memoryStockSummaryRepository {
+	return &memoryStockSummaryRepository{summaries: make(map[string]entity.StockSummary)}
+}
+
+func (r *memoryStockSummaryRepository) BulkUpsert(ctx context.Context, summaries []entity.StockSummary) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, summary := range summaries {
+		r.summaries[summary.StockCode+summary.Date.Format("20060102")] = summary
+	}
+	return nil
+}
+
+func (r *memoryStockSummaryRepository) Find(ctx context.Context, code string, startDate, endDate string) ([]entity.StockSummary, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	start, _ := time.Parse("2006-01-02", startDate)
+	end, _ := time.Parse("2006-01-02", endDate)
+
+	var result []entity.StockSummary
+	for _, summary := range r.summaries {
+		if code != "" && summary.StockCode != code {
+			continue
+		}
+		if (startDate != "" && summary.Date.Before(start)) || (endDate != "" && summary.Date.After(end)) {
+			continue
+		}
+		result = append(result, summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Date.Equal(result[j].Date) {
+			return result[i].Date.Before(result[j].Date)
+		}
+		return result[i].StockCode < result[j].StockCode
+	})
+	return result, nil
+}
+
+func (r *memoryStockSummaryRepository) FindBefore(ctx context.Context, code string, before time.Time, limit int64) ([]entity.StockSummary, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []entity.StockSummary
+	for _, summary := range r.summaries {
+		if summary.StockCode == code && summary.Date.Before(before) {
+			result = append(result, summary)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date.After(result[j].Date)
+	})
+	return result[:min(int64(len(result)), limit)], nil
+}
+
+func (r *memoryStockSummaryRepository) FindLatest(ctx context.Context, code string) (*entity.StockSummary, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var latest *entity.StockSummary
+	for _, summary := range r.summaries {
+		if summary.StockCode == code && (latest == nil || summary.Date.After(latest.Date)) {
+			latest = &summary
+		}
+	}
+	return latest, nil
+}
+
+func (r *memoryStockSummaryRepository) FindLatestDate(ctx context.Context) (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var latest time.Time
+	for _, summary := range r.summaries {
+		if summary.Date.After(latest) {
+			latest = summary.Date
+		}
+	}
+	return latest, nil
+}
+
+func (r *memoryStockSummaryRepository) FindLeaders(ctx context.Context, date time.Time, metric string, descending bool, filter entity.LeaderFilter, limit int64) ([]entity.MarketLeader, error) {
+	summaries, _ := r.Find(ctx, "", date.Format("2006-01-02"), date.Format("2006-01-02"))
+
+	var leaders []entity.MarketLeader
+	values := make(map[string]float64)
+	for _, summary := range summaries {
+		if len(filter.StockCodes) > 0 && !slices.Contains(filter.StockCodes, summary.StockCode) {
+			continue
+		}
+		if summary.Value < filter.MinValue || summary.Volume < filter.MinVolume {
+			continue
+		}
+		if metric == entity.LeaderMetricChangePct && summary.Previous <= 0 {
+			continue
+		}
+
+		leader := entity.MarketLeader{StockSummary: summary, ForeignNet: summary.ForeignBuy - summary.ForeignSell}
+		values[summary.StockCode] = map[string]float64{
+			entity.LeaderMetricChangePct:       leader.ChangePct,
+			entity.LeaderMetricValue:           summary.Value,
+			entity.LeaderMetricVolume:          summary.Volume,
+			entity.LeaderMetricFrequency:       summary.Frequency,
+			entity.LeaderMetricForeignNetValue: leader.ForeignNetValue,
+		}[metric]
+		leaders = append(leaders, leader)
+	}
+
+	sort.SliceStable(leaders, func(i, j int) bool {
+		a, b := values[leaders[i].StockCode], values[leaders[j].StockCode]
+		if a != b {
+			return (a < b) != descending
+		}
+		return leaders[i].StockCode < leaders[j].StockCode
+	})
+	return leaders[:min(int64(len(leaders)), limit)], nil
+}
+
+func (r *memoryStockSummaryRepository) FindPeriodSummaries(ctx context.Context, startDate, endDate time.Time) ([]entity.StockPeriodSummary, error) {
+	summaries, _ := r.Find(ctx, "", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+
+	var codes []string
+	periods := make(map[string]*entity.StockPeriodSummary)
+	logReturns := make(map[string]float64)
+	for _, summary := range summaries {
+		period, ok := periods[summary.StockCode]
+		if !ok {
+			period = &entity.StockPeriodSummary{
+				StockCode: summary.StockCode,
+				FirstDate: summary.Date,
+				Previous:  summary.Previous,
+				MarketCap: summary.Previous * summary.ListedShares,
+			}
+			periods[summary.StockCode] = period
+			codes = append(codes, summary.StockCode)
+		}
+		period.StockName = summary.StockName
+		period.LastDate = summary.Date
+		period.Close = summary.Close
+		period.Value += summary.Value
+		period.Volume += summary.Volume
+		period.ForeignNetValue += summary.ForeignNetValue
+		if summary.Previous > 0 && summary.Close > 0 {
+			logReturns[summary.StockCode] += math.Log(summary.Close / summary.Previous)
+		}
+		period.Days++
+	}
+
+	sort.Strings(codes)
+	result := make([]entity.StockPeriodSummary, 0, len(codes))
+	for _, code := range codes {
+		period := periods[code]
+		period.Return = math.Exp(logReturns[code]) - 1
+		result = append(result, *period)
+	}
+	return result, nil
+}
+
+func (r *memoryStockSummaryRepository) FindRanges(ctx context.Context, startDate, endDate time.Time) ([]entity.StockRange, error) {
+	summaries, _ := r.Find(ctx, "", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+
+	var codes []string
+	ranges := make(map[string]*entity.StockRange)
+	for _, summary := range summaries {
+		stockRange, ok := ranges[summary.StockCode]
+		if !ok {
+			stockRange = &entity.StockRange{StockCode: summary.StockCode}
+			ranges[summary.StockCode] = stockRange
+			codes = append(codes, summary.StockCode)
+		}
+		stockRange.High = max(stockRange.High, summary.High)
+		if summary.Low > 0 && (stockRange.Low == 0 || summary.Low < stockRange.Low) {
+			stockRange.Low = summary.Low
+		}
+		stockRange.AverageClose += summary.Close
+		stockRange.Days++
+	}
+
+	result := make([]entity.StockRange, 0, len(codes))
+	for _, code := range codes {
+		stockRange := ranges[code]
+		stockRange.AverageClose /= float64(stockRange.Days)
+		result = append(result, *stockRange)
+	}
+	return result, nil
+}
+
+func (r *memoryStockSummaryRepository) NormalizePercentages(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (r *memoryStockSummaryRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+func (r *memoryStockSummaryRepository) FindDates(ctx context.Context, startDate, endDate time.Time) ([]time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var dates []time.Time
+	for _, summary := range r.summaries {
+		if summary.Date.Before(startDate) || summary.Date.After(endDate) || slices.ContainsFunc(dates, summary.Date.Equal) {
+			continue
+		}
+		dates = append(dates, summary.Date)
+	}
+	return dates, nil
+}
+
+type memoryBrokerRepository struct {
+	brokers map[string]entity.Broker
+}
+
+func newMemoryBrokerRepository() *memoryBrokerRepository {
+	return &memoryBrokerRepository{brokers: make(map[string]entity.Broker)}
+}
+
+func (r *memoryBrokerRepository) BulkUpsert(ctx context.Context, brokers []entity.Broker) error {
+	for _, broker := range brokers {
+		r.brokers[broker.Code] = broker
+	}
+	return nil
+}
+
+func (r *memoryBrokerRepository) Find(ctx context.Context, code string) ([]entity.Broker, error) {
+	var result []entity.Broker
+	for _, broker := range r.brokers {
+		if code == "" || broker.Code == code {
+			result = append(result, broker)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Code < result[j].Code
+	})
+	return result, nil
+}
+
+type memoryFinancialReportRepository struct {
+	reports map[string]entity.FinancialReport
+}
+
+func newMemoryFinancialReportRepository() *memoryFinancialReportRepository {
+	return &memoryFinancialReportRepository{reports: make(map[string]entity.FinancialReport)}
+}
+
+func (r *memoryFinancialReportRepository) BulkUpsert(ctx context.Context, reports []entity.FinancialReport) error {
+	for _, report := range reports {
+		r.reports[report.StockCode+report.ReportPeriod+report.ReportYear] = report
+	}
+	return nil
+}
+
+func (r *memoryFinancialReportRepository) Find(ctx context.Context, stockCode, reportPeriod, reportYear string) (*entity.FinancialReport, error) {
+	report, ok := r.reports[stockCode+reportPeriod+reportYear]
+	if !ok {
+		return nil, nil
+	}
+	return &report, nil
+}
+
+type memoryBrokerSummaryRepository struct {
+	summaries map[string]entity.BrokerSummary
+}
+
+func newMemoryBrokerSummaryRepository() *memoryBrokerSummaryRepository {
+	return &memoryBrokerSummaryRepository{summaries: make(map[string]entity.BrokerSummary)}
+}
+
+func brokerSummaryKey(stockCode string, startDate, endDate time.Time, investorType, board string) string {
+	return strings.Join([]string{stockCode, startDate.Format("20060102"), endDate.Format("20060102"), investorType, board}, "|")
+}
+
+func (r *memoryBrokerSummaryRepository) Upsert(ctx context.Context, summary entity.BrokerSummary) error {
+	r.summaries[brokerSummaryKey(summary.StockCode, summary.StartDate, summary.EndDate, summary.InvestorType, summary.Board)] = summary
+	return nil
+}
+
+func (r *memoryBrokerSummaryRepository) FindOne(ctx context.Context, stockCode string, startDate, endDate time.Time, investorType, board string) (*entity.BrokerSummary, error) {
+	summary, ok := r.summaries[brokerSummaryKey(stockCode, startDate, endDate, investorType, board)]
+	if !ok {
+		return nil, nil
+	}
+	return &summary, nil
+}
+
+func (r *memoryBrokerSummaryRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// countingIndopremierClient counts the broker summaries requested from Indopremier.
+type countingIndopremierClient struct {
+	indopremier.IndopremierClient
+	calls int
+}
+
+func (c *countingIndopremierClient) GetBrokerSummary(ctx context.Context, stockCode, startDate, endDate, investorType, board string) (*indopremier.GetBrokerSummaryResponse, error) {
+	c.calls++
+	return c.IndopremierClient.GetBrokerSummary(ctx, stockCode, startDate, endDate, investorType, board)
+}
+
+type memoryMarketBreadthRepository struct {
+	breadths map[time.Time]entity.MarketBreadth
+}
+
+func newMemoryMarketBreadthRepository() *memoryMarketBreadthRepository {
+	return &memoryMarketBreadthRepository{breadths: make(map[time.Time]entity.MarketBreadth)}
+}
+
+func (r *memoryMarketBreadthRepository) Upsert(ctx context.Context, breadth entity.MarketBreadth) error {
+	r.breadths[breadth.Date] = breadth
+	return nil
+}
+
+func (r *memoryMarketBreadthRepository) Find(ctx context.Context, startDate, endDate time.Time) ([]entity.MarketBreadth, error) {
+	var result []entity.MarketBreadth
+	for date, breadth := range r.breadths {
+		if !date.Before(startDate) && !date.After(endDate) {
+			result = append(result, breadth)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date.Before(result[j].Date)
+	})
+	return result, nil
+}
+
+func (r *memoryMarketBreadthRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+type memoryBasketRepository struct {
+	baskets map[string]entity.Basket
+}
+
+func newMemoryBasketRepository() *memoryBasketRepository {
+	return &memoryBasketRepository{baskets: make(map[string]entity.Basket)}
+}
+
+func (r *memoryBasketRepository) Upsert(ctx context.Context, basket entity.Basket) error {
+	r.baskets[basket.ID] = basket
+	return nil
+}
+
+func (r *memoryBasketRepository) FindOne(ctx context.Context, id string) (*entity.Basket, error) {
+	basket, ok := r.baskets[id]
+	if !ok {
+		return nil, nil
+	}
+	return &basket, nil
+}
+
+func (r *memoryBasketRepository) All(ctx context.Context) ([]entity.Basket, error) {
+	var result []entity.Basket
+	for _, basket := range r.baskets {
+		result = append(result, basket)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+func (r *memoryBasketRepository) Delete(ctx context.Context, id string) error {
+	delete(r.baskets, id)
+	return nil
+}
+
+type memoryTradeRepository struct {
+	trades []entity.Trade
+}
+
+func newMemoryTradeRepository() *memoryTradeRepository {
+	return &memoryTradeRepository{}
+}
+
+func (r *memoryTradeRepository) Insert(ctx context.Context, trade entity.Trade) error {
+	r.trades = append(r.trades, trade)
+	return nil
+}
+
+func (r *memoryTradeRepository) Find(ctx context.Context, portfolio string) ([]entity.Trade, error) {
+	var result []entity.Trade
+	for _, trade := range r.trades {
+		if trade.Portfolio == portfolio {
+			result = append(result, trade)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Date.Before(result[j].Date)
+	})
+	return result, nil
+}
+
+func (r *memoryTradeRepository) FindOne(ctx context.Context, portfolio, id string) (*entity.Trade, error) {
+	for _, trade := range r.trades {
+		if trade.Portfolio == portfolio && trade.ID == id {
+			return &trade, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryTradeRepository) Delete(ctx context.Context, portfolio, id string) error {
+	r.trades = slices.DeleteFunc(r.trades, func(trade entity.Trade) bool {
+		return trade.Portfolio == portfolio && trade.ID == id
+	})
+	return nil
+}
+
+func (r *memoryTradeRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+type memoryWatchlistRepository struct {
+	watchlists map[string]entity.Watchlist
+}
+
+func newMemoryWatchlistRepository() *memoryWatchlistRepository {
+	return &memoryWatchlistRepository{watchlists: make(map[string]entity.Watchlist)}
+}
+
+func (r *memoryWatchlistRepository) Upsert(ctx context.Context, watchlist entity.Watchlist) error {
+	r.watchlists[watchlist.UserID+"/"+watchlist.ID] = watchlist
+	return nil
+}
+
+func (r *memoryWatchlistRepository) FindOne(ctx context.Context, userID, id string) (*entity.Watchlist, error) {
+	watchlist, ok := r.watchlists[userID+"/"+id]
+	if !ok {
+		return nil, nil
+	}
+	return &watchlist, nil
+}
+
+func (r *memoryWatchlistRepository) Find(ctx context.Context, userID string) ([]entity.Watchlist, error) {
+	var result []entity.Watchlist
+	for _, watchlist := range r.watchlists {
+		if watchlist.UserID == userID {
+			result = append(result, watchlist)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+func (r *memoryWatchlistRepository) Delete(ctx context.Context, userID, id string) error {
+	delete(r.watchlists, userID+"/"+id)
+	return nil
+}
+
+func (r *memoryWatchlistRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+type memoryAlertRuleRepository struct {
+	rules map[string]entity.AlertRule
+}
+
+func newMemoryAlertRuleRepository() *memoryAlertRuleRepository {
+	return &memoryAlertRuleRepository{rules: make(map[string]entity.AlertRule)}
+}
+
+func (r *memoryAlertRuleRepository) Upsert(ctx context.Context, rule entity.AlertRule) error {
+	r.rules[rule.ID] = rule
+	return nil
+}
+
+func (r *memoryAlertRuleRepository) UpdateState(ctx context.Context, rule entity.AlertRule) (bool, error) {
+	stored, ok := r.rules[rule.ID]
+	if !ok {
+		return false, nil
+	}
+	stored.Triggered = rule.Triggered
+	stored.LastEvaluated = rule.LastEvaluated
+	stored.LastTriggered = rule.LastTriggered
+	r.rules[rule.ID] = stored
+	return true, nil
+}
+
+func (r *memoryAlertRuleRepository) FindOne(ctx context.Context, id string) (*entity.AlertRule, error) {
+	rule, ok := r.rules[id]
+	if !ok {
+		return nil, nil
+	}
+	return &rule, nil
+}
+
+func (r *memoryAlertRuleRepository) All(ctx context.Context) ([]entity.AlertRule, error) {
+	var result []entity.AlertRule
+	for _, rule := range r.rules {
+		result = append(result, rule)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].StockCode+result[i].Name < result[j].StockCode+result[j].Name
+	})
+	return result, nil
+}
+
+func (r *memoryAlertRuleRepository) Delete(ctx context.Context, id string) error {
+	delete(r.rules, id)
+	return nil
+}
+
+type memoryAlertRepository struct {
+	alerts []entity.Alert
+}
+
+func newMemoryAlertRepository() *memoryAlertRepository {
+	return &memoryAlertRepository{}
+}
+
+func (r *memoryAlertRepository) Insert(ctx context.Context, alert entity.Alert) (bool, error) {
+	for _, stored := range r.alerts {
+		if stored.RuleID == alert.RuleID && stored.Date.Equal(alert.Date) {
+			return false, nil
+		}
+	}
+	r.alerts = append(r.alerts, alert)
+	return true, nil
+}
+
+func (r *memoryAlertRepository) Update(ctx context.Context, alert entity.Alert) error {
+	for i := range r.alerts {
+		if r.alerts[i].ID == alert.ID {
+			r.alerts[i] = alert
+		}
+	}
+	return nil
+}
+
+func (r *memoryAlertRepository) Find(ctx context.Context, ruleID string, limit int64) ([]entity.Alert, error) {
+	var result []entity.Alert
+	for i := len(r.alerts) - 1; i >= 0 && (limit <= 0 || int64(len(result)) < limit); i-- {
+		if ruleID == "" || r.alerts[i].RuleID == ruleID {
+			result = append(result, r.alerts[i])
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryAlertRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// fakeWebhookClient records the payloads sent and fails with err when set.
+type fakeWebhookClient struct {
+	sent []interface{}
+	err  error
+}
+
+func (c *fakeWebhookClient) Send(ctx context.Context, url, secret string, payload interface{}) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.sent = append(c.sent, payload)
+	return nil
+}
+
+type memoryJobRunRepository struct {
+	mu   sync.Mutex
+	runs []entity.JobRun
+}
+
+func newMemoryJobRunRepository() *memoryJobRunRepository {
+	return &memoryJobRunRepository{}
+}
+
+func (r *memoryJobRunRepository) Save(ctx context.Context, run entity.JobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.runs {
+		if r.runs[i].RunID == run.RunID {
+			r.runs[i] = run
+			return nil
+		}
+	}
+	r.runs = append(r.runs, run)
+	return nil
+}
+
+func (r *memoryJobRunRepository) FindByJob(ctx context.Context, jobName string, limit int64) ([]entity.JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []entity.JobRun
+	for i := len(r.runs) - 1; i >= 0 && (limit <= 0 || int64(len(result)) < limit); i-- {
+		if r.runs[i].JobName == jobName {
+			result = append(result, r.runs[i])
+		}
+	}
+	return result, nil
+}
+
+func (r *memoryJobRunRepository) FindLatest(ctx context.Context, jobName string) (*entity.JobRun, error) {
+	runs, err := r.FindByJob(ctx, jobName, 1)
+	if err != nil || len(runs) == 0 {
+		return nil, err
+	}
+	return &runs[0], nil
+}
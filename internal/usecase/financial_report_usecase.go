@@ -10,7 +10,7 @@ import (
 )
 
 type FinancialReportUseCase interface {
-	UpdateFinancialReport(ctx context.Context, period string, year string) error
+	UpdateFinancialReport(ctx context.Context, period string, year string) (int, error)
 	Find(ctx context.Context, stockCode, reportPeriod, reportYear string) (*entity.FinancialReport, error)
 }
 
@@ -28,10 +28,10 @@ func NewFinancialReportUseCase(cfg config.Config, idxClient idx.IdxClient, finan
 	}
 }
 
-func (b *financialReportUseCase) UpdateFinancialReport(ctx context.Context, period string, year string) error {
+func (b *financialReportUseCase) UpdateFinancialReport(ctx context.Context, period string, year string) (int, error) {
 	list, err := b.idxClient.GetFinancialReports(ctx, period, year)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	var financialReports []entity.FinancialReport
@@ -64,14 +64,14 @@ func (b *financialReportUseCase) UpdateFinancialReport(ctx context.Context, peri
 	}
 
 	if len(financialReports) == 0 {
-		return nil // no broker data to update
+		return 0, nil // no broker data to update
 	}
 
 	if err := b.financialReportRepository.BulkUpsert(ctx, financialReports); err != nil {
-		return fmt.Errorf("bulk upsert failed: %w", err)
+		return 0, fmt.Errorf("bulk upsert failed: %w", err)
 	}
 
-	return nil
+	return len(financialReports), nil
 }
 
 func (b *financialReportUseCase) Find(ctx context.Context, stockCode, reportPeriod, reportYear string) (*entity.FinancialReport, error) {
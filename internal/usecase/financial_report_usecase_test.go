@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestUpdateFinancialReport(t *testing.T) {
+	cfg := newTestConfig()
+	uc := NewFinancialReportUseCase(cfg, newTestIdxClient(cfg), newMemoryFinancialReportRepository())
+
+	records, err := uc.UpdateFinancialReport(context.Background(), "TW1", "2025")
+	if err != nil {
+		t.Fatalf("UpdateFinancialReport: %v", err)
+	}
+	if records != 2 {
+		t.Errorf("records = %d, want 2", records)
+	}
+
+	report, err := uc.Find(context.Background(), "BBCA", "TW1", "2025")
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if report == nil {
+		t.Fatal("BBCA TW1 2025 report is not stored")
+	}
+	if len(report.Attachment) != 2 {
+		t.Fatalf("got %d attachments, want 2", len(report.Attachment))
+	}
+	for _, attachment := range report.Attachment {
+		if !strings.HasPrefix(attachment.FilePath, "https://www.idx.co.id/StaticData/") {
+			t.Errorf("file path = %s, want an absolute IDX URL", attachment.FilePath)
+		}
+	}
+}
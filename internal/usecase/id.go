@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// newID returns a random identifier for stored records: job runs, watchlists,
+// portfolios, baskets, alert rules and alerts.
+func newID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"go-stock/internal/entity"
+	"go-stock/internal/indicator"
+	"go-stock/internal/repository"
+	"slices"
+	"time"
+)
+
+type IndicatorUseCase interface {
+	FindIndicators(ctx context.Context, stockCode, startDate, endDate string, specs []indicator.Spec) (*entity.StockIndicators, error)
+}
+
+type indicatorUseCase struct {
+	stockSummaryRepository repository.StockSummaryRepository
+}
+
+func NewIndicatorUseCase(stockSummaryRepository repository.StockSummaryRepository) IndicatorUseCase {
+	return &indicatorUseCase{
+		stockSummaryRepository: stockSummaryRepository,
+	}
+}
+
+// FindIndicators computes the indicators over the stored summaries of the stock
+// between startDate and endDate (YYYY-MM-DD). The summaries before startDate the
+// indicators need to warm up are loaded as well, so values are stable from the
+// first date on; without enough history the first values are nil. It returns nil
+// when the stock has no summaries in the range.
+func (u *indicatorUseCase) FindIndicators(ctx context.Context, stockCode, startDate, endDate string, specs []indicator.Spec) (*entity.StockIndicators, error) {
+	summaries, err := u.stockSummaryRepository.Find(ctx, stockCode, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	if len(summaries) == 0 {
+		return nil, nil
+	}
+
+	var warmUp []entity.StockSummary
+	if lookback := indicator.Lookback(specs...); lookback > 0 {
+		warmUp, err = u.stockSummaryRepository.FindBefore(ctx, stockCode, summaries[0].Date, int64(lookback))
+		if err != nil {
+			return nil, err
+		}
+		slices.Reverse(warmUp)
+	}
+
+	bars := make([]indicator.Bar, 0, len(warmUp)+len(summaries))
+	for _, summary := range slices.Concat(warmUp, summaries) {
+		bars = append(bars, toBar(summary))
+	}
+
+	result := &entity.StockIndicators{
+		StockCode: stockCode,
+		Dates:     make([]time.Time, 0, len(summaries)),
+		Close:     make([]float64, 0, len(summaries)),
+	}
+	for _, summary := range summaries {
+		result.Dates = append(result.Dates, summary.Date)
+		result.Close = append(result.Close, summary.Close)
+	}
+
+	for _, spec := range specs {
+		series, err := indicator.Compute(bars, spec)
+		if err != nil {
+			return nil, err
+		}
+		series = series.Trim(len(warmUp))
+
+		lines := make([]entity.IndicatorLine, 0, len(series.Lines))
+		for _, line := range series.Lines {
+			lines = append(lines, entity.IndicatorLine{
+				Name:   line.Name,
+				Values: line.Values,
+			})
+		}
+		result.Series = append(result.Series, entity.IndicatorSeries{
+			Name:  spec.String(),
+			Lines: lines,
+		})
+	}
+
+	return result, nil
+}
+
+// toBar maps a summary to a bar. A day without trades has no open, high or low,
+// its bar stays flat at the close.
+func toBar(summary entity.StockSummary) indicator.Bar {
+	bar := indicator.Bar{
+		Date:   summary.Date,
+		Open:   summary.OpenPrice,
+		High:   summary.High,
+		Low:    summary.Low,
+		Close:  summary.Close,
+		Volume: summary.Volume,
+		Value:  summary.Value,
+	}
+	if bar.Volume == 0 || bar.High == 0 || bar.Low == 0 {
+		bar.Open, bar.High, bar.Low = bar.Close, bar.Close, bar.Close
+	}
+	if bar.Open == 0 {
+		bar.Open = summary.Previous
+	}
+	return bar
+}
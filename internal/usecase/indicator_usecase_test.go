@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"go-stock/internal/entity"
+	"go-stock/internal/indicator"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFindIndicatorsWarmUp(t *testing.T) {
+	summaries := newMemoryStockSummaryRepository()
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	var history []entity.StockSummary
+	for i := range 200 {
+		price := 1000 + 50*math.Sin(float64(i)/7)
+		history = append(history, entity.StockSummary{
+			StockCode: "BBCA",
+			Date:      start.AddDate(0, 0, i),
+			OpenPrice: price,
+			High:      price + 10,
+			Low:       price - 10,
+			Close:     price,
+			Volume:    1000,
+		})
+	}
+	summaries.BulkUpsert(context.Background(), history)
+	uc := NewIndicatorUseCase(summaries)
+
+	specs, _ := indicator.ParseSpecs("sma:5,rsi:14")
+	result, err := uc.FindIndicators(context.Background(), "BBCA", "2024-07-01", "2024-07-10", specs)
+	if err != nil {
+		t.Fatalf("FindIndicators: %v", err)
+	}
+	if len(result.Dates) != 10 || result.Series[0].Name != "sma:5" {
+		t.Fatalf("got %d dates, series %+v", len(result.Dates), result.Series)
+	}
+
+	// The SMA is exact from the first date on, the RSI matches the one computed
+	// over the whole history within the warm-up tolerance.
+	offset := int(result.Dates[0].Sub(start).Hours() / 24)
+	var bars []indicator.Bar
+	for _, summary := range history {
+		bars = append(bars, toBar(summary))
+	}
+	for i, spec := range specs {
+		full, _ := indicator.Compute(bars, spec)
+		for j, value := range result.Series[i].Lines[0].Values {
+			want := *full.Lines[0].Values[offset+j]
+			if value == nil || math.Abs(*value-want) > 0.5 {
+				t.Errorf("%s on %s = %v, want %v", spec, result.Dates[j].Format("2006-01-02"), value, want)
+			}
+		}
+	}
+}
+
+func TestFindIndicatorsShortHistory(t *testing.T) {
+	summaries := newMemoryStockSummaryRepository()
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		summaries.BulkUpsert(context.Background(), []entity.StockSummary{
+			{StockCode: "BBCA", Date: start.AddDate(0, 0, i), Close: float64(100 + i)},
+		})
+	}
+	uc := NewIndicatorUseCase(summaries)
+
+	specs, _ := indicator.ParseSpecs("sma:2")
+	result, err := uc.FindIndicators(context.Background(), "BBCA", "2025-01-01", "2025-01-03", specs)
+	if err != nil {
+		t.Fatalf("FindIndicators: %v", err)
+	}
+	values := result.Series[0].Lines[0].Values
+	if values[0] != nil || *values[1] != 100.5 || *values[2] != 101.5 {
+		t.Errorf("sma:2 = %v", values)
+	}
+
+	result, err = uc.FindIndicators(context.Background(), "BBRI", "2025-01-01", "2025-01-03", specs)
+	if err != nil || result != nil {
+		t.Errorf("got %+v, %v; want nil for a stock without summaries", result, err)
+	}
+}
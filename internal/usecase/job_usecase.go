@@ -0,0 +1,422 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-stock/internal/config"
+	"go-stock/internal/entity"
+	"go-stock/internal/repository"
+	"go-stock/internal/shared/cron"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	robfig "github.com/robfig/cron/v3"
+)
+
+const (
+	JobUpdateSummaries       = "UpdateSummaries"
+	JobUpdateStock           = "UpdateStock"
+	JobUpdateBroker          = "UpdateBroker"
+	JobUpdateFinancialReport = "UpdateFinancialReport"
+	JobUpdateBrokerSummary   = "UpdateBrokerSummary"
+	JobUpdateMarketBreadth   = "UpdateMarketBreadth"
+	JobEvaluateAlerts        = "EvaluateAlerts"
+	JobBackfillSummaries     = "BackfillSummaries"
+)
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobRunning  = errors.New("job is already running")
+)
+
+// JobFunc runs a job and reports how many records it upserted. Empty params fall
+// back to values derived from the current date.
+type JobFunc func(ctx context.Context, params entity.JobParams) (int, error)
+
+type JobUseCase interface {
+	Schedule(ctx context.Context, name, spec string) error
+	Trigger(ctx context.Context, name string, params entity.JobParams) (*entity.JobRun, error)
+	Start(ctx context.Context)
+	Stop() context.Context
+	ListJobs(ctx context.Context) ([]entity.Job, error)
+	FindJob(ctx context.Context, name string) (*entity.Job, error)
+	FindRuns(ctx context.Context, name string, limit int64) ([]entity.JobRun, error)
+}
+
+type job struct {
+	name    string
+	run     JobFunc
+	spec    string
+	entryID robfig.EntryID
+}
+
+type jobUseCase struct {
+	cronClient       cron.CronClient
+	jobRunRepository repository.JobRunRepository
+	calendarUsecase  CalendarUseCase
+	location         *time.Location
+
+	mu      sync.RWMutex
+	ctx     context.Context // lifetime of the runner, set by Start
+	jobs    []*job
+	running map[string]string // job name to run ID
+}
+
+func NewJobUseCase(
+	cfg config.Config,
+	cronClient cron.CronClient,
+	jobRunRepository repository.JobRunRepository,
+	calendarUsecase CalendarUseCase,
+	stockUsecase StockUseCase,
+	stockSummaryUsecase StockSummaryUseCase,
+	marketUsecase MarketUseCase,
+	alertUsecase AlertUseCase,
+	brokerUsecase BrokerUseCase,
+	financialReportUsecase FinancialReportUseCase,
+	brokerSummaryUsecase BrokerSummaryUseCase,
+) JobUseCase {
+	location, err := time.LoadLocation(cfg.GetApplication().Timezone)
+	if err != nil {
+		log.Printf("⚠️ Invalid timezone %q: defaulting to UTC", cfg.GetApplication().Timezone)
+		location = time.UTC
+	}
+
+	j := &jobUseCase{
+		cronClient:       cronClient,
+		jobRunRepository: jobRunRepository,
+		calendarUsecase:  calendarUsecase,
+		location:         location,
+		ctx:              context.Background(),
+		running:          make(map[string]string),
+	}
+
+	j.register(JobUpdateSummaries, func(ctx context.Context, params entity.JobParams) (int, error) {
+		date, skip, err := j.tradingDate(ctx, JobUpdateSummaries, params)
+		if err != nil || skip {
+			return 0, err
+		}
+		return stockSummaryUsecase.UpdateSummaries(ctx, date.Format("20060102"))
+	})
+	j.register(JobUpdateStock, func(ctx context.Context, _ entity.JobParams) (int, error) {
+		return stockUsecase.UpdateStock(ctx)
+	})
+	j.register(JobUpdateBroker, func(ctx context.Context, _ entity.JobParams) (int, error) {
+		return brokerUsecase.UpdateBroker(ctx)
+	})
+	j.register(JobUpdateFinancialReport, func(ctx context.Context, params entity.JobParams) (int, error) {
+		period, year := financialReportPeriod(time.Now().In(j.location))
+		if params.Period != "" {
+			period = params.Period
+		}
+		if params.Year != "" {
+			year = params.Year
+		}
+		log.Printf("Update financial report for year %s and period %s", year, period)
+		return financialReportUsecase.UpdateFinancialReport(ctx, period, year)
+	})
+	j.register(JobUpdateBrokerSummary, func(ctx context.Context, params entity.JobParams) (int, error) {
+		date, skip, err := j.tradingDate(ctx, JobUpdateBrokerSummary, params)
+		if err != nil || skip {
+			return 0, err
+		}
+		return brokerSummaryUsecase.SnapshotBrokerSummaries(ctx, date)
+	})
+	// UpdateSummaries already computes the breadth of the day it stores; without a
+	// date this job fills in the days stored before that.
+	j.register(JobUpdateMarketBreadth, func(ctx context.Context, params entity.JobParams) (int, error) {
+		if params.Date == "" {
+			return marketUsecase.BackfillBreadth(ctx)
+		}
+		date, err := time.Parse("2006-01-02", params.Date)
+		if err != nil {
+			return 0, fmt.Errorf("invalid date %q: %w", params.Date, err)
+		}
+		return marketUsecase.UpdateBreadth(ctx, date)
+	})
+	// UpdateSummaries already evaluates the alerts of the day it stores; this job
+	// evaluates a day again after rules were added, latest stored day by default.
+	j.register(JobEvaluateAlerts, func(ctx context.Context, params entity.JobParams) (int, error) {
+		if params.Date == "" {
+			return alertUsecase.EvaluateAlerts(ctx, time.Time{})
+		}
+		date, err := time.Parse("2006-01-02", params.Date)
+		if err != nil {
+			return 0, fmt.Errorf("invalid date %q: %w", params.Date, err)
+		}
+		return alertUsecase.EvaluateAlerts(ctx, date)
+	})
+	// BackfillSummaries ingests the missing trading days of a date range. It needs
+	// both dates, so it is only triggered, never scheduled; the days that failed
+	// are reported in the error of the run.
+	j.register(JobBackfillSummaries, func(ctx context.Context, params entity.JobParams) (int, error) {
+		startDate, err := time.Parse("2006-01-02", params.StartDate)
+		if err != nil {
+			return 0, fmt.Errorf("invalid start date %q: %w", params.StartDate, err)
+		}
+		endDate, err := time.Parse("2006-01-02", params.EndDate)
+		if err != nil {
+			return 0, fmt.Errorf("invalid end date %q: %w", params.EndDate, err)
+		}
+
+		results, err := stockSummaryUsecase.BackfillSummaries(ctx, startDate, endDate)
+		records := 0
+		var failures []string
+		for _, result := range results {
+			records += result.Records
+			if result.Status == entity.BackfillStatusFailed {
+				failures = append(failures, fmt.Sprintf("%s: %s", result.Date.Format("2006-01-02"), result.Reason))
+			}
+		}
+		if err != nil {
+			return records, err
+		}
+		if len(failures) > 0 {
+			return records, fmt.Errorf("%d of %d days failed: %s", len(failures), len(results), strings.Join(failures, "; "))
+		}
+		return records, nil
+	})
+
+	return j
+}
+
+// tradingDate returns the date param of a job fetching a trading day, or today in
+// the timezone of the application. Without a date param the run is skipped when
+// today is no trading day, e.g. a holiday or collective leave day.
+func (j *jobUseCase) tradingDate(ctx context.Context, name string, params entity.JobParams) (time.Time, bool, error) {
+	if params.Date != "" {
+		date, err := time.Parse("2006-01-02", params.Date)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q: %w", params.Date, err)
+		}
+		return date, false, nil
+	}
+
+	now := time.Now().In(j.location)
+	day, err := j.calendarUsecase.FindDay(ctx, now)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if !day.Trading() {
+		reason := string(day.Status)
+		if day.Holiday != "" {
+			reason = day.Holiday
+		}
+		log.Printf("⏭️ Skipping %s: %s is no trading day (%s)", name, day.Date.Format("2006-01-02"), reason)
+		return now, true, nil
+	}
+	return now, false, nil
+}
+
+func (j *jobUseCase) register(name string, run JobFunc) {
+	j.jobs = append(j.jobs, &job{name: name, run: run})
+}
+
+func (j *jobUseCase) find(name string) *job {
+	for _, job := range j.jobs {
+		if job.name == name {
+			return job
+		}
+	}
+	return nil
+}
+
+// Schedule registers the named job on the cron scheduler. Scheduled runs use ctx,
+// so cancelling it aborts any run in progress.
+func (j *jobUseCase) Schedule(ctx context.Context, name, spec string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	job := j.find(name)
+	if job == nil {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, name)
+	}
+
+	entryID, err := j.cronClient.AddJob(spec, func() {
+		run, err := j.begin(ctx, job, entity.JobTriggerSchedule, entity.JobParams{})
+		if err != nil {
+			log.Printf("⏭️ Skipping scheduled %s run: %v", job.name, err)
+			return
+		}
+		j.execute(ctx, job, run)
+	})
+	if err != nil {
+		return err
+	}
+
+	job.spec = spec
+	job.entryID = entryID
+	return nil
+}
+
+// Start starts the scheduler. Manual runs triggered afterwards run under ctx, so
+// cancelling it on shutdown aborts them like the scheduled ones.
+func (j *jobUseCase) Start(ctx context.Context) {
+	j.mu.Lock()
+	j.ctx = ctx
+	j.mu.Unlock()
+
+	j.cronClient.Start()
+}
+
+func (j *jobUseCase) Stop() context.Context {
+	return j.cronClient.Stop()
+}
+
+// Trigger starts a run of the named job in the background and returns it right away.
+// It fails with ErrJobRunning while another run of the same job is in progress.
+func (j *jobUseCase) Trigger(ctx context.Context, name string, params entity.JobParams) (*entity.JobRun, error) {
+	j.mu.RLock()
+	job := j.find(name)
+	// The run outlives the request that triggered it, but not the runner.
+	runCtx := j.ctx
+	j.mu.RUnlock()
+	if job == nil {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, name)
+	}
+
+	run, err := j.begin(runCtx, job, entity.JobTriggerManual, params)
+	if err != nil {
+		return nil, err
+	}
+
+	go j.execute(runCtx, job, run)
+
+	return &run, nil
+}
+
+// begin marks the job as running and records the start of the run.
+func (j *jobUseCase) begin(ctx context.Context, job *job, trigger entity.JobTrigger, params entity.JobParams) (entity.JobRun, error) {
+	run := entity.JobRun{
+		RunID:     newID(),
+		JobName:   job.name,
+		Status:    entity.JobRunStatusRunning,
+		Trigger:   trigger,
+		Params:    params,
+		StartedAt: time.Now(),
+	}
+
+	j.mu.Lock()
+	if runID, ok := j.running[job.name]; ok {
+		j.mu.Unlock()
+		return entity.JobRun{}, fmt.Errorf("%w: run %s", ErrJobRunning, runID)
+	}
+	j.running[job.name] = run.RunID
+	j.mu.Unlock()
+
+	if err := j.jobRunRepository.Save(ctx, run); err != nil {
+		log.Printf("⚠️ Failed to record start of %s run %s: %v", job.name, run.RunID, err)
+	}
+
+	return run, nil
+}
+
+// execute runs the job, records the outcome and releases the job for the next run.
+func (j *jobUseCase) execute(ctx context.Context, job *job, run entity.JobRun) {
+	defer func() {
+		j.mu.Lock()
+		delete(j.running, job.name)
+		j.mu.Unlock()
+	}()
+
+	records, err := job.run(ctx, run.Params)
+
+	run.FinishedAt = time.Now()
+	run.Duration = run.FinishedAt.Sub(run.StartedAt)
+	run.Records = records
+	run.Status = entity.JobRunStatusSuccess
+	if err != nil {
+		run.Status = entity.JobRunStatusFailed
+		run.Error = err.Error()
+		log.Printf("❌ %s run %s failed after %s: %v", job.name, run.RunID, run.Duration, err)
+	} else {
+		log.Printf("✅ %s run %s upserted %d records in %s", job.name, run.RunID, records, run.Duration)
+	}
+
+	// The run is recorded even when ctx was cancelled mid-run.
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	if err := j.jobRunRepository.Save(saveCtx, run); err != nil {
+		log.Printf("⚠️ Failed to record %s run %s: %v", job.name, run.RunID, err)
+	}
+}
+
+func (j *jobUseCase) ListJobs(ctx context.Context) ([]entity.Job, error) {
+	j.mu.RLock()
+	names := make([]string, 0, len(j.jobs))
+	for _, job := range j.jobs {
+		names = append(names, job.name)
+	}
+	j.mu.RUnlock()
+
+	jobs := make([]entity.Job, 0, len(names))
+	for _, name := range names {
+		job, err := j.FindJob(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+
+	return jobs, nil
+}
+
+func (j *jobUseCase) FindJob(ctx context.Context, name string) (*entity.Job, error) {
+	j.mu.RLock()
+	job := j.find(name)
+	if job == nil {
+		j.mu.RUnlock()
+		return nil, nil
+	}
+	result := entity.Job{
+		Name:     job.name,
+		Schedule: job.spec,
+	}
+	entryID := job.entryID
+	_, result.Running = j.running[name]
+	j.mu.RUnlock()
+
+	if entryID != 0 {
+		for _, entry := range j.cronClient.Entries() {
+			if entry.ID == entryID {
+				result.NextRun = entry.Next
+				result.PreviousRun = entry.Prev
+				break
+			}
+		}
+	}
+
+	lastRun, err := j.jobRunRepository.FindLatest(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	result.LastRun = lastRun
+
+	return &result, nil
+}
+
+func (j *jobUseCase) FindRuns(ctx context.Context, name string, limit int64) ([]entity.JobRun, error) {
+	return j.jobRunRepository.FindByJob(ctx, name, limit)
+}
+
+// financialReportPeriod returns the latest report period published by the given time.
+// Audit reports cover the previous year.
+func financialReportPeriod(now time.Time) (period string, year string) {
+	y := now.Year()
+
+	switch month := now.Month(); {
+	case month >= 4 && month <= 6:
+		period = "TW1"
+	case month >= 7 && month <= 9:
+		period = "TW2"
+	case month >= 10 && month <= 12:
+		period = "TW3"
+	default:
+		period = "Audit"
+		y -= 1
+	}
+
+	return period, fmt.Sprintf("%d", y)
+}
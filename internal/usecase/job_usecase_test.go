@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"go-stock/internal/entity"
+	"go-stock/internal/shared/cron"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestJobsRunConcurrently runs the jobs taking a date side by side, as the
+// scheduler does; run it with -race to catch state shared between their runs.
+func TestJobsRunConcurrently(t *testing.T) {
+	j := NewJobUseCase(newTestConfig(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*jobUseCase)
+
+	names := []string{JobUpdateSummaries, JobUpdateBrokerSummary, JobUpdateMarketBreadth, JobEvaluateAlerts}
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = j.find(name).run(context.Background(), entity.JobParams{Date: name})
+		}()
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		if errs[i] == nil || !strings.Contains(errs[i].Error(), `invalid date "`+name+`"`) {
+			t.Errorf("%s: err = %v, want its own invalid date", name, errs[i])
+		}
+	}
+}
+
+func TestBackfillSummariesJob(t *testing.T) {
+	cfg := newTestConfig()
+	summaries := newMemoryStockSummaryRepository()
+	stockSummaryUsecase := NewStockSummaryUseCase(newTestIdxClient(cfg), NewCalendarUseCase(cfg, nil, summaries), summaries, newMemoryStockRepository())
+	j := NewJobUseCase(cfg, nil, nil, nil, nil, stockSummaryUsecase, nil, nil, nil, nil, nil).(*jobUseCase)
+	backfill := j.find(JobBackfillSummaries)
+
+	records, err := backfill.run(context.Background(), entity.JobParams{StartDate: "2025-01-01", EndDate: "2025-01-03"})
+	if err != nil || records != 8 {
+		t.Fatalf("got %d records, %v; want both trading days stored", records, err)
+	}
+
+	// 6 January has no recorded response, the run fails naming the day.
+	records, err = backfill.run(context.Background(), entity.JobParams{StartDate: "2025-01-02", EndDate: "2025-01-06"})
+	if err == nil || !strings.Contains(err.Error(), "1 of 5 days failed: 2025-01-06") || records != 0 {
+		t.Errorf("got %d records, %v; want the failed day reported", records, err)
+	}
+
+	if _, err := backfill.run(context.Background(), entity.JobParams{StartDate: "2025-01-02"}); err == nil {
+		t.Error("ran a backfill without an end date")
+	}
+}
+
+// waitForRun polls the stored run until it leaves the running status.
+func waitForRun(t *testing.T, runs *memoryJobRunRepository, name string) entity.JobRun {
+	t.Helper()
+
+	for range 200 {
+		run, err := runs.FindLatest(context.Background(), name)
+		if err != nil {
+			t.Fatalf("FindLatest: %v", err)
+		}
+		if run != nil && run.Status != entity.JobRunStatusRunning {
+			return *run
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("%s run did not finish", name)
+	return entity.JobRun{}
+}
+
+func TestTrigger(t *testing.T) {
+	runs := newMemoryJobRunRepository()
+	j := NewJobUseCase(newTestConfig(), cron.NewCronClient("UTC"), runs, nil, nil, nil, nil, nil, nil, nil, nil).(*jobUseCase)
+	release := make(chan struct{})
+	j.register("Blocking", func(ctx context.Context, params entity.JobParams) (int, error) {
+		<-release
+		return 3, nil
+	})
+
+	run, err := j.Trigger(context.Background(), "Blocking", entity.JobParams{Date: "2025-01-02"})
+	if err != nil {
+		t.Fatalf("Trigger: %v", err)
+	}
+	if stored, _ := runs.FindLatest(context.Background(), "Blocking"); stored == nil || stored.RunID != run.RunID || stored.Status != entity.JobRunStatusRunning || stored.Trigger != entity.JobTriggerManual {
+		t.Errorf("stored run = %+v, want run %s running", stored, run.RunID)
+	}
+
+	if _, err := j.Trigger(context.Background(), "Blocking", entity.JobParams{}); !errors.Is(err, ErrJobRunning) {
+		t.Errorf("second Trigger = %v, want ErrJobRunning", err)
+	}
+	if _, err := j.Trigger(context.Background(), "Missing", entity.JobParams{}); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Trigger of an unknown job = %v, want ErrJobNotFound", err)
+	}
+
+	close(release)
+	finished := waitForRun(t, runs, "Blocking")
+	if finished.RunID != run.RunID || finished.Status != entity.JobRunStatusSuccess || finished.Records != 3 || finished.Params.Date != "2025-01-02" || finished.FinishedAt.IsZero() {
+		t.Errorf("finished run = %+v, want run %s succeeded with 3 records", finished, run.RunID)
+	}
+
+	// The job is released for the next run.
+	if _, err := j.Trigger(context.Background(), "Blocking", entity.JobParams{}); err != nil {
+		t.Errorf("Trigger after the run finished: %v", err)
+	}
+	waitForRun(t, runs, "Blocking")
+}
+
+func TestTriggerStopsWithTheRunner(t *testing.T) {
+	runs := newMemoryJobRunRepository()
+	j := NewJobUseCase(newTestConfig(), cron.NewCronClient("UTC"), runs, nil, nil, nil, nil, nil, nil, nil, nil).(*jobUseCase)
+	j.register("Blocking", func(ctx context.Context, params entity.JobParams) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	j.Start(ctx)
+	defer j.Stop()
+
+	// The request that triggered the run ends without stopping it.
+	request, cancelRequest := context.WithCancel(context.Background())
+	if _, err := j.Trigger(request, "Blocking", entity.JobParams{}); err != nil {
+		t.Fatalf("Trigger: %v", err)
+	}
+	cancelRequest()
+	time.Sleep(20 * time.Millisecond)
+	if run, _ := runs.FindLatest(context.Background(), "Blocking"); run == nil || run.Status != entity.JobRunStatusRunning {
+		t.Fatalf("run = %+v after the request ended, want it running", run)
+	}
+
+	cancel()
+	run := waitForRun(t, runs, "Blocking")
+	if run.Status != entity.JobRunStatusFailed || run.Error != context.Canceled.Error() {
+		t.Errorf("run = %+v after shutdown, want it failed as cancelled", run)
+	}
+}
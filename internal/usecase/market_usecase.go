@@ -0,0 +1,515 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"go-stock/internal/entity"
+	"go-stock/internal/marketrules"
+	"go-stock/internal/repository"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// leaderboards ranks a trading day by metric; a leaderboard with a sign keeps only
+// the leaders moving that way, e.g. gainers must have gained.
+var leaderboards = []struct {
+	name       string
+	metric     string
+	descending bool
+	sign       int
+}{
+	{name: entity.LeaderboardGainers, metric: entity.LeaderMetricChangePct, descending: true, sign: 1},
+	{name: entity.LeaderboardLosers, metric: entity.LeaderMetricChangePct, sign: -1},
+	{name: entity.LeaderboardMostValue, metric: entity.LeaderMetricValue, descending: true},
+	{name: entity.LeaderboardMostVolume, metric: entity.LeaderMetricVolume, descending: true},
+	{name: entity.LeaderboardMostFrequency, metric: entity.LeaderMetricFrequency, descending: true},
+	{name: entity.LeaderboardForeignNetBuy, metric: entity.LeaderMetricForeignNetValue, descending: true, sign: 1},
+	{name: entity.LeaderboardForeignNetSell, metric: entity.LeaderMetricForeignNetValue, sign: -1},
+}
+
+type MarketUseCase interface {
+	FindLeaders(ctx context.Context, date time.Time, names []string, board string, filter entity.LeaderFilter, limit int64) (*entity.MarketLeaders, error)
+	FindSectors(ctx context.Context, startDate, endDate time.Time, group string) (*entity.SectorOverview, error)
+	FindSector(ctx context.Context, startDate, endDate time.Time, group, name string) (*entity.Sector, error)
+	UpdateBreadth(ctx context.Context, date time.Time) (int, error)
+	BackfillBreadth(ctx context.Context) (int, error)
+	FindBreadth(ctx context.Context, startDate, endDate time.Time) ([]entity.MarketBreadth, error)
+	FindPriceLimits(ctx context.Context, stockCode string, price float64) (*entity.PriceLimits, error)
+	FindAutoRejections(ctx context.Context, stockCode string, startDate, endDate time.Time) ([]entity.AutoRejection, error)
+}
+
+type marketUseCase struct {
+	stockSummaryRepository  repository.StockSummaryRepository
+	stockRepository         repository.StockRepository
+	marketBreadthRepository repository.MarketBreadthRepository
+}
+
+func NewMarketUseCase(stockSummaryRepository repository.StockSummaryRepository, stockRepository repository.StockRepository, marketBreadthRepository repository.MarketBreadthRepository) MarketUseCase {
+	return &marketUseCase{
+		stockSummaryRepository:  stockSummaryRepository,
+		stockRepository:         stockRepository,
+		marketBreadthRepository: marketBreadthRepository,
+	}
+}
+
+// FindLeaders returns the named leaderboards of a trading day, all of them when no
+// name is given. A zero date means the latest stored day. A board limits the
+// leaders to the stocks listed on it.
+func (u *marketUseCase) FindLeaders(ctx context.Context, date time.Time, names []string, board string, filter entity.LeaderFilter, limit int64) (*entity.MarketLeaders, error) {
+	if date.IsZero() {
+		latest, err := u.stockSummaryRepository.FindLatestDate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		date = latest
+	}
+	result := &entity.MarketLeaders{Date: date}
+
+	if board != "" {
+		stocks, err := u.stockRepository.All(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, stock := range stocks {
+			if strings.EqualFold(stock.Board, board) {
+				filter.StockCodes = append(filter.StockCodes, stock.StockCode)
+			}
+		}
+	}
+
+	for _, leaderboard := range leaderboards {
+		if len(names) > 0 && !containsFold(names, leaderboard.name) {
+			continue
+		}
+
+		var leaders []entity.MarketLeader
+		if !date.IsZero() && (board == "" || len(filter.StockCodes) > 0) {
+			var err error
+			leaders, err = u.stockSummaryRepository.FindLeaders(ctx, date, leaderboard.metric, leaderboard.descending, filter, limit)
+			if err != nil {
+				return nil, fmt.Errorf("find %s: %w", leaderboard.name, err)
+			}
+		}
+
+		kept := make([]entity.MarketLeader, 0, len(leaders))
+		for _, leader := range leaders {
+			value := leaderValue(leader, leaderboard.metric)
+			if leaderboard.sign > 0 && value <= 0 || leaderboard.sign < 0 && value >= 0 {
+				break
+			}
+			kept = append(kept, leader)
+		}
+		result.Leaderboards = append(result.Leaderboards, entity.Leaderboard{
+			Name:    leaderboard.name,
+			Leaders: kept,
+		})
+	}
+
+	return result, nil
+}
+
+// FindSectors aggregates the stocks by the group of their profile over a date
+// range, the latest stored day when both dates are zero. Sectors are ordered by
+// market capitalization and come without their constituents; stocks without a
+// classification are left out.
+func (u *marketUseCase) FindSectors(ctx context.Context, startDate, endDate time.Time, group string) (*entity.SectorOverview, error) {
+	overview, err := u.sectors(ctx, startDate, endDate, group)
+	if err != nil {
+		return nil, err
+	}
+	for i := range overview.Sectors {
+		overview.Sectors[i].Constituents = nil
+	}
+	return overview, nil
+}
+
+// FindSector returns one sector of FindSectors with its constituents ordered by
+// weight, or nil when no stock is classified under the name.
+func (u *marketUseCase) FindSector(ctx context.Context, startDate, endDate time.Time, group, name string) (*entity.Sector, error) {
+	overview, err := u.sectors(ctx, startDate, endDate, group)
+	if err != nil {
+		return nil, err
+	}
+	for _, sector := range overview.Sectors {
+		if strings.EqualFold(sector.Name, name) {
+			return &sector, nil
+		}
+	}
+	return nil, nil
+}
+
+func (u *marketUseCase) sectors(ctx context.Context, startDate, endDate time.Time, group string) (*entity.SectorOverview, error) {
+	if startDate.IsZero() && endDate.IsZero() {
+		latest, err := u.stockSummaryRepository.FindLatestDate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		startDate, endDate = latest, latest
+	}
+	overview := &entity.SectorOverview{Group: group, StartDate: startDate, EndDate: endDate, Sectors: []entity.Sector{}}
+	if endDate.IsZero() {
+		return overview, nil
+	}
+
+	stocks, err := u.stockRepository.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[string]string, len(stocks))
+	for _, stock := range stocks {
+		if len(stock.Profiles) == 0 {
+			continue
+		}
+		profile := stock.Profiles[0]
+		switch group {
+		case entity.SectorGroupSector:
+			groups[stock.StockCode] = profile.Sector
+		case entity.SectorGroupSubSector:
+			groups[stock.StockCode] = profile.SubSector
+		case entity.SectorGroupIndustry:
+			groups[stock.StockCode] = profile.Industry
+		case entity.SectorGroupSubIndustry:
+			groups[stock.StockCode] = profile.SubIndustry
+		default:
+			return nil, fmt.Errorf("unknown sector group %q", group)
+		}
+	}
+
+	periods, err := u.stockSummaryRepository.FindPeriodSummaries(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int)
+	for _, period := range periods {
+		name := strings.TrimSpace(groups[period.StockCode])
+		if name == "" {
+			continue
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(overview.Sectors)
+			index[name] = i
+			overview.Sectors = append(overview.Sectors, entity.Sector{Name: name})
+		}
+
+		sector := &overview.Sectors[i]
+		sector.MarketCap += period.MarketCap
+		sector.Return += period.Return * period.MarketCap
+		sector.Value += period.Value
+		sector.ForeignNetValue += period.ForeignNetValue
+		switch {
+		case period.Return > 0:
+			sector.Advancers++
+		case period.Return < 0:
+			sector.Decliners++
+		default:
+			sector.Unchanged++
+		}
+		sector.Constituents = append(sector.Constituents, entity.SectorConstituent{StockPeriodSummary: period})
+	}
+
+	for i := range overview.Sectors {
+		sector := &overview.Sectors[i]
+		if sector.MarketCap > 0 {
+			sector.Return /= sector.MarketCap
+		}
+		for j := range sector.Constituents {
+			if sector.MarketCap > 0 {
+				sector.Constituents[j].Weight = sector.Constituents[j].MarketCap / sector.MarketCap
+			}
+		}
+		sort.SliceStable(sector.Constituents, func(a, b int) bool {
+			return sector.Constituents[a].MarketCap > sector.Constituents[b].MarketCap
+		})
+	}
+	sort.SliceStable(overview.Sectors, func(a, b int) bool {
+		return overview.Sectors[a].MarketCap > overview.Sectors[b].MarketCap
+	})
+
+	return overview, nil
+}
+
+// UpdateBreadth computes and stores the breadth of a trading day. It returns the
+// number of breadths stored, 0 when no summary is stored for the day.
+func (u *marketUseCase) UpdateBreadth(ctx context.Context, date time.Time) (int, error) {
+	date = truncateDate(date)
+	summaries, err := u.stockSummaryRepository.Find(ctx, "", date.Format("2006-01-02"), date.Format("2006-01-02"))
+	if err != nil {
+		return 0, err
+	}
+	if len(summaries) == 0 {
+		return 0, nil
+	}
+
+	breadth := entity.MarketBreadth{Date: date, ComputedAt: time.Now()}
+	for _, summary := range summaries {
+		breadth.Value += summary.Value
+		breadth.Volume += summary.Volume
+		breadth.Frequency += summary.Frequency
+		if summary.Previous <= 0 {
+			continue
+		}
+		breadth.Stocks++
+		switch {
+		case summary.Close > summary.Previous:
+			breadth.Advancers++
+		case summary.Close < summary.Previous:
+			breadth.Decliners++
+		default:
+			breadth.Unchanged++
+		}
+	}
+
+	previousYear, err := u.stockSummaryRepository.FindRanges(ctx, date.AddDate(-1, 0, 0), date.AddDate(0, 0, -1))
+	if err != nil {
+		return 0, err
+	}
+	ranges := make(map[string]entity.StockRange, len(previousYear))
+	for _, stockRange := range previousYear {
+		ranges[stockRange.StockCode] = stockRange
+	}
+	for _, summary := range summaries {
+		stockRange, ok := ranges[summary.StockCode]
+		if !ok {
+			continue // no history to break out of
+		}
+		if summary.High > stockRange.High {
+			breadth.NewHighs++
+		}
+		if summary.Low > 0 && stockRange.Low > 0 && summary.Low < stockRange.Low {
+			breadth.NewLows++
+		}
+	}
+
+	// 200 trading days span about 290 calendar days.
+	dates, err := u.stockSummaryRepository.FindDates(ctx, date.AddDate(0, 0, -400), date)
+	if err != nil {
+		return 0, err
+	}
+	slices.SortFunc(dates, func(a, b time.Time) int { return a.Compare(b) })
+	for period, above := range map[int]*float64{20: &breadth.AboveSMA20, 50: &breadth.AboveSMA50, 200: &breadth.AboveSMA200} {
+		if len(dates) < period {
+			continue
+		}
+		averages, err := u.stockSummaryRepository.FindRanges(ctx, dates[len(dates)-period], date)
+		if err != nil {
+			return 0, err
+		}
+		*above = percentAboveAverage(summaries, averages, period)
+	}
+
+	if err := u.marketBreadthRepository.Upsert(ctx, breadth); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// percentAboveAverage returns the percentage of the stocks with period days of
+// history that closed above their average close.
+func percentAboveAverage(summaries []entity.StockSummary, averages []entity.StockRange, period int) float64 {
+	averageByCode := make(map[string]float64, len(averages))
+	for _, average := range averages {
+		if average.Days == period {
+			averageByCode[average.StockCode] = average.AverageClose
+		}
+	}
+
+	counted, above := 0, 0
+	for _, summary := range summaries {
+		average, ok := averageByCode[summary.StockCode]
+		if !ok {
+			continue
+		}
+		counted++
+		if summary.Close > average {
+			above++
+		}
+	}
+	if counted == 0 {
+		return 0
+	}
+	return float64(above) / float64(counted) * 100
+}
+
+// BackfillBreadth computes the breadth of every stored trading day without one,
+// oldest first, and returns the number of days computed.
+func (u *marketUseCase) BackfillBreadth(ctx context.Context) (int, error) {
+	latest, err := u.stockSummaryRepository.FindLatestDate(ctx)
+	if err != nil || latest.IsZero() {
+		return 0, err
+	}
+
+	dates, err := u.stockSummaryRepository.FindDates(ctx, time.Time{}, latest)
+	if err != nil {
+		return 0, err
+	}
+	slices.SortFunc(dates, func(a, b time.Time) int { return a.Compare(b) })
+
+	breadths, err := u.marketBreadthRepository.Find(ctx, time.Time{}, latest)
+	if err != nil {
+		return 0, err
+	}
+	computed := make(map[time.Time]bool, len(breadths))
+	for _, breadth := range breadths {
+		computed[truncateDate(breadth.Date)] = true
+	}
+
+	total := 0
+	for _, date := range dates {
+		if computed[truncateDate(date)] {
+			continue
+		}
+		records, err := u.UpdateBreadth(ctx, date)
+		if err != nil {
+			return total, fmt.Errorf("breadth of %s: %w", date.Format("2006-01-02"), err)
+		}
+		total += records
+	}
+	return total, nil
+}
+
+// FindBreadth returns the stored breadths of a date range. Without an end date
+// the range ends on the latest stored day, without a start date it covers the
+// 30 days before the end.
+func (u *marketUseCase) FindBreadth(ctx context.Context, startDate, endDate time.Time) ([]entity.MarketBreadth, error) {
+	if endDate.IsZero() {
+		latest, err := u.stockSummaryRepository.FindLatestDate(ctx)
+		if err != nil || latest.IsZero() {
+			return nil, err
+		}
+		endDate = latest
+	}
+	if startDate.IsZero() {
+		startDate = endDate.AddDate(0, 0, -30)
+	}
+	return u.marketBreadthRepository.Find(ctx, startDate, endDate)
+}
+
+// FindPriceLimits rounds a price to the tick size of its band and returns the
+// auto-rejection limits of the next session on the board of the stock, from its
+// latest stored close. It returns nil when the stock is not listed.
+func (u *marketUseCase) FindPriceLimits(ctx context.Context, stockCode string, price float64) (*entity.PriceLimits, error) {
+	stock, err := u.stockRepository.FindOne(ctx, strings.ToUpper(stockCode))
+	if err != nil || stock == nil {
+		return nil, err
+	}
+
+	result := &entity.PriceLimits{
+		StockCode:    stock.StockCode,
+		Board:        stock.Board,
+		Price:        price,
+		TickSize:     marketrules.TickSize(price),
+		Valid:        marketrules.IsValid(price),
+		RoundedPrice: marketrules.Round(price),
+		RoundDown:    marketrules.RoundDown(price),
+		RoundUp:      marketrules.RoundUp(price),
+	}
+
+	latest, err := u.stockSummaryRepository.FindLatest(ctx, stock.StockCode)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil || latest.Close <= 0 {
+		return result, nil
+	}
+
+	limits := marketrules.AutoRejection(stock.Board, latest.Close)
+	result.ReferenceDate = latest.Date
+	result.ReferenceClose = latest.Close
+	result.Upper = limits.Upper
+	result.Lower = limits.Lower
+	result.UpperPercent = limits.UpperPercent
+	result.LowerPercent = limits.LowerPercent
+	return result, nil
+}
+
+// FindAutoRejections returns the summaries of a date range whose high reached the
+// upper auto-rejection limit around their previous close, or whose low reached
+// the lower one, ordered by date and code. Without a stock code every stock is
+// checked; a zero end date is the latest stored day and a zero start date the
+// end date.
+func (u *marketUseCase) FindAutoRejections(ctx context.Context, stockCode string, startDate, endDate time.Time) ([]entity.AutoRejection, error) {
+	if endDate.IsZero() {
+		latest, err := u.stockSummaryRepository.FindLatestDate(ctx)
+		if err != nil || latest.IsZero() {
+			return nil, err
+		}
+		endDate = latest
+	}
+	if startDate.IsZero() {
+		startDate = endDate
+	}
+
+	summaries, err := u.stockSummaryRepository.Find(ctx, strings.ToUpper(stockCode), startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	stocks, err := u.stockRepository.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	boards := make(map[string]string, len(stocks))
+	for _, stock := range stocks {
+		boards[stock.StockCode] = stock.Board
+	}
+
+	result := make([]entity.AutoRejection, 0)
+	for _, summary := range summaries {
+		if summary.Previous <= 0 || summary.Volume == 0 {
+			continue
+		}
+		limits := marketrules.AutoRejection(boards[summary.StockCode], summary.Previous)
+		limit := limits.Hit(summary.High, summary.Low)
+		if limit == "" {
+			continue
+		}
+		result = append(result, entity.AutoRejection{
+			Date:          summary.Date,
+			StockCode:     summary.StockCode,
+			StockName:     summary.StockName,
+			Board:         boards[summary.StockCode],
+			Previous:      summary.Previous,
+			High:          summary.High,
+			Low:           summary.Low,
+			Close:         summary.Close,
+			Upper:         limits.Upper,
+			Lower:         limits.Lower,
+			Limit:         limit,
+			ClosedAtLimit: limit == marketrules.LimitUpper && summary.Close >= limits.Upper || limit == marketrules.LimitLower && summary.Close <= limits.Lower,
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].Date.Equal(result[j].Date) {
+			return result[i].Date.Before(result[j].Date)
+		}
+		return result[i].StockCode < result[j].StockCode
+	})
+	return result, nil
+}
+
+func leaderValue(leader entity.MarketLeader, metric string) float64 {
+	switch metric {
+	case entity.LeaderMetricChangePct:
+		return leader.ChangePct
+	case entity.LeaderMetricForeignNetValue:
+		return leader.ForeignNetValue
+	case entity.LeaderMetricValue:
+		return leader.Value
+	case entity.LeaderMetricVolume:
+		return leader.Volume
+	default:
+		return leader.Frequency
+	}
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
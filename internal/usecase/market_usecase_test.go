@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"context"
+	"go-stock/internal/entity"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFindLeaders(t *testing.T) {
+	cfg := newTestConfig()
+	stocks := newMemoryStockRepository()
+	stocks.BulkUpsert(context.Background(), []entity.Stock{
+		{StockCode: "BBCA", Board: "Utama"},
+		{StockCode: "BBRI", Board: "Utama"},
+		{StockCode: "GOTO", Board: "Utama"},
+		{StockCode: "TLKM", Board: "Pengembangan"},
+	})
+	summaries := newMemoryStockSummaryRepository()
+	if _, err := NewStockSummaryUseCase(newTestIdxClient(cfg), NewCalendarUseCase(cfg, nil, summaries), summaries, stocks).UpdateSummaries(context.Background(), "20250102"); err != nil {
+		t.Fatalf("UpdateSummaries: %v", err)
+	}
+	uc := NewMarketUseCase(summaries, stocks, newMemoryMarketBreadthRepository())
+
+	result, err := uc.FindLeaders(context.Background(), time.Time{}, nil, "", entity.LeaderFilter{}, 10)
+	if err != nil {
+		t.Fatalf("FindLeaders: %v", err)
+	}
+	if !result.Date.Equal(time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC)) || len(result.Leaderboards) != 7 {
+		t.Fatalf("got %d leaderboards on %s, want all 7 on the latest day", len(result.Leaderboards), result.Date)
+	}
+	for _, leaderboard := range result.Leaderboards {
+		for i, leader := range leaderboard.Leaders {
+			switch leaderboard.Name {
+			case entity.LeaderboardGainers:
+				if leader.ChangePct <= 0 || i > 0 && leader.ChangePct > leaderboard.Leaders[i-1].ChangePct {
+					t.Errorf("gainers = %+v", leaderboard.Leaders)
+				}
+			case entity.LeaderboardLosers:
+				if leader.ChangePct >= 0 || i > 0 && leader.ChangePct < leaderboard.Leaders[i-1].ChangePct {
+					t.Errorf("losers = %+v", leaderboard.Leaders)
+				}
+			case entity.LeaderboardMostValue:
+				if i > 0 && leader.Value > leaderboard.Leaders[i-1].Value {
+					t.Errorf("most value = %+v", leaderboard.Leaders)
+				}
+			}
+		}
+	}
+	if leaders := result.Leaderboards[2].Leaders; len(leaders) != 4 {
+		t.Errorf("most value has %d leaders, want every stock", len(leaders))
+	}
+
+	result, err = uc.FindLeaders(context.Background(), time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC), []string{entity.LeaderboardMostValue}, "utama", entity.LeaderFilter{}, 2)
+	if err != nil {
+		t.Fatalf("FindLeaders: %v", err)
+	}
+	if len(result.Leaderboards) != 1 || len(result.Leaderboards[0].Leaders) != 2 {
+		t.Fatalf("result = %+v, want the 2 most traded Utama stocks", result)
+	}
+	for _, leader := range result.Leaderboards[0].Leaders {
+		if leader.StockCode == "TLKM" {
+			t.Errorf("TLKM is not on the Utama board")
+		}
+	}
+
+	result, err = uc.FindLeaders(context.Background(), time.Time{}, nil, "Akselerasi", entity.LeaderFilter{}, 10)
+	if err != nil {
+		t.Fatalf("FindLeaders: %v", err)
+	}
+	for _, leaderboard := range result.Leaderboards {
+		if len(leaderboard.Leaders) != 0 {
+			t.Errorf("%s has leaders on a board without stocks", leaderboard.Name)
+		}
+	}
+}
+
+func TestFindSectors(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, time.January, d, 0, 0, 0, 0, time.UTC) }
+	stocks := newMemoryStockRepository()
+	stocks.BulkUpsert(context.Background(), []entity.Stock{
+		{StockCode: "BBCA", Profiles: []entity.Profile{{Sector: "Keuangan", SubSector: "Bank"}}},
+		{StockCode: "BBRI", Profiles: []entity.Profile{{Sector: "Keuangan", SubSector: "Bank"}}},
+		{StockCode: "TLKM", Profiles: []entity.Profile{{Sector: "Infrastruktur"}}},
+		{StockCode: "GOTO"},
+	})
+	summaries := newMemoryStockSummaryRepository()
+	summaries.BulkUpsert(context.Background(), []entity.StockSummary{
+		// BBCA gains 10% over two days, with a 1:2 split in between that IDX
+		// reflects in the previous close.
+		{StockCode: "BBCA", Date: day(2), Previous: 1000, Close: 1050, ListedShares: 300, Value: 100, Volume: 10, ForeignBuy: 6, ForeignSell: 2, ForeignNetValue: 40},
+		{StockCode: "BBCA", Date: day(3), Previous: 525, Close: 550, ListedShares: 600, Value: 50, Volume: 10},
+		// BBRI loses 20% on a market capitalization of 100000.
+		{StockCode: "BBRI", Date: day(2), Previous: 500, Close: 450, ListedShares: 200, Value: 30, Volume: 5},
+		{StockCode: "BBRI", Date: day(3), Previous: 450, Close: 400, ListedShares: 200, Value: 20, Volume: 5},
+		{StockCode: "TLKM", Date: day(3), Previous: 2000, Close: 2000, ListedShares: 10},
+		{StockCode: "GOTO", Date: day(3), Previous: 50, Close: 60, ListedShares: 1000},
+	})
+	uc := NewMarketUseCase(summaries, stocks, newMemoryMarketBreadthRepository())
+
+	overview, err := uc.FindSectors(context.Background(), day(2), day(3), entity.SectorGroupSector)
+	if err != nil {
+		t.Fatalf("FindSectors: %v", err)
+	}
+	if len(overview.Sectors) != 2 || overview.Sectors[0].Name != "Keuangan" || overview.Sectors[1].Name != "Infrastruktur" {
+		t.Fatalf("sectors = %+v, want Keuangan then Infrastruktur by market cap", overview.Sectors)
+	}
+
+	finance := overview.Sectors[0]
+	// (300000 * 10% + 100000 * -20%) / 400000
+	if math.Abs(finance.Return-0.025) > 1e-9 || finance.MarketCap != 400000 || finance.Value != 200 {
+		t.Errorf("Keuangan = %+v", finance)
+	}
+	if math.Abs(finance.ForeignNetValue-40) > 1e-9 || finance.Advancers != 1 || finance.Decliners != 1 || finance.Constituents != nil {
+		t.Errorf("Keuangan = %+v", finance)
+	}
+
+	sector, err := uc.FindSector(context.Background(), day(2), day(3), entity.SectorGroupSubSector, "bank")
+	if err != nil {
+		t.Fatalf("FindSector: %v", err)
+	}
+	if sector == nil || len(sector.Constituents) != 2 || sector.Constituents[0].StockCode != "BBCA" || sector.Constituents[0].Weight != 0.75 {
+		t.Fatalf("Bank = %+v", sector)
+	}
+
+	// Without dates the latest day is aggregated: BBCA adds 15000 to its market
+	// cap of 315000, BBRI loses 10000 of 90000.
+	overview, err = uc.FindSectors(context.Background(), time.Time{}, time.Time{}, entity.SectorGroupSector)
+	if err != nil {
+		t.Fatalf("FindSectors: %v", err)
+	}
+	if !overview.StartDate.Equal(day(3)) || math.Abs(overview.Sectors[0].Return-5000.0/405000) > 1e-9 {
+		t.Errorf("latest overview = %+v", overview)
+	}
+
+	sector, err = uc.FindSector(context.Background(), day(2), day(3), entity.SectorGroupSector, "Teknologi")
+	if err != nil || sector != nil {
+		t.Errorf("got %+v, %v; want no sector for a stock without a profile", sector, err)
+	}
+}
+
+func TestUpdateBreadth(t *testing.T) {
+	// 21 trading days: AAAA rises by 1 a day, BBBB falls by 1 and CCCC stays flat.
+	summaries := newMemoryStockSummaryRepository()
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	var days []entity.StockSummary
+	for i := range 21 {
+		date := start.AddDate(0, 0, i)
+		for code, price := range map[string]float64{"AAAA": 100 + float64(i), "BBBB": 200 - float64(i), "CCCC": 50} {
+			previous := price
+			switch code {
+			case "AAAA":
+				previous = price - 1
+			case "BBBB":
+				previous = price + 1
+			}
+			if i == 0 {
+				previous = 0
+			}
+			days = append(days, entity.StockSummary{
+				StockCode: code, Date: date, Previous: previous,
+				High: price, Low: price, Close: price, Volume: 10, Value: 10 * price, Frequency: 1,
+			})
+		}
+	}
+	summaries.BulkUpsert(context.Background(), days)
+
+	breadths := newMemoryMarketBreadthRepository()
+	uc := NewMarketUseCase(summaries, newMemoryStockRepository(), breadths)
+
+	last := start.AddDate(0, 0, 20)
+	if records, err := uc.UpdateBreadth(context.Background(), last); err != nil || records != 1 {
+		t.Fatalf("UpdateBreadth = %d, %v", records, err)
+	}
+	result, err := uc.FindBreadth(context.Background(), time.Time{}, time.Time{})
+	if err != nil || len(result) != 1 {
+		t.Fatalf("FindBreadth = %+v, %v", result, err)
+	}
+	breadth := result[0]
+	if breadth.Stocks != 3 || breadth.Advancers != 1 || breadth.Decliners != 1 || breadth.Unchanged != 1 {
+		t.Errorf("advance/decline = %+v", breadth)
+	}
+	if breadth.NewHighs != 1 || breadth.NewLows != 1 {
+		t.Errorf("new highs/lows = %d/%d, want 1/1", breadth.NewHighs, breadth.NewLows)
+	}
+	// Only AAAA closed above its average; 50 and 200 days are not stored yet.
+	if math.Abs(breadth.AboveSMA20-100.0/3) > 1e-9 || breadth.AboveSMA50 != 0 || breadth.AboveSMA200 != 0 {
+		t.Errorf("above averages = %v/%v/%v", breadth.AboveSMA20, breadth.AboveSMA50, breadth.AboveSMA200)
+	}
+	if breadth.Value != 10*(120+180+50) || breadth.Volume != 30 || breadth.Frequency != 3 {
+		t.Errorf("turnover = %v/%v/%v", breadth.Value, breadth.Volume, breadth.Frequency)
+	}
+
+	if records, err := uc.BackfillBreadth(context.Background()); err != nil || records != 20 {
+		t.Fatalf("BackfillBreadth = %d, %v, want the 20 missing days", records, err)
+	}
+	result, _ = uc.FindBreadth(context.Background(), start, last)
+	if len(result) != 21 || result[0].Stocks != 0 || result[0].Value != 10*(100+200+50) {
+		t.Errorf("first day breadth = %+v, want turnover without previous closes", result[0])
+	}
+	if records, _ := uc.UpdateBreadth(context.Background(), last.AddDate(0, 0, 1)); records != 0 {
+		t.Errorf("UpdateBreadth of a day without summaries = %d", records)
+	}
+}
+
+func TestSummariesHook(t *testing.T) {
+	cfg := newTestConfig()
+	summaries := newMemoryStockSummaryRepository()
+	uc := NewStockSummaryUseCase(newTestIdxClient(cfg), NewCalendarUseCase(cfg, nil, summaries), summaries, newMemoryStockRepository())
+
+	var dates []time.Time
+	uc.OnSummariesUpdated(func(ctx context.Context, date time.Time) error {
+		dates = append(dates, date)
+		return nil
+	})
+	if _, err := uc.UpdateSummaries(context.Background(), "20250102"); err != nil {
+		t.Fatalf("UpdateSummaries: %v", err)
+	}
+	if len(dates) != 1 || dates[0].Format("2006-01-02") != "2025-01-02" {
+		t.Errorf("hook ran for %v, want 2025-01-02 once", dates)
+	}
+}
+
+func TestAutoRejections(t *testing.T) {
+	stocks := newMemoryStockRepository()
+	stocks.BulkUpsert(context.Background(), []entity.Stock{
+		{StockCode: "AAAA", Board: "Utama"},
+		{StockCode: "BBBB", Board: "Akselerasi"},
+		{StockCode: "CCCC", Board: "Utama"},
+	})
+	date := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	summaries := newMemoryStockSummaryRepository()
+	summaries.BulkUpsert(context.Background(), []entity.StockSummary{
+		// 25% above 1000 is 1250, locked at the limit.
+		{StockCode: "AAAA", Date: date, Previous: 1000, High: 1250, Low: 1100, Close: 1250, Volume: 100},
+		// 10% below 100 on the acceleration board, rebounding from it.
+		{StockCode: "BBBB", Date: date, Previous: 100, High: 95, Low: 90, Close: 93, Volume: 100},
+		{StockCode: "CCCC", Date: date, Previous: 1000, High: 1200, Low: 900, Close: 1100, Volume: 100},
+	})
+	uc := NewMarketUseCase(summaries, stocks, newMemoryMarketBreadthRepository())
+
+	rejections, err := uc.FindAutoRejections(context.Background(), "", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("FindAutoRejections: %v", err)
+	}
+	if len(rejections) != 2 {
+		t.Fatalf("rejections = %+v, want AAAA and BBBB", rejections)
+	}
+	if r := rejections[0]; r.StockCode != "AAAA" || r.Limit != "ara" || !r.ClosedAtLimit || r.Upper != 1250 {
+		t.Errorf("AAAA = %+v", r)
+	}
+	if r := rejections[1]; r.StockCode != "BBBB" || r.Limit != "arb" || r.ClosedAtLimit || r.Lower != 90 {
+		t.Errorf("BBBB = %+v", r)
+	}
+
+	limits, err := uc.FindPriceLimits(context.Background(), "aaaa", 1253)
+	if err != nil || limits == nil {
+		t.Fatalf("FindPriceLimits = %+v, %v", limits, err)
+	}
+	if limits.Valid || limits.RoundedPrice != 1255 || limits.TickSize != 5 {
+		t.Errorf("price = %+v", limits)
+	}
+	// From the close of 1250: 25% up, 15% down.
+	if !limits.ReferenceDate.Equal(date) || limits.Upper != 1560 || limits.Lower != 1065 {
+		t.Errorf("limits = %v - %v from %v", limits.Lower, limits.Upper, limits.ReferenceDate)
+	}
+	if missing, err := uc.FindPriceLimits(context.Background(), "ZZZZ", 100); err != nil || missing != nil {
+		t.Errorf("FindPriceLimits(ZZZZ) = %+v, %v, want nil", missing, err)
+	}
+}
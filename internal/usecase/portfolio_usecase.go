@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-stock/internal/entity"
+	"go-stock/internal/portfolio"
+	"go-stock/internal/repository"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrInvalidTrade = errors.New("invalid trade")
+
+type PortfolioUseCase interface {
+	RecordTrade(ctx context.Context, trade entity.Trade) (*entity.Trade, error)
+	DeleteTrade(ctx context.Context, portfolioName, id string) (bool, error)
+	FindTrades(ctx context.Context, portfolioName string) ([]entity.Trade, error)
+	FindPortfolio(ctx context.Context, portfolioName string, date time.Time) (*entity.Portfolio, error)
+	FindValues(ctx context.Context, portfolioName string, startDate, endDate time.Time) ([]entity.PortfolioValue, error)
+}
+
+type portfolioUseCase struct {
+	tradeRepository        repository.TradeRepository
+	stockSummaryRepository repository.StockSummaryRepository
+	stockRepository        repository.StockRepository
+}
+
+func NewPortfolioUseCase(tradeRepository repository.TradeRepository, stockSummaryRepository repository.StockSummaryRepository, stockRepository repository.StockRepository) PortfolioUseCase {
+	return &portfolioUseCase{
+		tradeRepository:        tradeRepository,
+		stockSummaryRepository: stockSummaryRepository,
+		stockRepository:        stockRepository,
+	}
+}
+
+// RecordTrade stores a trade of a listed stock. A sale must not exceed the shares
+// held on its date, taking later sales into account too.
+func (u *portfolioUseCase) RecordTrade(ctx context.Context, trade entity.Trade) (*entity.Trade, error) {
+	trade.StockCode = strings.ToUpper(strings.TrimSpace(trade.StockCode))
+	trade.Date = truncateDate(trade.Date)
+	stock, err := u.stockRepository.FindOne(ctx, trade.StockCode)
+	if err != nil {
+		return nil, err
+	}
+	if stock == nil {
+		return nil, fmt.Errorf("%w: unknown stock %s", ErrInvalidTrade, trade.StockCode)
+	}
+
+	trades, err := u.tradeRepository.Find(ctx, trade.Portfolio)
+	if err != nil {
+		return nil, err
+	}
+	trade.ID = newID()
+	trade.CreatedAt = time.Now()
+	if err := u.check(ctx, insertTrade(trades, trade)); err != nil {
+		return nil, err
+	}
+
+	if err := u.tradeRepository.Insert(ctx, trade); err != nil {
+		return nil, err
+	}
+	return &trade, nil
+}
+
+// DeleteTrade deletes a trade and reports whether it existed. A buy cannot be
+// deleted while later sales depend on it.
+func (u *portfolioUseCase) DeleteTrade(ctx context.Context, portfolioName, id string) (bool, error) {
+	trades, err := u.tradeRepository.Find(ctx, portfolioName)
+	if err != nil {
+		return false, err
+	}
+
+	remaining := make([]entity.Trade, 0, len(trades))
+	for _, trade := range trades {
+		if trade.ID != id {
+			remaining = append(remaining, trade)
+		}
+	}
+	if len(remaining) == len(trades) {
+		return false, nil
+	}
+	if err := u.check(ctx, remaining); err != nil {
+		return false, err
+	}
+
+	if err := u.tradeRepository.Delete(ctx, portfolioName, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (u *portfolioUseCase) FindTrades(ctx context.Context, portfolioName string) ([]entity.Trade, error) {
+	return u.tradeRepository.Find(ctx, portfolioName)
+}
+
+// FindPortfolio returns the positions of a portfolio at the close of date, the
+// latest stored day by default. It returns nil when nothing was traded by then.
+func (u *portfolioUseCase) FindPortfolio(ctx context.Context, portfolioName string, date time.Time) (*entity.Portfolio, error) {
+	if date.IsZero() {
+		latest, err := u.stockSummaryRepository.FindLatestDate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		date = latest
+	}
+
+	result, err := u.run(ctx, portfolioName, date, date)
+	if err != nil || result == nil || len(result.Positions) == 0 {
+		return nil, err
+	}
+
+	summary := &entity.Portfolio{
+		Name:      portfolioName,
+		Date:      date,
+		Positions: result.Positions,
+		Dividends: result.Dividends,
+	}
+	for _, position := range result.Positions {
+		summary.MarketValue += position.MarketValue
+		summary.Cost += position.Cost
+		summary.UnrealizedPnL += position.UnrealizedPnL
+		summary.RealizedPnL += position.RealizedPnL
+		summary.DividendIncome += position.DividendIncome
+	}
+	return summary, nil
+}
+
+// FindValues returns the value of a portfolio at the close of every trading day
+// of a range. Without an end date the range ends on the latest stored day,
+// without a start date it starts with the first trade.
+func (u *portfolioUseCase) FindValues(ctx context.Context, portfolioName string, startDate, endDate time.Time) ([]entity.PortfolioValue, error) {
+	if endDate.IsZero() {
+		latest, err := u.stockSummaryRepository.FindLatestDate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		endDate = latest
+	}
+
+	result, err := u.run(ctx, portfolioName, startDate, endDate)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	return result.Values, nil
+}
+
+// run replays the trades of a portfolio with the corporate actions and closes of
+// the traded stocks up to endDate. It returns nil when there are no trades.
+func (u *portfolioUseCase) run(ctx context.Context, portfolioName string, startDate, endDate time.Time) (*portfolio.Result, error) {
+	trades, err := u.tradeRepository.Find(ctx, portfolioName)
+	if err != nil || len(trades) == 0 {
+		return nil, err
+	}
+
+	dividends, err := u.dividends(ctx, trades)
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []entity.StockSummary
+	for code := range dividends {
+		stockSummaries, err := u.stockSummaryRepository.Find(ctx, code, trades[0].Date.Format("2006-01-02"), endDate.Format("2006-01-02"))
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, stockSummaries...)
+	}
+
+	result, err := portfolio.Run(trades, dividends, summaries, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// check replays trades to make sure no sale exceeds the shares held.
+func (u *portfolioUseCase) check(ctx context.Context, trades []entity.Trade) error {
+	dividends, err := u.dividends(ctx, trades)
+	if err != nil {
+		return err
+	}
+
+	_, err = portfolio.Run(trades, dividends, nil, time.Time{}, trades[len(trades)-1].Date)
+	if errors.Is(err, portfolio.ErrOversold) {
+		return fmt.Errorf("%w: %w", ErrInvalidTrade, err)
+	}
+	return err
+}
+
+// dividends returns the dividends of every traded stock, keyed by stock code.
+func (u *portfolioUseCase) dividends(ctx context.Context, trades []entity.Trade) (map[string][]entity.Dividend, error) {
+	dividends := make(map[string][]entity.Dividend)
+	for _, trade := range trades {
+		if _, ok := dividends[trade.StockCode]; ok {
+			continue
+		}
+		stock, err := u.stockRepository.FindOne(ctx, trade.StockCode)
+		if err != nil {
+			return nil, err
+		}
+		dividends[trade.StockCode] = nil
+		if stock != nil {
+			dividends[trade.StockCode] = stock.Dividends
+		}
+	}
+	return dividends, nil
+}
+
+// insertTrade inserts a trade after the trades of the same date or before.
+func insertTrade(trades []entity.Trade, trade entity.Trade) []entity.Trade {
+	i := len(trades)
+	for i > 0 && trades[i-1].Date.After(trade.Date) {
+		i--
+	}
+	return slices.Insert(slices.Clone(trades), i, trade)
+}
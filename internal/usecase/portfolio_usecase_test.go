@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"go-stock/internal/entity"
+	"testing"
+	"time"
+)
+
+func TestPortfolio(t *testing.T) {
+	day := func(n int) time.Time { return time.Date(2025, time.March, n, 0, 0, 0, 0, time.UTC) }
+	stocks := newMemoryStockRepository()
+	stocks.BulkUpsert(context.Background(), []entity.Stock{
+		{StockCode: "AAAA", Dividends: []entity.Dividend{{ExDate: day(5), CashDividendPerShare: 10}}},
+	})
+	summaries := newMemoryStockSummaryRepository()
+	summaries.BulkUpsert(context.Background(), []entity.StockSummary{
+		{Date: day(3), StockCode: "AAAA", Close: 1000},
+		{Date: day(4), StockCode: "AAAA", Close: 1050},
+		{Date: day(5), StockCode: "AAAA", Close: 1100},
+	})
+	uc := NewPortfolioUseCase(newMemoryTradeRepository(), summaries, stocks)
+
+	buy, err := uc.RecordTrade(context.Background(), entity.Trade{Portfolio: "main", StockCode: "aaaa", Date: day(3), Side: entity.TradeSideBuy, Price: 1000, Lots: 2})
+	if err != nil {
+		t.Fatalf("RecordTrade: %v", err)
+	}
+	if _, err := uc.RecordTrade(context.Background(), entity.Trade{Portfolio: "main", StockCode: "AAAA", Date: day(4), Side: entity.TradeSideSell, Price: 1050, Lots: 3}); !errors.Is(err, ErrInvalidTrade) {
+		t.Errorf("overselling = %v, want ErrInvalidTrade", err)
+	}
+	if _, err := uc.RecordTrade(context.Background(), entity.Trade{Portfolio: "main", StockCode: "ZZZZ", Date: day(4), Side: entity.TradeSideBuy, Price: 1, Lots: 1}); !errors.Is(err, ErrInvalidTrade) {
+		t.Errorf("buying an unknown stock = %v, want ErrInvalidTrade", err)
+	}
+	if _, err := uc.RecordTrade(context.Background(), entity.Trade{Portfolio: "main", StockCode: "AAAA", Date: day(4), Side: entity.TradeSideSell, Price: 1050, Lots: 1, Fee: 500}); err != nil {
+		t.Fatalf("RecordTrade: %v", err)
+	}
+
+	result, err := uc.FindPortfolio(context.Background(), "main", time.Time{})
+	if err != nil || result == nil {
+		t.Fatalf("FindPortfolio = %+v, %v", result, err)
+	}
+	if !result.Date.Equal(day(5)) || result.MarketValue != 100*1100 || result.RealizedPnL != 100*50-500 || result.DividendIncome != 100*10 {
+		t.Errorf("portfolio = %+v", result)
+	}
+
+	values, err := uc.FindValues(context.Background(), "main", time.Time{}, time.Time{})
+	if err != nil || len(values) != 3 || values[0].MarketValue != 200*1000 {
+		t.Errorf("FindValues = %+v, %v", values, err)
+	}
+
+	// The sale depends on the shares bought.
+	if _, err := uc.DeleteTrade(context.Background(), "main", buy.ID); !errors.Is(err, ErrInvalidTrade) {
+		t.Errorf("deleting the buy = %v, want ErrInvalidTrade", err)
+	}
+	if result, _ := uc.FindPortfolio(context.Background(), "other", time.Time{}); result != nil {
+		t.Errorf("portfolio without trades = %+v, want nil", result)
+	}
+}
@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-stock/internal/entity"
+	"go-stock/internal/indicator"
+	"go-stock/internal/repository"
+	"go-stock/internal/screener"
+	"slices"
+	"time"
+)
+
+var ErrInvalidScreener = errors.New("invalid screener")
+
+type ScreenerUseCase interface {
+	Screen(ctx context.Context, filter screener.Filter, sorts []screener.Sort, limit, offset int64) (*entity.ScreenerResult, error)
+}
+
+type screenerUseCase struct {
+	stockSummaryRepository repository.StockSummaryRepository
+	stockRepository        repository.StockRepository
+}
+
+func NewScreenerUseCase(stockSummaryRepository repository.StockSummaryRepository, stockRepository repository.StockRepository) ScreenerUseCase {
+	return &screenerUseCase{
+		stockSummaryRepository: stockSummaryRepository,
+		stockRepository:        stockRepository,
+	}
+}
+
+// Screen matches every stock traded on the latest stored date against the filter
+// and returns the requested page of the sorted matches. When the filter or the
+// sort reads indicators, enough history is loaded for them to warm up.
+func (u *screenerUseCase) Screen(ctx context.Context, filter screener.Filter, sorts []screener.Sort, limit, offset int64) (*entity.ScreenerResult, error) {
+	predicate, fields, err := screener.Compile(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidScreener, err)
+	}
+	for _, s := range sorts {
+		field, err := screener.ParseField(s.Field)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidScreener, err)
+		}
+		fields = append(fields, field)
+	}
+
+	var specs []indicator.Spec
+	for _, field := range fields {
+		if field.Indicator != nil && !slices.ContainsFunc(specs, func(spec indicator.Spec) bool {
+			return spec.String() == field.Indicator.String()
+		}) {
+			if lookback := indicator.Lookback(*field.Indicator); lookback > screener.MaxLookback {
+				return nil, fmt.Errorf("%w: %s needs %d days of history, more than the %d screened", ErrInvalidScreener, field.Indicator, lookback, screener.MaxLookback)
+			}
+			specs = append(specs, *field.Indicator)
+		}
+	}
+
+	latest, err := u.stockSummaryRepository.FindLatestDate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &entity.ScreenerResult{Date: latest, Rows: []entity.ScreenerRow{}}
+	if latest.IsZero() {
+		return result, nil
+	}
+
+	start, err := u.historyStart(ctx, latest, indicator.Lookback(specs...))
+	if err != nil {
+		return nil, err
+	}
+	summaries, err := u.stockSummaryRepository.Find(ctx, "", start.Format("2006-01-02"), latest.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+
+	stocks, err := u.stockRepository.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stockByCode := make(map[string]entity.Stock, len(stocks))
+	for _, stock := range stocks {
+		stockByCode[stock.StockCode] = stock
+	}
+
+	var codes []string
+	history := make(map[string][]entity.StockSummary)
+	for _, summary := range summaries {
+		if _, ok := history[summary.StockCode]; !ok {
+			codes = append(codes, summary.StockCode)
+		}
+		history[summary.StockCode] = append(history[summary.StockCode], summary)
+	}
+
+	var rows []screener.Row
+	details := make(map[string]entity.ScreenerRow)
+	for _, code := range codes {
+		stockSummaries := history[code]
+		last := stockSummaries[len(stockSummaries)-1]
+		if !last.Date.Equal(latest) {
+			continue // not traded on the latest date, e.g. delisted
+		}
+
+		row, detail, err := screenerRow(stockByCode[code], stockSummaries, specs)
+		if err != nil {
+			return nil, err
+		}
+		if predicate(row) {
+			rows = append(rows, row)
+			details[code] = detail
+		}
+	}
+
+	if err := screener.SortRows(rows, sorts); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidScreener, err)
+	}
+
+	result.Total = int64(len(rows))
+	// Clamped so a huge or negative page cannot overflow past the rows.
+	first := max(0, min(offset, result.Total))
+	last := first + max(0, min(limit, result.Total-first))
+	for _, row := range rows[first:last] {
+		result.Rows = append(result.Rows, details[row.StockCode])
+	}
+	return result, nil
+}
+
+// historyStart returns the date lookback trading days before latest, or latest
+// itself when no history is needed.
+func (u *screenerUseCase) historyStart(ctx context.Context, latest time.Time, lookback int) (time.Time, error) {
+	if lookback == 0 {
+		return latest, nil
+	}
+
+	// Two calendar days per trading day leave room for weekends and holidays.
+	dates, err := u.stockSummaryRepository.FindDates(ctx, latest.AddDate(0, 0, -2*lookback-14), latest)
+	if err != nil {
+		return time.Time{}, err
+	}
+	slices.SortFunc(dates, func(a, b time.Time) int { return a.Compare(b) })
+	return dates[max(0, len(dates)-1-lookback)], nil
+}
+
+// screenerRow builds the row of a stock from its summaries, ordered by date and
+// ending with the latest one.
+func screenerRow(stock entity.Stock, summaries []entity.StockSummary, specs []indicator.Spec) (screener.Row, entity.ScreenerRow, error) {
+	summary := summaries[len(summaries)-1]
+	summary.DeriveMetrics()
+
+	values := map[string]float64{
+		"previous":          summary.Previous,
+		"open_price":        summary.OpenPrice,
+		"high":              summary.High,
+		"low":               summary.Low,
+		"close":             summary.Close,
+		"change":            summary.Change,
+		"volume":            summary.Volume,
+		"value":             summary.Value,
+		"frequency":         summary.Frequency,
+		"foreign_buy":       summary.ForeignBuy,
+		"foreign_sell":      summary.ForeignSell,
+		"foreign_net":       summary.ForeignBuy - summary.ForeignSell,
+		"foreign_net_value": summary.ForeignNetValue,
+		"listed_shares":     summary.ListedShares,
+		"tradeble_shares":   summary.TradebleShares,
+		"typical_price":     summary.TypicalPrice,
+		"market_cap":        summary.MarketCap,
+	}
+	// Metrics without a base are left out, so no filter on them matches.
+	if summary.Previous > 0 {
+		values["change_pct"] = summary.ChangePct
+	}
+	if summary.Offer > 0 && summary.Bid > 0 {
+		values["spread"] = summary.Spread
+	}
+	if summary.TradebleShares > 0 {
+		values["turnover_ratio"] = summary.TurnoverRatio
+	}
+	if summary.ListedShares > 0 {
+		values["listed_turnover_ratio"] = summary.Volume / summary.ListedShares
+	}
+
+	if len(specs) > 0 {
+		bars := make([]indicator.Bar, 0, len(summaries))
+		for _, summary := range summaries {
+			bars = append(bars, toBar(summary))
+		}
+		for _, spec := range specs {
+			series, err := indicator.Compute(bars, spec)
+			if err != nil {
+				return screener.Row{}, entity.ScreenerRow{}, err
+			}
+			for _, line := range series.Lines {
+				if value := line.Values[len(line.Values)-1]; value != nil {
+					values[spec.String()+"."+line.Name] = *value
+				}
+			}
+		}
+	}
+
+	detail := entity.ScreenerRow{
+		StockCode: summary.StockCode,
+		StockName: summary.StockName,
+		Board:     stock.Board,
+		Values:    values,
+	}
+	texts := map[string]string{
+		"stock_name": summary.StockName,
+		"board":      stock.Board,
+	}
+	if len(stock.Profiles) > 0 {
+		profile := stock.Profiles[0]
+		detail.Sector = profile.Sector
+		detail.SubSector = profile.SubSector
+		texts["sector"] = profile.Sector
+		texts["sub_sector"] = profile.SubSector
+		texts["industry"] = profile.Industry
+		texts["sub_industry"] = profile.SubIndustry
+	}
+
+	return screener.Row{StockCode: summary.StockCode, Numbers: values, Texts: texts}, detail, nil
+}
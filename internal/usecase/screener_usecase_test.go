@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"go-stock/internal/entity"
+	"go-stock/internal/screener"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestScreen(t *testing.T) {
+	stocks := newMemoryStockRepository()
+	stocks.BulkUpsert(context.Background(), []entity.Stock{
+		{StockCode: "BBCA", Board: "Utama", Profiles: []entity.Profile{{Sector: "Keuangan"}}},
+		{StockCode: "BBRI", Board: "Utama", Profiles: []entity.Profile{{Sector: "Keuangan"}}},
+		{StockCode: "GOTO", Board: "Utama", Profiles: []entity.Profile{{Sector: "Teknologi"}}},
+	})
+
+	// 60 trading days: BBCA rises, BBRI falls, GOTO only trades on the last day
+	// and the delisted TLKM stops before it.
+	summaries := newMemoryStockSummaryRepository()
+	start := time.Date(2025, time.January, 6, 0, 0, 0, 0, time.UTC)
+	day := start
+	for i := range 60 {
+		summaries.BulkUpsert(context.Background(), []entity.StockSummary{
+			{StockCode: "BBCA", Date: day, Previous: float64(9000 + 10*i), Close: float64(9010 + 10*i), Volume: 2e6, Value: 20e9, ForeignBuy: 200, ForeignSell: 100, ListedShares: 1e8, TradebleShares: 4e7},
+			{StockCode: "BBRI", Date: day, Previous: float64(5000 - 10*i), Close: float64(4990 - 10*i), Value: 15e9, ForeignBuy: 100, ForeignSell: 200},
+		})
+		if i < 59 {
+			summaries.BulkUpsert(context.Background(), []entity.StockSummary{
+				{StockCode: "TLKM", Date: day, Previous: 2800, Close: 2800, Value: 30e9},
+			})
+		}
+		day = day.AddDate(0, 0, 1)
+		if day.Weekday() == time.Saturday {
+			day = day.AddDate(0, 0, 2)
+		}
+	}
+	latest := day.AddDate(0, 0, -1)
+	if latest.Weekday() == time.Sunday {
+		latest = latest.AddDate(0, 0, -2)
+	}
+	summaries.BulkUpsert(context.Background(), []entity.StockSummary{
+		{StockCode: "GOTO", Date: latest, Previous: 70, Close: 72, Value: 11e9, ForeignBuy: 10},
+	})
+	uc := NewScreenerUseCase(summaries, stocks)
+
+	filter := screener.Filter{All: []screener.Filter{
+		{Field: "close", Op: screener.OpGt, ValueField: "sma:50"},
+		{Field: "value", Op: screener.OpGt, Value: 10e9},
+		{Field: "foreign_net", Op: screener.OpGt, Value: 0.0},
+	}}
+	result, err := uc.Screen(context.Background(), filter, nil, 20, 0)
+	if err != nil {
+		t.Fatalf("Screen: %v", err)
+	}
+	if !result.Date.Equal(latest) || result.Total != 1 || result.Rows[0].StockCode != "BBCA" {
+		t.Fatalf("result = %+v, want only BBCA on %s", result, latest.Format("2006-01-02"))
+	}
+	row := result.Rows[0]
+	if row.Sector != "Keuangan" || row.Values["sma:50.value"] != 9355 || row.Values["foreign_net"] != 100 {
+		t.Errorf("BBCA row = %+v", row)
+	}
+	if row.Values["turnover_ratio"] != 0.05 || row.Values["listed_turnover_ratio"] != 0.02 {
+		t.Errorf("BBCA turnover = %v of the tradable and %v of the listed shares, want 0.05 and 0.02", row.Values["turnover_ratio"], row.Values["listed_turnover_ratio"])
+	}
+
+	// Every stock traded on the latest date, sorted by change and paginated.
+	result, err = uc.Screen(context.Background(), screener.Filter{}, []screener.Sort{{Field: "change_pct", Descending: true}}, 2, 1)
+	if err != nil {
+		t.Fatalf("Screen: %v", err)
+	}
+	if result.Total != 3 || len(result.Rows) != 2 || result.Rows[0].StockCode != "BBCA" || result.Rows[1].StockCode != "BBRI" {
+		t.Errorf("second page = %+v", result)
+	}
+
+	// Offsets past the rows return an empty page instead of panicking.
+	for _, offset := range []int64{3, math.MaxInt64} {
+		result, err = uc.Screen(context.Background(), screener.Filter{}, nil, 20, offset)
+		if err != nil || result.Total != 3 || len(result.Rows) != 0 {
+			t.Errorf("Screen at offset %d = %+v, %v, want no rows of 3", offset, result, err)
+		}
+	}
+
+	_, err = uc.Screen(context.Background(), screener.Filter{Field: "price", Op: screener.OpGt, Value: 1.0}, nil, 20, 0)
+	if !errors.Is(err, ErrInvalidScreener) {
+		t.Errorf("err = %v, want ErrInvalidScreener", err)
+	}
+
+	// Indicators needing more history than the screener loads are rejected.
+	if _, err = uc.Screen(context.Background(), screener.Filter{}, []screener.Sort{{Field: "sma:300"}}, 20, 0); !errors.Is(err, ErrInvalidScreener) {
+		t.Errorf("err = %v for sma:300, want ErrInvalidScreener", err)
+	}
+	if _, err = uc.Screen(context.Background(), screener.Filter{}, []screener.Sort{{Field: "sma:200"}}, 20, 0); err != nil {
+		t.Errorf("Screen sorted by sma:200: %v", err)
+	}
+}
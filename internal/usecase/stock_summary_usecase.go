@@ -2,43 +2,213 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"go-stock/internal/adjustment"
 	"go-stock/internal/entity"
 	"go-stock/internal/infrastructure/idx"
 	"go-stock/internal/repository"
 	"go-stock/internal/shared/helper"
+	"log"
+	"slices"
+	"sort"
+	"time"
 )
 
+// SummariesHook runs after the summaries of a trading day have been stored.
+type SummariesHook func(ctx context.Context, date time.Time) error
+
 type StockSummaryUseCase interface {
-	UpdateSummaries(ctx context.Context, date string) error
-	FindSummaries(ctx context.Context, stockCode string, startDate, endDate string) ([]entity.StockSummary, error)
+	UpdateSummaries(ctx context.Context, date string) (int, error)
+	BackfillSummaries(ctx context.Context, startDate, endDate time.Time) ([]entity.BackfillResult, error)
+	MigrateMetrics(ctx context.Context) (int, error)
+	FindSummaries(ctx context.Context, stockCode string, startDate, endDate, interval string, adjusted bool) ([]entity.StockSummary, error)
+	OnSummariesUpdated(hook SummariesHook)
 }
 
 type stockSummaryUseCase struct {
 	stockSummaryRepository repository.StockSummaryRepository
+	stockRepository        repository.StockRepository
 	idxClient              idx.IdxClient
+	calendarUsecase        CalendarUseCase
+	hooks                  []SummariesHook
 }
 
-func NewStockSummaryUseCase(idxClient idx.IdxClient, stockSummaryRepository repository.StockSummaryRepository) StockSummaryUseCase {
+func NewStockSummaryUseCase(idxClient idx.IdxClient, calendarUsecase CalendarUseCase, stockSummaryRepository repository.StockSummaryRepository, stockRepository repository.StockRepository) StockSummaryUseCase {
 	return &stockSummaryUseCase{
 		stockSummaryRepository: stockSummaryRepository,
+		stockRepository:        stockRepository,
 		idxClient:              idxClient,
+		calendarUsecase:        calendarUsecase,
+	}
+}
+
+// FindSummaries returns the summaries between startDate and endDate (YYYY-MM-DD)
+// rolled up to interval, with their derived metrics. For a weekly, monthly or
+// yearly interval the range is widened to whole periods so the first and last
+// bars are not cut short. Adjusted summaries are back-adjusted for the splits,
+// bonus shares and cash dividends of the stock before they are rolled up.
+func (b *stockSummaryUseCase) FindSummaries(ctx context.Context, code string, startDate, endDate, interval string, adjusted bool) ([]entity.StockSummary, error) {
+	if interval == "" || interval == entity.IntervalDaily {
+		summaries, err := b.stockSummaryRepository.Find(ctx, code, startDate, endDate)
+		if err != nil || !adjusted {
+			return summaries, err
+		}
+		return b.adjustSummaries(ctx, summaries)
+	}
+
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date %q: %w", startDate, err)
+	}
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date %q: %w", endDate, err)
+	}
+	start, err = periodStart(start, interval)
+	if err != nil {
+		return nil, err
+	}
+	end, _ = periodStart(end, interval)
+	end = nextPeriod(end, interval).AddDate(0, 0, -1)
+
+	summaries, err := b.stockSummaryRepository.Find(ctx, code, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	if adjusted {
+		summaries, err = b.adjustSummaries(ctx, summaries)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resampleSummaries(summaries, interval)
+}
+
+// adjustSummaries back-adjusts the summaries of each stock for the corporate
+// actions stored on it that go ex after its first summary. The stocks and the
+// closes before the ex-dates are loaded once per call, not once per action.
+func (b *stockSummaryUseCase) adjustSummaries(ctx context.Context, summaries []entity.StockSummary) ([]entity.StockSummary, error) {
+	var codes []string
+	byCode := make(map[string][]entity.StockSummary)
+	for _, summary := range summaries {
+		if _, ok := byCode[summary.StockCode]; !ok {
+			codes = append(codes, summary.StockCode)
+		}
+		byCode[summary.StockCode] = append(byCode[summary.StockCode], summary)
+	}
+
+	stocks, err := b.findStocks(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	adjusted := make([]entity.StockSummary, 0, len(summaries))
+	for _, code := range codes {
+		stockSummaries := byCode[code]
+		stock, ok := stocks[code]
+		if !ok {
+			adjusted = append(adjusted, stockSummaries...)
+			continue
+		}
+
+		first := stockSummaries[0].Date
+		var dividends []entity.Dividend
+		for _, dividend := range stock.Dividends {
+			if dividend.ExDate.After(first) {
+				dividends = append(dividends, dividend)
+			}
+		}
+
+		sessions, err := b.referenceSessions(ctx, code, stockSummaries, dividends)
+		if err != nil {
+			return nil, err
+		}
+		actions, err := adjustment.Actions(dividends, func(exDate time.Time) (float64, error) {
+			for i := len(sessions) - 1; i >= 0; i-- {
+				if sessions[i].Date.Before(exDate) {
+					return sessions[i].Close, nil
+				}
+			}
+			return 0, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		adjusted = append(adjusted, adjustment.Apply(stockSummaries, actions)...)
+	}
+
+	sort.SliceStable(adjusted, func(i, j int) bool {
+		if !adjusted[i].Date.Equal(adjusted[j].Date) {
+			return adjusted[i].Date.Before(adjusted[j].Date)
+		}
+		return adjusted[i].StockCode < adjusted[j].StockCode
+	})
+	return adjusted, nil
+}
+
+// findStocks returns the listed stocks among codes by code, reading a single
+// stock directly and every stock at once otherwise.
+func (b *stockSummaryUseCase) findStocks(ctx context.Context, codes []string) (map[string]entity.Stock, error) {
+	stocks := make(map[string]entity.Stock, len(codes))
+	if len(codes) == 1 {
+		stock, err := b.stockRepository.FindOne(ctx, codes[0])
+		if err != nil || stock == nil {
+			return stocks, err
+		}
+		stocks[stock.StockCode] = *stock
+		return stocks, nil
+	}
+
+	all, err := b.stockRepository.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, stock := range all {
+		stocks[stock.StockCode] = stock
+	}
+	return stocks, nil
+}
+
+// referenceSessions returns the sessions of a stock up to the last ex-date of
+// its cash dividends, for their closes before the ex-date: the loaded summaries,
+// ordered by date, and the sessions stored after them in a single query.
+func (b *stockSummaryUseCase) referenceSessions(ctx context.Context, code string, summaries []entity.StockSummary, dividends []entity.Dividend) ([]entity.StockSummary, error) {
+	var lastExDate time.Time
+	for _, dividend := range dividends {
+		if dividend.CashDividendPerShare > 0 && dividend.ExDate.After(lastExDate) {
+			lastExDate = dividend.ExDate
+		}
+	}
+
+	from := summaries[len(summaries)-1].Date.AddDate(0, 0, 1)
+	to := truncateDate(lastExDate).AddDate(0, 0, -1)
+	if to.Before(from) {
+		return summaries, nil
+	}
+	later, err := b.stockSummaryRepository.Find(ctx, code, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
 	}
+	return append(slices.Clip(summaries), later...), nil
 }
 
-func (b *stockSummaryUseCase) FindSummaries(ctx context.Context, code string, startDate, endDate string) ([]entity.StockSummary, error) {
-	return b.stockSummaryRepository.Find(ctx, code, startDate, endDate)
+// OnSummariesUpdated registers a hook run by UpdateSummaries once it stored the
+// summaries of a day, e.g. to derive data from them. Hooks run in the order they
+// were registered; a failing hook is logged and does not fail the update.
+func (b *stockSummaryUseCase) OnSummariesUpdated(hook SummariesHook) {
+	b.hooks = append(b.hooks, hook)
 }
 
-func (b *stockSummaryUseCase) UpdateSummaries(ctx context.Context, date string) error {
+func (b *stockSummaryUseCase) UpdateSummaries(ctx context.Context, date string) (int, error) {
 	list, err := b.idxClient.GetStockSummaryList(ctx, date)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	var stockSummaries []entity.StockSummary
 	for _, stockSummary := range list.StockSummaryListData {
-		stockSummaries = append(stockSummaries, entity.StockSummary{
+		summary := entity.StockSummary{
 			IDStockSummary:      stockSummary.IDStockSummary,
 			Date:                helper.StringToDate(stockSummary.Date),
 			StockCode:           stockSummary.StockCode,
@@ -68,18 +238,243 @@ func (b *stockSummaryUseCase) UpdateSummaries(ctx context.Context, date string)
 			NonRegularVolume:    stockSummary.NonRegularVolume,
 			NonRegularValue:     stockSummary.NonRegularValue,
 			NonRegularFrequency: stockSummary.NonRegularFrequency,
-			Persen:              stockSummary.Persen,
-			Percentage:          stockSummary.Percentage,
-		})
+			Persen:              float64(stockSummary.Persen),
+			Percentage:          float64(stockSummary.Percentage),
+		}
+		summary.DeriveMetrics()
+		stockSummaries = append(stockSummaries, summary)
 	}
 
 	if len(stockSummaries) == 0 {
-		return nil // no broker data to update
+		return 0, nil // no broker data to update
 	}
 
 	if err := b.stockSummaryRepository.BulkUpsert(ctx, stockSummaries); err != nil {
-		return fmt.Errorf("bulk upsert failed: %w", err)
+		return 0, fmt.Errorf("bulk upsert failed: %w", err)
+	}
+
+	for _, hook := range b.hooks {
+		if err := hook(ctx, stockSummaries[0].Date); err != nil {
+			log.Printf("⚠️ Summaries hook failed for %s: %v", stockSummaries[0].Date.Format("2006-01-02"), err)
+		}
+	}
+
+	return len(stockSummaries), nil
+}
+
+// BackfillSummaries walks every day between startDate and endDate (inclusive) and
+// ingests the summaries of each trading day that is not stored yet. Weekends and
+// the holidays of the calendar are skipped; weekdays the calendar infers as closed
+// are fetched again, as they may just have been missed. Days are fetched one at a
+// time, so the delay configured on the IDX client applies between them. A failing
+// day does not stop the backfill; its error is recorded in the result.
+func (b *stockSummaryUseCase) BackfillSummaries(ctx context.Context, startDate, endDate time.Time) ([]entity.BackfillResult, error) {
+	start := truncateDate(startDate)
+	end := truncateDate(endDate)
+	if end.Before(start) {
+		return nil, fmt.Errorf("end date %s is before start date %s", end.Format("2006-01-02"), start.Format("2006-01-02"))
+	}
+
+	days, err := b.calendarUsecase.FindDays(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []entity.BackfillResult
+	for _, day := range days {
+		result := entity.BackfillResult{Date: day.Date}
+
+		switch day.Status {
+		case entity.TradingDayStatusWeekend:
+			result.Status = entity.BackfillStatusSkipped
+			result.Reason = "weekend"
+		case entity.TradingDayStatusHoliday:
+			result.Status = entity.BackfillStatusSkipped
+			result.Reason = "holiday: " + day.Holiday
+		case entity.TradingDayStatusSession:
+			result.Status = entity.BackfillStatusSkipped
+			result.Reason = "already present"
+		default:
+			records, err := b.UpdateSummaries(ctx, day.Date.Format("20060102"))
+			switch {
+			case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+				return results, err
+			case err != nil:
+				result.Status = entity.BackfillStatusFailed
+				result.Reason = err.Error()
+			case records == 0:
+				result.Status = entity.BackfillStatusEmpty
+				result.Reason = "no summaries returned"
+			default:
+				result.Status = entity.BackfillStatusSuccess
+				result.Records = records
+			}
+			log.Printf("Backfill stock summaries for %s: %s (%d records)", day.Date.Format("2006-01-02"), result.Status, records)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// MigrateMetrics converts the percentages stored untyped into numbers and fills
+// in the derived metrics of every stored summary, one day at a time. Running it
+// again recomputes them.
+func (b *stockSummaryUseCase) MigrateMetrics(ctx context.Context) (int, error) {
+	if _, err := b.stockSummaryRepository.NormalizePercentages(ctx); err != nil {
+		return 0, err
+	}
+
+	dates, err := b.stockSummaryRepository.FindDates(ctx, time.Time{}, truncateDate(time.Now()).AddDate(0, 0, 1))
+	if err != nil {
+		return 0, err
 	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
 
-	return nil
+	var migrated int
+	for _, date := range dates {
+		day := date.Format("2006-01-02")
+		summaries, err := b.stockSummaryRepository.Find(ctx, "", day, day)
+		if err != nil {
+			return migrated, err
+		}
+		for i := range summaries {
+			summaries[i].DeriveMetrics()
+		}
+		if err := b.stockSummaryRepository.BulkUpsert(ctx, summaries); err != nil {
+			return migrated, fmt.Errorf("migrate summaries of %s: %w", day, err)
+		}
+		migrated += len(summaries)
+		log.Printf("Migrated the metrics of %d summaries for %s", len(summaries), day)
+	}
+
+	return migrated, nil
+}
+
+// truncateDate drops the time of day, matching how summary dates are stored.
+func truncateDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// resampleSummaries rolls daily summaries, ordered by date, up into one summary per
+// stock and period, dated on the first day of the period. Prices run from the first
+// open to the last close, flows are summed and the share counts, bid and offer are
+// taken from the last day.
+func resampleSummaries(summaries []entity.StockSummary, interval string) ([]entity.StockSummary, error) {
+	type key struct {
+		stockCode string
+		period    time.Time
+	}
+
+	var keys []key
+	bars := make(map[key]*entity.StockSummary)
+	for _, summary := range summaries {
+		period, err := periodStart(summary.Date, interval)
+		if err != nil {
+			return nil, err
+		}
+		k := key{stockCode: summary.StockCode, period: period}
+
+		bar, ok := bars[k]
+		if !ok {
+			bar = &entity.StockSummary{
+				Date:       period,
+				StockCode:  summary.StockCode,
+				Previous:   summary.Previous,
+				OpenPrice:  summary.OpenPrice,
+				FirstTrade: summary.FirstTrade,
+				High:       summary.High,
+				Low:        summary.Low,
+			}
+			bars[k] = bar
+			keys = append(keys, k)
+		}
+
+		// A day without trades has no open, high or low.
+		if bar.OpenPrice == 0 {
+			bar.OpenPrice = summary.OpenPrice
+		}
+		if bar.FirstTrade == 0 {
+			bar.FirstTrade = summary.FirstTrade
+		}
+		if summary.High > bar.High {
+			bar.High = summary.High
+		}
+		if summary.Low > 0 && (bar.Low == 0 || summary.Low < bar.Low) {
+			bar.Low = summary.Low
+		}
+
+		bar.StockName = summary.StockName
+		bar.Remarks = summary.Remarks
+		bar.Close = summary.Close
+		bar.Change = summary.Close - bar.Previous
+		bar.Volume += summary.Volume
+		bar.Value += summary.Value
+		bar.Frequency += summary.Frequency
+		bar.IndexIndividual = summary.IndexIndividual
+		bar.Offer = summary.Offer
+		bar.OfferVolume = summary.OfferVolume
+		bar.Bid = summary.Bid
+		bar.BidVolume = summary.BidVolume
+		bar.ListedShares = summary.ListedShares
+		bar.TradebleShares = summary.TradebleShares
+		bar.WeightForIndex = summary.WeightForIndex
+		bar.ForeignSell += summary.ForeignSell
+		bar.ForeignBuy += summary.ForeignBuy
+		bar.DelistingDate = summary.DelistingDate
+		bar.NonRegularVolume += summary.NonRegularVolume
+		bar.NonRegularValue += summary.NonRegularValue
+		bar.NonRegularFrequency += summary.NonRegularFrequency
+	}
+
+	result := make([]entity.StockSummary, 0, len(keys))
+	for _, k := range keys {
+		bar := bars[k]
+		if bar.OpenPrice == 0 {
+			// Not traded during the whole period.
+			bar.OpenPrice = bar.Previous
+		}
+		bar.DeriveMetrics()
+		result = append(result, *bar)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].Date.Equal(result[j].Date) {
+			return result[i].Date.Before(result[j].Date)
+		}
+		return result[i].StockCode < result[j].StockCode
+	})
+	return result, nil
+}
+
+// periodStart returns the first day of the period containing t: the Monday of its
+// week, the first of its month or of its year.
+func periodStart(t time.Time, interval string) (time.Time, error) {
+	day := truncateDate(t)
+	switch interval {
+	case entity.IntervalDaily:
+		return day, nil
+	case entity.IntervalWeekly:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7), nil
+	case entity.IntervalMonthly:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	case entity.IntervalYearly:
+		return time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, time.UTC), nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown interval %q", interval)
+	}
+}
+
+// nextPeriod returns the start of the period after the one starting at start.
+func nextPeriod(start time.Time, interval string) time.Time {
+	switch interval {
+	case entity.IntervalWeekly:
+		return start.AddDate(0, 0, 7)
+	case entity.IntervalMonthly:
+		return start.AddDate(0, 1, 0)
+	case entity.IntervalYearly:
+		return start.AddDate(1, 0, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
 }
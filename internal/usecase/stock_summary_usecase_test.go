@@ -0,0 +1,281 @@
+package usecase
+
+import (
+	"context"
+	"go-stock/internal/entity"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestUpdateSummaries(t *testing.T) {
+	cfg := newTestConfig()
+	summaries := newMemoryStockSummaryRepository()
+	uc := NewStockSummaryUseCase(newTestIdxClient(cfg), NewCalendarUseCase(cfg, nil, summaries), summaries, newMemoryStockRepository())
+
+	records, err := uc.UpdateSummaries(context.Background(), "20250102")
+	if err != nil {
+		t.Fatalf("UpdateSummaries: %v", err)
+	}
+	if records != 4 {
+		t.Errorf("records = %d, want 4", records)
+	}
+
+	result, err := uc.FindSummaries(context.Background(), "BBCA", "2025-01-01", "2025-01-31", "", false)
+	if err != nil {
+		t.Fatalf("FindSummaries: %v", err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("got %d BBCA summaries, want 1", len(result))
+	}
+	summary := result[0]
+	if !summary.Date.Equal(time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("date = %v, want 2025-01-02 UTC midnight", summary.Date)
+	}
+	if summary.Close != 9775 || summary.Change != 100 || summary.ForeignBuy != 6120300 {
+		t.Errorf("BBCA summary = %+v", summary)
+	}
+	if math.Abs(summary.ChangePct-100.0/9675*100) > 1e-9 || summary.Spread != 25 || summary.MarketCap != 123275050000*9775 {
+		t.Errorf("BBCA change %v%%, spread %v, market cap %v", summary.ChangePct, summary.Spread, summary.MarketCap)
+	}
+	if math.Abs(summary.TypicalPrice-(9800+9650+9775)/3.0) > 1e-9 || summary.TurnoverRatio != 18234500.0/103000000000 {
+		t.Errorf("BBCA typical price %v, turnover ratio %v", summary.TypicalPrice, summary.TurnoverRatio)
+	}
+}
+
+func TestMigrateMetrics(t *testing.T) {
+	cfg := newTestConfig()
+	summaries := newMemoryStockSummaryRepository()
+	day := func(d int) time.Time { return time.Date(2025, time.January, d, 0, 0, 0, 0, time.UTC) }
+	summaries.BulkUpsert(context.Background(), []entity.StockSummary{
+		{StockCode: "BBCA", Date: day(2), Previous: 1000, High: 1100, Low: 1000, Close: 1050, Volume: 200, Value: 210000, ForeignBuy: 50, ForeignSell: 20, Offer: 1055, Bid: 1050, ListedShares: 10000, TradebleShares: 4000},
+		// Suspended: no trades, no quotes and no previous close.
+		{StockCode: "GOTO", Date: day(3), Close: 70, ListedShares: 1000},
+	})
+	uc := NewStockSummaryUseCase(newTestIdxClient(cfg), NewCalendarUseCase(cfg, nil, summaries), summaries, newMemoryStockRepository())
+
+	migrated, err := uc.MigrateMetrics(context.Background())
+	if err != nil {
+		t.Fatalf("MigrateMetrics: %v", err)
+	}
+	if migrated != 2 {
+		t.Errorf("migrated = %d, want 2", migrated)
+	}
+
+	result, _ := summaries.Find(context.Background(), "", "2025-01-01", "2025-01-31")
+	bbca, suspended := result[0], result[1]
+	if bbca.ChangePct != 5 || bbca.ForeignNetValue != 30*1050 || bbca.TypicalPrice != 1050 || bbca.Spread != 5 || bbca.TurnoverRatio != 0.05 || bbca.MarketCap != 10500000 {
+		t.Errorf("BBCA metrics = %+v", bbca)
+	}
+	if suspended.ChangePct != 0 || suspended.ForeignNetValue != 0 || suspended.TypicalPrice != 70 || suspended.Spread != 0 || suspended.TurnoverRatio != 0 || suspended.MarketCap != 70000 {
+		t.Errorf("GOTO metrics = %+v", suspended)
+	}
+}
+
+func TestUpdateSummariesHoliday(t *testing.T) {
+	cfg := newTestConfig()
+	summaries := newMemoryStockSummaryRepository()
+	uc := NewStockSummaryUseCase(newTestIdxClient(cfg), NewCalendarUseCase(cfg, nil, summaries), summaries, newMemoryStockRepository())
+
+	records, err := uc.UpdateSummaries(context.Background(), "20250101")
+	if err != nil || records != 0 {
+		t.Fatalf("got %d records, %v; want none on a holiday", records, err)
+	}
+}
+
+func TestBackfillSummaries(t *testing.T) {
+	cfg := newTestConfig()
+	summaries := newMemoryStockSummaryRepository()
+	summaries.BulkUpsert(context.Background(), []entity.StockSummary{
+		{StockCode: "BBCA", Date: time.Date(2025, time.January, 3, 0, 0, 0, 0, time.UTC)},
+	})
+	uc := NewStockSummaryUseCase(newTestIdxClient(cfg), NewCalendarUseCase(cfg, nil, summaries), summaries, newMemoryStockRepository())
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.January, 5, 15, 30, 0, 0, time.UTC)
+	results, err := uc.BackfillSummaries(context.Background(), start, end)
+	if err != nil {
+		t.Fatalf("BackfillSummaries: %v", err)
+	}
+
+	want := []struct {
+		status  entity.BackfillStatus
+		records int
+		reason  string
+	}{
+		{entity.BackfillStatusEmpty, 0, "no summaries returned"},
+		{entity.BackfillStatusSuccess, 4, ""},
+		{entity.BackfillStatusSkipped, 0, "already present"},
+		{entity.BackfillStatusSkipped, 0, "weekend"},
+		{entity.BackfillStatusSkipped, 0, "weekend"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(results), len(want), results)
+	}
+	for i, result := range results {
+		day := start.AddDate(0, 0, i)
+		if !result.Date.Equal(day) || result.Status != want[i].status || result.Records != want[i].records || result.Reason != want[i].reason {
+			t.Errorf("result %d = %+v, want %v %+v", i, result, day.Format("2006-01-02"), want[i])
+		}
+	}
+}
+
+func TestBackfillSummariesHolidays(t *testing.T) {
+	cfg := newTestConfig()
+	summaries := newMemoryStockSummaryRepository()
+	holidays := []entity.Holiday{{Date: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), Name: "Tahun Baru 2025 Masehi"}}
+	uc := NewStockSummaryUseCase(newTestIdxClient(cfg), NewCalendarUseCase(cfg, holidays, summaries), summaries, newMemoryStockRepository())
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	results, err := uc.BackfillSummaries(context.Background(), start, start.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("BackfillSummaries: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(results), results)
+	}
+	if results[0].Status != entity.BackfillStatusSkipped || results[0].Reason != "holiday: Tahun Baru 2025 Masehi" {
+		t.Errorf("holiday result = %+v, want skipped", results[0])
+	}
+	if results[1].Status != entity.BackfillStatusSuccess || results[1].Records != 4 {
+		t.Errorf("trading day result = %+v, want 4 records", results[1])
+	}
+}
+
+func TestBackfillSummariesInvalidRange(t *testing.T) {
+	cfg := newTestConfig()
+	summaries := newMemoryStockSummaryRepository()
+	uc := NewStockSummaryUseCase(newTestIdxClient(cfg), NewCalendarUseCase(cfg, nil, summaries), summaries, newMemoryStockRepository())
+
+	start := time.Date(2025, time.January, 3, 0, 0, 0, 0, time.UTC)
+	if _, err := uc.BackfillSummaries(context.Background(), start, start.AddDate(0, 0, -1)); err == nil {
+		t.Fatal("want an error when the end date is before the start date")
+	}
+}
+
+func TestFindSummariesWeekly(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, time.January, d, 0, 0, 0, 0, time.UTC) }
+	summaries := newMemoryStockSummaryRepository()
+	summaries.BulkUpsert(context.Background(), []entity.StockSummary{
+		// Week of Monday 30 December 2024; the first of January is a holiday.
+		{StockCode: "BBCA", Date: day(2), Previous: 9675, OpenPrice: 9700, High: 9800, Low: 9650, Close: 9775, Volume: 10, Value: 100, Frequency: 1, ForeignBuy: 5, ForeignSell: 3},
+		{StockCode: "BBCA", Date: day(3), Previous: 9775, OpenPrice: 9775, High: 9900, Low: 9700, Close: 9850, Volume: 20, Value: 200, Frequency: 2, ForeignBuy: 7, ForeignSell: 1, ListedShares: 123},
+		// Week of 6 January, suspended on Monday.
+		{StockCode: "BBCA", Date: day(6), Previous: 9850, Close: 9850},
+		{StockCode: "BBCA", Date: day(7), Previous: 9850, OpenPrice: 9825, High: 9875, Low: 9600, Close: 9625, Volume: 5, Value: 50, Frequency: 1},
+		{StockCode: "BBRI", Date: day(7), Previous: 4000, OpenPrice: 4010, High: 4050, Low: 3990, Close: 4020, Volume: 1},
+	})
+	cfg := newTestConfig()
+	uc := NewStockSummaryUseCase(newTestIdxClient(cfg), NewCalendarUseCase(cfg, nil, summaries), summaries, newMemoryStockRepository())
+
+	// The range is widened to whole weeks, so 6 January is included.
+	result, err := uc.FindSummaries(context.Background(), "BBCA", "2025-01-03", "2025-01-07", entity.IntervalWeekly, false)
+	if err != nil {
+		t.Fatalf("FindSummaries: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("got %d weekly bars, want 2", len(result))
+	}
+
+	first, second := result[0], result[1]
+	if !first.Date.Equal(time.Date(2024, time.December, 30, 0, 0, 0, 0, time.UTC)) || !second.Date.Equal(day(6)) {
+		t.Errorf("dates = %v, %v; want the Mondays", first.Date, second.Date)
+	}
+	if first.Previous != 9675 || first.OpenPrice != 9700 || first.High != 9900 || first.Low != 9650 || first.Close != 9850 || first.Change != 175 {
+		t.Errorf("first week prices = %+v", first)
+	}
+	if first.Volume != 30 || first.Value != 300 || first.Frequency != 3 || first.ForeignBuy != 12 || first.ForeignSell != 4 || first.ListedShares != 123 {
+		t.Errorf("first week totals = %+v", first)
+	}
+	if second.OpenPrice != 9825 || second.High != 9875 || second.Low != 9600 || second.Close != 9625 || second.Volume != 5 {
+		t.Errorf("second week = %+v, want the suspended day ignored for open, high and low", second)
+	}
+
+	result, err = uc.FindSummaries(context.Background(), "", "2025-01-01", "2025-01-31", entity.IntervalMonthly, false)
+	if err != nil {
+		t.Fatalf("FindSummaries: %v", err)
+	}
+	if len(result) != 2 || result[0].StockCode != "BBCA" || result[1].StockCode != "BBRI" || result[0].Volume != 35 {
+		t.Errorf("monthly bars = %+v", result)
+	}
+}
+
+func TestFindSummariesAdjusted(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2021, time.October, d, 0, 0, 0, 0, time.UTC) }
+	stocks := newMemoryStockRepository()
+	stocks.BulkUpsert(context.Background(), []entity.Stock{{
+		StockCode: "BBCA",
+		Dividends: []entity.Dividend{
+			{Type: "dt", ExDate: day(4), CashDividendPerShare: 500},
+			{Type: "sp", ExDate: day(12), Ratio1: 1, Ratio2: 5},
+			{Type: "dt", ExDate: day(14), CashDividendPerShare: 70},
+		},
+	}})
+	summaries := newMemoryStockSummaryRepository()
+	summaries.BulkUpsert(context.Background(), []entity.StockSummary{
+		{StockCode: "BBCA", Date: day(8), OpenPrice: 34000, High: 34500, Low: 33500, Close: 34000, Volume: 100},
+		{StockCode: "BBCA", Date: day(11), OpenPrice: 34000, High: 35500, Low: 34000, Close: 35000, Volume: 100},
+		{StockCode: "BBCA", Date: day(12), OpenPrice: 7000, High: 7100, Low: 6900, Close: 7000, Volume: 500},
+		{StockCode: "BBCA", Date: day(13), OpenPrice: 7000, High: 7050, Low: 6950, Close: 7000, Volume: 500},
+		{StockCode: "BBCA", Date: day(14), OpenPrice: 6930, High: 6950, Low: 6900, Close: 6930, Volume: 500},
+	})
+	cfg := newTestConfig()
+	uc := NewStockSummaryUseCase(newTestIdxClient(cfg), NewCalendarUseCase(cfg, nil, summaries), summaries, stocks)
+
+	result, err := uc.FindSummaries(context.Background(), "BBCA", "2021-10-08", "2021-10-14", "", true)
+	if err != nil {
+		t.Fatalf("FindSummaries: %v", err)
+	}
+
+	// The split divides the prices before 12 October by 5, the dividend of 70 on a
+	// close of 7000 takes 1% off everything before 14 October. The dividend that
+	// went ex before the range is ignored.
+	want := []float64{6732, 6930, 6930, 6930, 6930}
+	for i, summary := range result {
+		if math.Abs(summary.Close-want[i]) > 1e-9 {
+			t.Errorf("close on %s = %v, want %v", summary.Date.Format("2006-01-02"), summary.Close, want[i])
+		}
+	}
+	if result[0].Volume != 500 || result[2].Volume != 500 {
+		t.Errorf("volumes = %v, %v; want 500 shares after the split", result[0].Volume, result[2].Volume)
+	}
+
+	weekly, err := uc.FindSummaries(context.Background(), "BBCA", "2021-10-08", "2021-10-14", entity.IntervalWeekly, true)
+	if err != nil {
+		t.Fatalf("FindSummaries: %v", err)
+	}
+	if len(weekly) != 2 || math.Abs(weekly[1].High-7029) > 1e-9 || weekly[1].Volume != 2000 {
+		t.Errorf("adjusted weekly bars = %+v", weekly)
+	}
+	// A range ending before the dividend reads its reference close of 13 October
+	// with one more query, and looks up nothing per action.
+	counting := &countingStockSummaryRepository{memoryStockSummaryRepository: summaries}
+	uc = NewStockSummaryUseCase(newTestIdxClient(cfg), NewCalendarUseCase(cfg, nil, summaries), counting, stocks)
+	result, err = uc.FindSummaries(context.Background(), "BBCA", "2021-10-08", "2021-10-11", "", true)
+	if err != nil {
+		t.Fatalf("FindSummaries: %v", err)
+	}
+	if len(result) != 2 || math.Abs(result[0].Close-6732) > 1e-9 || math.Abs(result[1].Close-6930) > 1e-9 {
+		t.Errorf("adjusted summaries = %+v", result)
+	}
+	if counting.finds != 2 || counting.findBefores != 0 {
+		t.Errorf("got %d Find and %d FindBefore queries, want 2 and none", counting.finds, counting.findBefores)
+	}
+}
+
+// countingStockSummaryRepository counts the queries of the summaries.
+type countingStockSummaryRepository struct {
+	*memoryStockSummaryRepository
+	finds       int
+	findBefores int
+}
+
+func (r *countingStockSummaryRepository) Find(ctx context.Context, code string, startDate, endDate string) ([]entity.StockSummary, error) {
+	r.finds++
+	return r.memoryStockSummaryRepository.Find(ctx, code, startDate, endDate)
+}
+
+func (r *countingStockSummaryRepository) FindBefore(ctx context.Context, code string, before time.Time, limit int64) ([]entity.StockSummary, error) {
+	r.findBefores++
+	return r.memoryStockSummaryRepository.FindBefore(ctx, code, before, limit)
+}
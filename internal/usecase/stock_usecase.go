@@ -2,182 +2,324 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go-stock/internal/config"
 	"go-stock/internal/entity"
 	"go-stock/internal/infrastructure/idx"
 	"go-stock/internal/repository"
 	"go-stock/internal/shared/helper"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	updateStockCheckpoint = "update_stock"
+	// updateStockCheckpointTTL bounds how long an unfinished run can be resumed;
+	// older checkpoints are dropped so profiles do not go stale.
+	updateStockCheckpointTTL = 24 * time.Hour
 )
 
 type StockUseCase interface {
-	UpdateStock(ctx context.Context) error
+	UpdateStock(ctx context.Context) (int, error)
 	ListStocks(ctx context.Context) ([]entity.Stock, error)
 	FindStock(ctx context.Context, code string) (*entity.Stock, error)
 	ListStocksWithPagination(ctx context.Context, limit, offset int64) ([]entity.Stock, int64, error)
 	SearchStocks(ctx context.Context, query string) ([]entity.Stock, error)
 }
 type stockUseCase struct {
-	stockRepository repository.StockRepository
-	idxClient       idx.IdxClient
-	cfg             config.Config
+	stockRepository      repository.StockRepository
+	checkpointRepository repository.CheckpointRepository
+	idxClient            idx.IdxClient
+	cfg                  config.Config
 }
 
-func NewStockUsecase(cfg config.Config, idxClient idx.IdxClient, stockRepository repository.StockRepository) StockUseCase {
+func NewStockUsecase(cfg config.Config, idxClient idx.IdxClient, stockRepository repository.StockRepository, checkpointRepository repository.CheckpointRepository) StockUseCase {
 	return &stockUseCase{
-		stockRepository: stockRepository,
-		idxClient:       idxClient,
-		cfg:             cfg,
+		stockRepository:      stockRepository,
+		checkpointRepository: checkpointRepository,
+		idxClient:            idxClient,
+		cfg:                  cfg,
 	}
 }
 
-func (s *stockUseCase) UpdateStock(ctx context.Context) error {
+// UpdateStock refreshes every listed stock together with its company profile.
+// Profiles are fetched by a pool of workers and upserted in batches; the codes of
+// each stored batch are checkpointed so a restarted run only fetches the rest.
+// Failed profiles do not stop the run, they are reported together at the end.
+func (s *stockUseCase) UpdateStock(ctx context.Context) (int, error) {
 	list, err := s.idxClient.GetStockList(ctx)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	var stocks []entity.Stock
+	done := make(map[string]bool)
+	checkpoint, err := s.checkpointRepository.Find(ctx, updateStockCheckpoint)
+	if err != nil {
+		return 0, err
+	}
+	if checkpoint != nil && time.Since(checkpoint.StartedAt) < updateStockCheckpointTTL {
+		for _, code := range checkpoint.Done {
+			done[code] = true
+		}
+		log.Printf("Resuming stock update, %d of %d profiles already stored", len(done), len(list.StockListData))
+	} else if checkpoint != nil {
+		if err := s.checkpointRepository.Delete(ctx, updateStockCheckpoint); err != nil {
+			return 0, err
+		}
+	}
 
+	pending := make([]idx.StockListData, 0, len(list.StockListData))
 	for _, stock := range list.StockListData {
-		company, err := s.idxClient.GetCompanyProfile(ctx, stock.Code)
-		if err != nil {
-			return fmt.Errorf("invalid stock company %s: %w", stock.Code, err)
+		if !done[stock.Code] {
+			pending = append(pending, stock)
 		}
+	}
 
-		profiles := make([]entity.Profile, 0, len(company.Profiles))
-		for _, profile := range company.Profiles {
-			profiles = append(profiles, entity.Profile{
-				Address:      profile.Alamat,
-				BAE:          profile.Bae,
-				Industry:     profile.Industri,
-				SubIndustry:  profile.SubIndustri,
-				Email:        profile.Email,
-				Fax:          profile.Fax,
-				MainBusiness: profile.KegiatanUsahaUtama,
-				StockCode:    profile.KodeEmiten,
-				StockName:    profile.NamaEmiten,
-				TIN:          profile.Npwp,
-				Sector:       profile.Sektor,
-				SubSector:    profile.SubSektor,
-				ListingDate:  profile.TanggalPencatatan,
-				Phone:        profile.Telepon,
-				Website:      profile.Website,
-				Status:       profile.Status,
-				Logo:         fmt.Sprintf("%s%s", s.cfg.GetService().IDXService.BaseURL, profile.Logo),
-			})
-		}
+	workers := s.cfg.GetService().IDXService.ProfileWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	batchSize := s.cfg.GetService().IDXService.ProfileBatchSize
+	if batchSize < 1 {
+		batchSize = 50
+	}
 
-		secretaries := make([]entity.Secretary, 0, len(company.Sekretaris))
-		for _, secretary := range company.Sekretaris {
-			secretaries = append(secretaries, entity.Secretary{
-				Name:         secretary.Nama,
-				PhoneNumber:  secretary.Telepon,
-				Website:      secretary.Website,
-				Email:        secretary.Email,
-				Fax:          secretary.Fax,
-				MobileNumber: secretary.Hp,
-			})
-		}
+	type result struct {
+		code  string
+		stock entity.Stock
+		err   error
+	}
 
-		directors := make([]entity.Director, 0, len(company.Direktur))
-		for _, director := range company.Direktur {
-			directors = append(directors, entity.Director{
-				Name:         director.Nama,
-				Position:     director.Jabatan,
-				IsAffiliated: director.Afiliasi,
-			})
-		}
+	// Stops the workers early when storing a batch fails.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-		commissioners := make([]entity.Commissioner, 0, len(company.Komisaris))
-		for _, commissioner := range company.Komisaris {
-			commissioners = append(commissioners, entity.Commissioner{
-				Name:          commissioner.Nama,
-				Position:      commissioner.Jabatan,
-				IsIndependent: commissioner.Independen,
-			})
-		}
+	queue := make(chan idx.StockListData)
+	results := make(chan result)
 
-		auditCommittees := make([]entity.AuditCommittee, 0, len(company.KomiteAudit))
-		for _, auditCommittee := range company.KomiteAudit {
-			auditCommittees = append(auditCommittees, entity.AuditCommittee{
-				Name:     auditCommittee.Nama,
-				Position: auditCommittee.Jabatan,
-			})
-		}
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for stock := range queue {
+				company, err := s.idxClient.GetCompanyProfile(ctx, stock.Code)
+				if err != nil {
+					results <- result{code: stock.Code, err: err}
+					continue
+				}
+				results <- result{code: stock.Code, stock: s.toStock(stock, company)}
+			}
+		}()
+	}
 
-		shareHolders := make([]entity.Shareholder, 0, len(company.PemegangSaham))
-		for _, shareHolder := range company.PemegangSaham {
-			shareHolders = append(shareHolders, entity.Shareholder{
-				Share:        shareHolder.Jumlah,
-				Category:     shareHolder.Kategori,
-				Name:         shareHolder.Nama,
-				IsController: shareHolder.Pengendali,
-				Percentage:   shareHolder.Persentase,
-			})
+	go func() {
+		defer close(queue)
+		for _, stock := range pending {
+			select {
+			case queue <- stock:
+			case <-ctx.Done():
+				return
+			}
 		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var (
+		upserted int
+		batch    []entity.Stock
+		failures []error
+	)
 
-		subsidiaries := make([]entity.Subsidiary, 0, len(company.AnakPerusahaan))
-		for _, subsidiary := range company.AnakPerusahaan {
-			subsidiaries = append(subsidiaries, entity.Subsidiary{
-				BusinessFields:  subsidiary.BidangUsaha,
-				TotalAsset:      subsidiary.JumlahAset,
-				Location:        subsidiary.Lokasi,
-				Currency:        subsidiary.MataUang,
-				Name:            subsidiary.Nama,
-				Percentage:      subsidiary.Persentase,
-				Units:           subsidiary.Satuan,
-				OperationStatus: subsidiary.StatusOperasi,
-				CommercialYear:  subsidiary.TahunKomersil,
-			})
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
 		}
+		if err := s.stockRepository.BulkUpsert(ctx, batch); err != nil {
+			return fmt.Errorf("bulk upsert failed: %w", err)
+		}
+		codes := make([]string, 0, len(batch))
+		for _, stock := range batch {
+			codes = append(codes, stock.StockCode)
+		}
+		if err := s.checkpointRepository.AddDone(ctx, updateStockCheckpoint, codes); err != nil {
+			return err
+		}
+		upserted += len(batch)
+		batch = batch[:0]
+		return nil
+	}
 
-		dividends := make([]entity.Dividend, 0, len(company.Dividen))
-		for _, dividend := range company.Dividen {
-			dividends = append(dividends, entity.Dividend{
-				Name:                         dividend.Nama,
-				Type:                         dividend.Jenis,
-				Year:                         dividend.TahunBuku,
-				TotalStockBonus:              dividend.TotalSahamBonus,
-				CashDividendPerShareCurrency: dividend.CashDividenTotalMU,
-				CashDividendPerShare:         dividend.CashDividenPerSaham,
-				CumDate:                      helper.StringToDate(dividend.TanggalCum),
-				ExDate:                       helper.StringToDate(dividend.TanggalExRegulerDanNegosiasi),
-				RecordDate:                   helper.StringToDate(dividend.TanggalDPS),
-				PaymentDate:                  helper.StringToDate(dividend.TanggalPembayaran),
-				Ratio1:                       dividend.Rasio1,
-				Ratio2:                       dividend.Rasio2,
-				CashDividendCurrency:         dividend.CashDividenTotalMU,
-				CashDividendTotal:            dividend.CashDividenTotal,
-			})
+	var flushErr error
+	for r := range results {
+		if r.err != nil {
+			failures = append(failures, fmt.Errorf("invalid stock company %s: %w", r.code, r.err))
+			continue
 		}
+		if flushErr != nil {
+			continue // drain the workers
+		}
+		batch = append(batch, r.stock)
+		if len(batch) >= batchSize {
+			if flushErr = flush(); flushErr != nil {
+				cancel()
+			}
+		}
+	}
+	if flushErr == nil {
+		flushErr = flush()
+	}
 
-		stocks = append(stocks, entity.Stock{
-			StockCode:       stock.Code,
-			StockName:       stock.Name,
-			Share:           stock.Share,
-			ListingDate:     helper.StringToDate(stock.ListingDate),
-			Board:           stock.Board,
-			Profiles:        profiles,
-			Secretaries:     secretaries,
-			Directors:       directors,
-			Commissioners:   commissioners,
-			AuditCommittees: auditCommittees,
-			Shareholders:    shareHolders,
-			Subsidiaries:    subsidiaries,
-			Dividends:       dividends,
+	if flushErr != nil {
+		return upserted, flushErr
+	}
+	if err := ctx.Err(); err != nil {
+		return upserted, err
+	}
+	if len(failures) > 0 {
+		return upserted, fmt.Errorf("%d of %d company profiles failed: %w", len(failures), len(pending), errors.Join(failures...))
+	}
+
+	// Every stock is stored, the next run starts from scratch.
+	if err := s.checkpointRepository.Delete(ctx, updateStockCheckpoint); err != nil {
+		return upserted, err
+	}
+
+	return upserted, nil
+}
+
+// toStock maps a listed stock and its company profile to a stock entity.
+func (s *stockUseCase) toStock(stock idx.StockListData, company *idx.CompanyProfileResponse) entity.Stock {
+	profiles := make([]entity.Profile, 0, len(company.Profiles))
+	for _, profile := range company.Profiles {
+		profiles = append(profiles, entity.Profile{
+			Address:      profile.Alamat,
+			BAE:          profile.Bae,
+			Industry:     profile.Industri,
+			SubIndustry:  profile.SubIndustri,
+			Email:        profile.Email,
+			Fax:          profile.Fax,
+			MainBusiness: profile.KegiatanUsahaUtama,
+			StockCode:    profile.KodeEmiten,
+			StockName:    profile.NamaEmiten,
+			TIN:          profile.Npwp,
+			Sector:       profile.Sektor,
+			SubSector:    profile.SubSektor,
+			ListingDate:  profile.TanggalPencatatan,
+			Phone:        profile.Telepon,
+			Website:      profile.Website,
+			Status:       profile.Status,
+			Logo:         fmt.Sprintf("%s%s", s.cfg.GetService().IDXService.BaseURL, profile.Logo),
+		})
+	}
+
+	secretaries := make([]entity.Secretary, 0, len(company.Sekretaris))
+	for _, secretary := range company.Sekretaris {
+		secretaries = append(secretaries, entity.Secretary{
+			Name:         secretary.Nama,
+			PhoneNumber:  secretary.Telepon,
+			Website:      secretary.Website,
+			Email:        secretary.Email,
+			Fax:          secretary.Fax,
+			MobileNumber: secretary.Hp,
+		})
+	}
+
+	directors := make([]entity.Director, 0, len(company.Direktur))
+	for _, director := range company.Direktur {
+		directors = append(directors, entity.Director{
+			Name:         director.Nama,
+			Position:     director.Jabatan,
+			IsAffiliated: director.Afiliasi,
+		})
+	}
+
+	commissioners := make([]entity.Commissioner, 0, len(company.Komisaris))
+	for _, commissioner := range company.Komisaris {
+		commissioners = append(commissioners, entity.Commissioner{
+			Name:          commissioner.Nama,
+			Position:      commissioner.Jabatan,
+			IsIndependent: commissioner.Independen,
+		})
+	}
+
+	auditCommittees := make([]entity.AuditCommittee, 0, len(company.KomiteAudit))
+	for _, auditCommittee := range company.KomiteAudit {
+		auditCommittees = append(auditCommittees, entity.AuditCommittee{
+			Name:     auditCommittee.Nama,
+			Position: auditCommittee.Jabatan,
+		})
+	}
+
+	shareHolders := make([]entity.Shareholder, 0, len(company.PemegangSaham))
+	for _, shareHolder := range company.PemegangSaham {
+		shareHolders = append(shareHolders, entity.Shareholder{
+			Share:        shareHolder.Jumlah,
+			Category:     shareHolder.Kategori,
+			Name:         shareHolder.Nama,
+			IsController: shareHolder.Pengendali,
+			Percentage:   shareHolder.Persentase,
 		})
 	}
 
-	if len(stocks) == 0 {
-		return nil // no stock data to update
+	subsidiaries := make([]entity.Subsidiary, 0, len(company.AnakPerusahaan))
+	for _, subsidiary := range company.AnakPerusahaan {
+		subsidiaries = append(subsidiaries, entity.Subsidiary{
+			BusinessFields:  subsidiary.BidangUsaha,
+			TotalAsset:      subsidiary.JumlahAset,
+			Location:        subsidiary.Lokasi,
+			Currency:        subsidiary.MataUang,
+			Name:            subsidiary.Nama,
+			Percentage:      subsidiary.Persentase,
+			Units:           subsidiary.Satuan,
+			OperationStatus: subsidiary.StatusOperasi,
+			CommercialYear:  subsidiary.TahunKomersil,
+		})
 	}
 
-	if err := s.stockRepository.BulkUpsert(ctx, stocks); err != nil {
-		return fmt.Errorf("bulk upsert failed: %w", err)
+	dividends := make([]entity.Dividend, 0, len(company.Dividen))
+	for _, dividend := range company.Dividen {
+		dividends = append(dividends, entity.Dividend{
+			Name:                         dividend.Nama,
+			Type:                         dividend.Jenis,
+			Year:                         dividend.TahunBuku,
+			TotalStockBonus:              dividend.TotalSahamBonus,
+			CashDividendPerShareCurrency: dividend.CashDividenTotalMU,
+			CashDividendPerShare:         dividend.CashDividenPerSaham,
+			CumDate:                      helper.StringToDate(dividend.TanggalCum),
+			ExDate:                       helper.StringToDate(dividend.TanggalExRegulerDanNegosiasi),
+			RecordDate:                   helper.StringToDate(dividend.TanggalDPS),
+			PaymentDate:                  helper.StringToDate(dividend.TanggalPembayaran),
+			Ratio1:                       dividend.Rasio1,
+			Ratio2:                       dividend.Rasio2,
+			CashDividendCurrency:         dividend.CashDividenTotalMU,
+			CashDividendTotal:            dividend.CashDividenTotal,
+		})
 	}
 
-	return nil
+	return entity.Stock{
+		StockCode:       stock.Code,
+		StockName:       stock.Name,
+		Share:           stock.Share,
+		ListingDate:     helper.StringToDate(stock.ListingDate),
+		Board:           stock.Board,
+		Profiles:        profiles,
+		Secretaries:     secretaries,
+		Directors:       directors,
+		Commissioners:   commissioners,
+		AuditCommittees: auditCommittees,
+		Shareholders:    shareHolders,
+		Subsidiaries:    subsidiaries,
+		Dividends:       dividends,
+	}
 }
 
 func (s *stockUseCase) ListStocks(ctx context.Context) ([]entity.Stock, error) {
@@ -195,3 +337,24 @@ func (s *stockUseCase) ListStocksWithPagination(ctx context.Context, limit, offs
 func (s *stockUseCase) SearchStocks(ctx context.Context, query string) ([]entity.Stock, error) {
 	return s.stockRepository.Search(ctx, query)
 }
+
+// listedStockCodes upper-cases and deduplicates stock codes. It returns the first
+// code that is not listed as unknown, with no codes.
+func listedStockCodes(ctx context.Context, stockRepository repository.StockRepository, codes []string) ([]string, string, error) {
+	result := make([]string, 0, len(codes))
+	for _, code := range codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" || slices.Contains(result, code) {
+			continue
+		}
+		stock, err := stockRepository.FindOne(ctx, code)
+		if err != nil {
+			return nil, "", err
+		}
+		if stock == nil {
+			return nil, code, nil
+		}
+		result = append(result, code)
+	}
+	return result, "", nil
+}
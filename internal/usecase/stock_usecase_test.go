@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"go-stock/internal/entity"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUpdateStock(t *testing.T) {
+	cfg := newTestConfig()
+	stocks := newMemoryStockRepository()
+	checkpoints := newMemoryCheckpointRepository()
+	uc := NewStockUsecase(cfg, newTestIdxClient(cfg), stocks, checkpoints)
+
+	// GOTO's profile is blocked by a Cloudflare challenge, the others are stored.
+	upserted, err := uc.UpdateStock(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "1 of 4 company profiles failed") || !strings.Contains(err.Error(), "GOTO") {
+		t.Fatalf("err = %v, want the failed GOTO profile reported", err)
+	}
+	if upserted != 3 {
+		t.Errorf("upserted = %d, want 3", upserted)
+	}
+
+	stock, _ := stocks.FindOne(context.Background(), "BBCA")
+	if stock == nil {
+		t.Fatal("BBCA is not stored")
+	}
+	if stock.Board != "Utama" || !stock.ListingDate.Equal(time.Date(2000, time.May, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("BBCA listing = %s %v", stock.Board, stock.ListingDate)
+	}
+	if len(stock.Profiles) != 1 || stock.Profiles[0].Logo != "https://www.idx.co.id/Portals/0/StaticData/ListedCompanies/LogoEmiten/BBCA.jpg" {
+		t.Errorf("BBCA profiles = %+v, want one profile with an absolute logo URL", stock.Profiles)
+	}
+	if len(stock.Dividends) != 2 || stock.Dividends[1].Ratio1 != 1 || stock.Dividends[1].Ratio2 != 5 {
+		t.Errorf("BBCA dividends = %+v, want the cash dividend and the 1:5 split", stock.Dividends)
+	}
+
+	// The stored stocks are checkpointed so the next run only retries GOTO.
+	checkpoint, _ := checkpoints.Find(context.Background(), updateStockCheckpoint)
+	if checkpoint == nil {
+		t.Fatal("checkpoint is deleted after a partial run")
+	}
+	slices.Sort(checkpoint.Done)
+	if !slices.Equal(checkpoint.Done, []string{"BBCA", "BBRI", "TLKM"}) {
+		t.Errorf("checkpoint done = %v, want BBCA BBRI TLKM", checkpoint.Done)
+	}
+}
+
+func TestUpdateStockResumesFromCheckpoint(t *testing.T) {
+	cfg := newTestConfig()
+	stocks := newMemoryStockRepository()
+	checkpoints := newMemoryCheckpointRepository()
+	checkpoints.AddDone(context.Background(), updateStockCheckpoint, []string{"BBCA", "GOTO"})
+	uc := NewStockUsecase(cfg, newTestIdxClient(cfg), stocks, checkpoints)
+
+	upserted, err := uc.UpdateStock(context.Background())
+	if err != nil {
+		t.Fatalf("UpdateStock: %v", err)
+	}
+	if upserted != 2 {
+		t.Errorf("upserted = %d, want the 2 stocks missing from the checkpoint", upserted)
+	}
+	if stock, _ := stocks.FindOne(context.Background(), "BBCA"); stock != nil {
+		t.Error("BBCA is fetched again although it is checkpointed")
+	}
+
+	// A complete run starts the next one from scratch.
+	if checkpoint, _ := checkpoints.Find(context.Background(), updateStockCheckpoint); checkpoint != nil {
+		t.Errorf("checkpoint = %+v, want it deleted", checkpoint)
+	}
+}
+
+func TestUpdateStockIgnoresStaleCheckpoint(t *testing.T) {
+	cfg := newTestConfig()
+	stocks := newMemoryStockRepository()
+	checkpoints := newMemoryCheckpointRepository()
+	checkpoints.checkpoints[updateStockCheckpoint] = entity.Checkpoint{
+		Name:      updateStockCheckpoint,
+		StartedAt: time.Now().Add(-2 * updateStockCheckpointTTL),
+		Done:      []string{"BBCA", "BBRI", "GOTO", "TLKM"},
+	}
+	uc := NewStockUsecase(cfg, newTestIdxClient(cfg), stocks, checkpoints)
+
+	upserted, _ := uc.UpdateStock(context.Background())
+	if upserted != 3 {
+		t.Errorf("upserted = %d, want every available profile fetched again", upserted)
+	}
+}
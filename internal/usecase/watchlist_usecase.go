@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-stock/internal/entity"
+	"go-stock/internal/repository"
+	"strings"
+	"time"
+)
+
+var ErrInvalidWatchlist = errors.New("invalid watchlist")
+
+type WatchlistUseCase interface {
+	CreateWatchlist(ctx context.Context, watchlist entity.Watchlist) (*entity.Watchlist, error)
+	UpdateWatchlist(ctx context.Context, watchlist entity.Watchlist) (*entity.Watchlist, error)
+	DeleteWatchlist(ctx context.Context, userID, id string) (bool, error)
+	FindWatchlist(ctx context.Context, userID, id string) (*entity.Watchlist, error)
+	FindWatchlists(ctx context.Context, userID string) ([]entity.Watchlist, error)
+}
+
+type watchlistUseCase struct {
+	watchlistRepository    repository.WatchlistRepository
+	stockSummaryRepository repository.StockSummaryRepository
+	stockRepository        repository.StockRepository
+}
+
+func NewWatchlistUseCase(watchlistRepository repository.WatchlistRepository, stockSummaryRepository repository.StockSummaryRepository, stockRepository repository.StockRepository) WatchlistUseCase {
+	return &watchlistUseCase{
+		watchlistRepository:    watchlistRepository,
+		stockSummaryRepository: stockSummaryRepository,
+		stockRepository:        stockRepository,
+	}
+}
+
+func (u *watchlistUseCase) CreateWatchlist(ctx context.Context, watchlist entity.Watchlist) (*entity.Watchlist, error) {
+	if err := u.normalize(ctx, &watchlist); err != nil {
+		return nil, err
+	}
+
+	watchlist.ID = newID()
+	watchlist.CreatedAt = time.Now()
+	watchlist.UpdatedAt = watchlist.CreatedAt
+	if err := u.watchlistRepository.Upsert(ctx, watchlist); err != nil {
+		return nil, err
+	}
+	return u.enrich(ctx, watchlist)
+}
+
+// UpdateWatchlist replaces the name and stocks of a watchlist of the user. It
+// returns nil when the user has no such watchlist.
+func (u *watchlistUseCase) UpdateWatchlist(ctx context.Context, watchlist entity.Watchlist) (*entity.Watchlist, error) {
+	existing, err := u.watchlistRepository.FindOne(ctx, watchlist.UserID, watchlist.ID)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+	if err := u.normalize(ctx, &watchlist); err != nil {
+		return nil, err
+	}
+
+	watchlist.CreatedAt = existing.CreatedAt
+	watchlist.UpdatedAt = time.Now()
+	if err := u.watchlistRepository.Upsert(ctx, watchlist); err != nil {
+		return nil, err
+	}
+	return u.enrich(ctx, watchlist)
+}
+
+// DeleteWatchlist deletes a watchlist of the user and reports whether it existed.
+func (u *watchlistUseCase) DeleteWatchlist(ctx context.Context, userID, id string) (bool, error) {
+	watchlist, err := u.watchlistRepository.FindOne(ctx, userID, id)
+	if err != nil || watchlist == nil {
+		return false, err
+	}
+	if err := u.watchlistRepository.Delete(ctx, userID, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (u *watchlistUseCase) FindWatchlist(ctx context.Context, userID, id string) (*entity.Watchlist, error) {
+	watchlist, err := u.watchlistRepository.FindOne(ctx, userID, id)
+	if err != nil || watchlist == nil {
+		return nil, err
+	}
+	return u.enrich(ctx, *watchlist)
+}
+
+func (u *watchlistUseCase) FindWatchlists(ctx context.Context, userID string) ([]entity.Watchlist, error) {
+	watchlists, err := u.watchlistRepository.Find(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]entity.Watchlist, 0, len(watchlists))
+	for _, watchlist := range watchlists {
+		enriched, err := u.enrich(ctx, watchlist)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *enriched)
+	}
+	return result, nil
+}
+
+// enrich adds the latest stored summary of each stock to a watchlist.
+func (u *watchlistUseCase) enrich(ctx context.Context, watchlist entity.Watchlist) (*entity.Watchlist, error) {
+	watchlist.Stocks = make([]entity.WatchlistStock, 0, len(watchlist.StockCodes))
+	for _, code := range watchlist.StockCodes {
+		summary, err := u.stockSummaryRepository.FindLatest(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		watchlist.Stocks = append(watchlist.Stocks, entity.WatchlistStock{StockCode: code, Summary: summary})
+	}
+	return &watchlist, nil
+}
+
+// normalize trims the name of a watchlist and checks that every stock is listed.
+func (u *watchlistUseCase) normalize(ctx context.Context, watchlist *entity.Watchlist) error {
+	watchlist.Name = strings.TrimSpace(watchlist.Name)
+	if watchlist.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidWatchlist)
+	}
+
+	codes, unknown, err := listedStockCodes(ctx, u.stockRepository, watchlist.StockCodes)
+	if err != nil {
+		return err
+	}
+	if unknown != "" {
+		return fmt.Errorf("%w: unknown stock %s", ErrInvalidWatchlist, unknown)
+	}
+	watchlist.StockCodes = codes
+	return nil
+}
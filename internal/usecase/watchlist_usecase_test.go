@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"go-stock/internal/entity"
+	"testing"
+)
+
+func TestWatchlist(t *testing.T) {
+	cfg := newTestConfig()
+	stocks := newMemoryStockRepository()
+	stocks.BulkUpsert(context.Background(), []entity.Stock{{StockCode: "BBCA"}, {StockCode: "TLKM"}, {StockCode: "ABCD"}})
+	summaries := newMemoryStockSummaryRepository()
+	if _, err := NewStockSummaryUseCase(newTestIdxClient(cfg), NewCalendarUseCase(cfg, nil, summaries), summaries, stocks).UpdateSummaries(context.Background(), "20250102"); err != nil {
+		t.Fatalf("UpdateSummaries: %v", err)
+	}
+	uc := NewWatchlistUseCase(newMemoryWatchlistRepository(), summaries, stocks)
+
+	if _, err := uc.CreateWatchlist(context.Background(), entity.Watchlist{UserID: "alice", Name: "Bad", StockCodes: []string{"ZZZZ"}}); !errors.Is(err, ErrInvalidWatchlist) {
+		t.Errorf("CreateWatchlist with an unknown stock = %v, want ErrInvalidWatchlist", err)
+	}
+	watchlist, err := uc.CreateWatchlist(context.Background(), entity.Watchlist{UserID: "alice", Name: "Mine", StockCodes: []string{"tlkm", "BBCA", "ABCD"}})
+	if err != nil {
+		t.Fatalf("CreateWatchlist: %v", err)
+	}
+	if len(watchlist.Stocks) != 3 || watchlist.Stocks[0].StockCode != "TLKM" || watchlist.Stocks[0].Summary == nil {
+		t.Fatalf("stocks = %+v, want TLKM first with its summary", watchlist.Stocks)
+	}
+	if watchlist.Stocks[2].Summary != nil {
+		t.Errorf("ABCD has no stored summary, got %+v", watchlist.Stocks[2].Summary)
+	}
+
+	// Watchlists are private to their user.
+	if other, _ := uc.FindWatchlist(context.Background(), "bob", watchlist.ID); other != nil {
+		t.Errorf("bob found alice's watchlist")
+	}
+	if deleted, _ := uc.DeleteWatchlist(context.Background(), "bob", watchlist.ID); deleted {
+		t.Errorf("bob deleted alice's watchlist")
+	}
+
+	watchlist.StockCodes = []string{"BBCA"}
+	if updated, err := uc.UpdateWatchlist(context.Background(), *watchlist); err != nil || len(updated.Stocks) != 1 {
+		t.Fatalf("UpdateWatchlist = %+v, %v", updated, err)
+	}
+	watchlists, err := uc.FindWatchlists(context.Background(), "alice")
+	if err != nil || len(watchlists) != 1 || watchlists[0].Stocks[0].Summary.StockCode != "BBCA" {
+		t.Errorf("FindWatchlists = %+v, %v", watchlists, err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"embed"
 	"go-stock/internal/app"
 	"go-stock/internal/config"
+	"go-stock/internal/delivery/cli"
 	"go-stock/internal/delivery/cron"
 	"go-stock/internal/delivery/http"
 	"log"
@@ -26,6 +27,9 @@ var view embed.FS
 // @license.url http://www.apache.org/licenses/LICENSE-2.0.html
 // @host localhost:3000
 // @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 func main() {
 	// Load the configuration
 	cfg, err := config.NewConfig("config.yaml")
@@ -39,6 +43,17 @@ func main() {
 		log.Fatalf("Failed to initialize app: %v", err)
 	}
 
+	// Run a one-off command instead of the server, e.g. "go-stock backfill -start 2025-01-01"
+	if len(os.Args) > 1 {
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		if err := cli.Run(ctx, bootstrap, os.Args[1:]); err != nil {
+			log.Fatalf("Command %q failed: %v", os.Args[1], err)
+		}
+		return
+	}
+
 	// Set up context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()